@@ -2,18 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/culbec/CRYPTO-sss/src/backend/internal"
 	"github.com/culbec/CRYPTO-sss/src/backend/internal/api/auth"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/api/dkg"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/api/sharing"
 	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
 	"github.com/culbec/CRYPTO-sss/src/backend/pkg"
 	"github.com/culbec/CRYPTO-sss/src/backend/pkg/mongo"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
 	"github.com/gin-gonic/gin"
 )
 
+// osMinEntropy: min-entropy in bits per byte of the operating system generator, which delivers full entropy.
+const osMinEntropy float64 = 8
+
 func loggerMiddleware(logger *slog.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		reqCtx := logging.WithContext(ctx.Request.Context(), logger)
@@ -42,24 +49,24 @@ func corsMiddleware() gin.HandlerFunc {
 	}
 }
 
-func prepareAuthHandlers(router *gin.Engine, auth *auth.AuthHandler) []*gin.RouterGroup {
+func prepareAuthHandlers(router *gin.Engine, authHandler *auth.AuthHandler) []*gin.RouterGroup {
 	router.POST("/api/auth/login", func(ctx *gin.Context) {
 		ctx.Header("Content-Type", "application/json")
-		auth.Login(ctx)
+		authHandler.Login(ctx)
 	})
 	router.POST("/api/auth/register", func(ctx *gin.Context) {
 		ctx.Header("Content-Type", "application/json")
-		auth.Register(ctx)
+		authHandler.Register(ctx)
 	})
 	router.POST("/api/auth/logout", func(ctx *gin.Context) {
 		ctx.Header("Content-Type", "application/json")
-		auth.Logout(ctx)
+		authHandler.Logout(ctx)
 	})
 
 	router.POST("/api/auth/validate", func(ctx *gin.Context) {
 		logger := logging.FromContext(ctx.Request.Context())
 
-		_, err := auth.ValidateToken(ctx)
+		_, err := authHandler.ValidateToken(ctx)
 		if err != nil {
 			msg := "error validating token: " + err.Error()
 			logger.Error(msg)
@@ -70,8 +77,36 @@ func prepareAuthHandlers(router *gin.Engine, auth *auth.AuthHandler) []*gin.Rout
 		ctx.JSON(http.StatusOK, gin.H{"message": "Valid token"})
 	})
 
-	// TODO: protected routes
-	return []*gin.RouterGroup{}
+	// protected routes
+	sssGroup := router.Group("/api/sss", auth.RequireAuth(authHandler))
+
+	return []*gin.RouterGroup{sssGroup}
+}
+
+func prepareSharingHandlers(group *gin.RouterGroup, sharing *sharing.SharingHandler) {
+	group.POST("/combine", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		sharing.Combine(ctx)
+	})
+	group.POST("/redistribute/relay", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		sharing.RelaySubShares(ctx)
+	})
+	group.POST("/redistribute/fetch", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		sharing.FetchSubShares(ctx)
+	})
+}
+
+func prepareDKGHandlers(group *gin.RouterGroup, dkg *dkg.DKGHandler) {
+	group.POST("/dkg/simulate", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		dkg.Simulate(ctx)
+	})
+	group.POST("/dkg/replay", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		dkg.Replay(ctx)
+	})
 }
 
 func prepareHandlers(router *gin.Engine, ctx context.Context, config *pkg.Config, client *mongo.Client) {
@@ -90,9 +125,13 @@ func prepareHandlers(router *gin.Engine, ctx context.Context, config *pkg.Config
 
 	// API handlers
 	authHandler := auth.NewAuthHandler(client, []byte(secretKey))
+	sharingHandler := sharing.NewSharingHandler()
+	dkgHandler := dkg.NewDKGHandler()
 
-	// TODO: protected routes
-	_ = prepareAuthHandlers(router, authHandler)
+	protectedGroups := prepareAuthHandlers(router, authHandler)
+	sssGroup := protectedGroups[0]
+	prepareSharingHandlers(sssGroup, sharingHandler)
+	prepareDKGHandlers(sssGroup, dkgHandler)
 }
 
 func main() {
@@ -111,6 +150,15 @@ func main() {
 	}
 	logger.Info("Config loaded!")
 
+	logger.Info("Enabling entropy health tests...")
+	healthChecked, err := entropy.NewHealthChecked(rand.Reader, osMinEntropy)
+	if err != nil {
+		logger.Error("Error preparing the entropy health tests, using the unchecked OS generator", "error", err)
+	} else {
+		entropy.SetDefault(healthChecked)
+		logger.Info("Entropy health tests enabled!")
+	}
+
 	serverHost, serverPort := config.ServerHost, config.ServerPort
 	if serverHost == "" {
 		logger.Warn("Server host not set, using default", "default", internal.SERVER_HOST)
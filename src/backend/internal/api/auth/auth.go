@@ -142,6 +142,7 @@ func (a *AuthHandler) Login(ctx *gin.Context) error {
 	logger := logging.FromContext(ctx.Request.Context())
 
 	var req types.LoginRequest
+	defer func() { _ = req.Password.Close() }()
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		msg := "invalid login request: " + err.Error()
@@ -177,8 +178,8 @@ func (a *AuthHandler) Login(ctx *gin.Context) error {
 		saltBytes = []byte(user[0].Salt)
 	}
 
-	err := a.hasher.ComparePasswords(
-		[]byte(req.Password),
+	err := a.hasher.ComparePasswordBuffer(
+		req.Password,
 		saltBytes,
 		[]byte(user[0].Password),
 	)
@@ -252,6 +253,7 @@ func (a *AuthHandler) Register(ctx *gin.Context) error {
 	logger := logging.FromContext(ctx.Request.Context())
 
 	var req types.RegisterRequest
+	defer func() { _ = req.Password.Close() }()
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		msg := "invalid register request: " + err.Error()
@@ -260,8 +262,8 @@ func (a *AuthHandler) Register(ctx *gin.Context) error {
 		return errors.New(msg)
 	}
 
-	hashSalt, err := a.hasher.GenerateHash(
-		[]byte(req.Password),
+	hashSalt, err := a.hasher.GenerateHashBuffer(
+		req.Password,
 		[]byte{},
 	)
 	if err != nil {
@@ -0,0 +1,92 @@
+package dkg
+
+import (
+	"errors"
+	"net/http"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	security_dkg "github.com/culbec/CRYPTO-sss/src/backend/pkg/security/dkg"
+	"github.com/gin-gonic/gin"
+)
+
+// DKGHandler: handler for the distributed key generation demo endpoints.
+// The server plays every participant, so unlike a real deployment it could add up the key; only the public
+// transcript leaves it, and the key shares are discarded.
+type DKGHandler struct{}
+
+func NewDKGHandler() *DKGHandler {
+	return &DKGHandler{}
+}
+
+// Simulate: runs a key generation with the requested participants and deviations, returning its transcript.
+func (d *DKGHandler) Simulate(ctx *gin.Context) error {
+	logger := logging.FromContext(ctx.Request.Context())
+
+	var req types.DKGRequest
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MAX_DKG_BODY_BYTES)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		msg := "invalid dkg request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	result, err := security_dkg.Run(req.Participants, req.Threshold, req.Behaviors)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, security_dkg.ErrNoQualifiedDealers) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		msg := "error running dkg: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	logger.Info("dkg simulated", "participants", req.Participants, "threshold", req.Threshold, "qualified", result.Transcript.Qualified)
+	ctx.JSON(http.StatusOK, types.DKGResponse{Transcript: result.Transcript})
+	return nil
+}
+
+// Replay: checks a transcript, returning the public state after each round for a step-by-step replay.
+func (d *DKGHandler) Replay(ctx *gin.Context) error {
+	logger := logging.FromContext(ctx.Request.Context())
+
+	var req types.DKGReplayRequest
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MAX_DKG_BODY_BYTES)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		msg := "invalid dkg replay request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	checkpoints, err := security_dkg.Replay(req.Transcript)
+	if err != nil {
+		msg := "error replaying dkg transcript: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	logger.Info("dkg transcript replayed", "rounds", len(checkpoints))
+	ctx.JSON(http.StatusOK, types.DKGReplayResponse{Checkpoints: checkpoints})
+	return nil
+}
@@ -0,0 +1,208 @@
+package sharing
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+	"github.com/gin-gonic/gin"
+)
+
+// SharingHandler: handler for the secret sharing endpoints.
+// Redistribution runs on the holders' devices: old holders reshare locally and the server only relays the
+// sub-shares, sealed to their new holder, so it never sees enough to rebuild the secret. Combine is the exception,
+// as it receives the shares themselves and must be trusted with the secret.
+type SharingHandler struct {
+	mu        sync.Mutex
+	mailboxes map[mailbox][]sss.SealedSubShare // sealed sub-shares waiting for their new holder
+	stored    int                              // number of sealed sub-shares across the mailboxes
+}
+
+// mailbox: identifies the sealed sub-shares of one redistribution addressed to one new holder.
+type mailbox struct {
+	redistribution string
+	to             byte
+}
+
+func NewSharingHandler() *SharingHandler {
+	return &SharingHandler{mailboxes: make(map[mailbox][]sss.SealedSubShare)}
+}
+
+// validateSealed: checks the envelopes of one old holder's sealed sub-shares: at most one per new holder, non-zero
+// X values, an X25519 ephemeral key, and a ciphertext no longer than a sealed MAX_SECRET_LEN sub-share.
+// Returns an error describing the first invalid sub-share.
+func validateSealed(subShares []sss.SealedSubShare) error {
+	if len(subShares) > sss.MaxShares {
+		return errors.New("at most " + strconv.Itoa(sss.MaxShares) + " sub-shares can be relayed at once")
+	}
+	for _, subShare := range subShares {
+		if subShare.From == 0 || subShare.To == 0 {
+			return errors.New("sub-share X values cannot be 0")
+		}
+		if len(subShare.Ephemeral) != 32 {
+			return errors.New("sub-share ephemeral key must be 32 bytes")
+		}
+		if len(subShare.Ciphertext) > constants.MAX_SECRET_LEN+sss.SealOverhead {
+			return errors.New("sub-shares cannot be longer than " + strconv.Itoa(constants.MAX_SECRET_LEN) + " bytes")
+		}
+	}
+	return nil
+}
+
+// RelaySubShares: stores the sealed sub-shares an old holder produced until their new holders fetch them.
+func (s *SharingHandler) RelaySubShares(ctx *gin.Context) error {
+	logger := logging.FromContext(ctx.Request.Context())
+
+	var req types.RelaySubSharesRequest
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MAX_RELAY_BODY_BYTES)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		msg := "invalid relay request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	if err := validateSealed(req.SubShares); err != nil {
+		msg := "invalid relay request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	s.mu.Lock()
+	if s.stored+len(req.SubShares) > constants.MAX_RELAYED_SUB_SHARES {
+		s.mu.Unlock()
+		msg := "relay is full, sub-shares must be fetched before more are sent"
+		logger.Error(msg)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+	for _, subShare := range req.SubShares {
+		key := mailbox{redistribution: req.Redistribution, to: subShare.To}
+		s.mailboxes[key] = append(s.mailboxes[key], subShare)
+	}
+	s.stored += len(req.SubShares)
+	s.mu.Unlock()
+
+	logger.Info("sealed sub-shares relayed", "redistribution", req.Redistribution, "sub_shares", len(req.SubShares))
+	ctx.JSON(http.StatusOK, gin.H{"relayed": len(req.SubShares)})
+	return nil
+}
+
+// FetchSubShares: hands a new holder the sealed sub-shares addressed to them and forgets them.
+func (s *SharingHandler) FetchSubShares(ctx *gin.Context) error {
+	logger := logging.FromContext(ctx.Request.Context())
+
+	var req types.FetchSubSharesRequest
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MAX_RELAY_BODY_BYTES)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		msg := "invalid fetch request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	key := mailbox{redistribution: req.Redistribution, to: req.To}
+	s.mu.Lock()
+	subShares := s.mailboxes[key]
+	delete(s.mailboxes, key)
+	s.stored -= len(subShares)
+	s.mu.Unlock()
+
+	if subShares == nil {
+		subShares = []sss.SealedSubShare{}
+	}
+
+	logger.Info("sealed sub-shares fetched", "redistribution", req.Redistribution, "to", req.To, "sub_shares", len(subShares))
+	ctx.JSON(http.StatusOK, types.SealedSubSharesResponse{SubShares: subShares})
+	return nil
+}
+
+// Combine: reconstructs a secret from possibly dishonest shares, reporting the custodians whose shares were rejected.
+// The reconstruction wipes its intermediate values, and the handler wipes the decoded shares and the secret, but
+// copies remain out of its reach until the garbage collector reuses them: the request body read by the JSON
+// binding, and the base64-encoded response built by the JSON encoder and gin's response writer.
+// The decoding cost grows with the cube of the share count, so the body size and the share length are capped.
+func (s *SharingHandler) Combine(ctx *gin.Context) error {
+	logger := logging.FromContext(ctx.Request.Context())
+
+	var req types.CombineRequest
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MAX_COMBINE_BODY_BYTES)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		msg := "invalid combine request: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	defer func() {
+		for _, share := range req.Shares {
+			share.Wipe()
+		}
+	}()
+
+	for _, share := range req.Shares {
+		if len(share.Y) > constants.MAX_SECRET_LEN {
+			msg := "invalid combine request: shares cannot be longer than " + strconv.Itoa(constants.MAX_SECRET_LEN) + " bytes"
+			logger.Error(msg)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return errors.New(msg)
+		}
+	}
+
+	result, err := sss.CombineRobust(req.Shares, req.Threshold)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, sss.ErrInconsistentShares) || errors.Is(err, sss.ErrTooManyBadShares) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		msg := "error combining shares: " + err.Error()
+		logger.Error(msg)
+		ctx.JSON(status, gin.H{"error": msg})
+		return errors.New(msg)
+	}
+
+	rejected := make([]int, len(result.Rejected))
+	for i, x := range result.Rejected {
+		rejected[i] = int(x)
+	}
+	if len(rejected) > 0 {
+		logger.Warn("rejected inconsistent shares", "rejected", rejected)
+	}
+
+	ctx.JSON(http.StatusOK, types.CombineResponse{
+		Secret:   result.Secret,
+		Rejected: rejected,
+	})
+	// this clears the reconstructed secret only; the encoded response holds its own copy
+	securemem.Wipe(result.Secret)
+	return nil
+}
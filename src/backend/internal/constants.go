@@ -24,6 +24,17 @@ const ARGON2ID_DEFAULT_SALT_LEN uint32 = 16
 
 const DEFAULT_JWT_EXPIRY time.Duration = 60 * time.Minute
 
+// robust combine decodes every secret byte in O(m^3) for m shares, so both the body and the share length are capped
+const MAX_SECRET_LEN int = 64
+const MAX_COMBINE_BODY_BYTES int64 = 16 * 1024
+
+// the relay keeps sealed sub-shares in memory until fetched, so requests and the stored total are capped
+const MAX_RELAY_BODY_BYTES int64 = 64 * 1024
+const MAX_RELAYED_SUB_SHARES int = 1 << 16
+
+// a replayed transcript is checked message by message; the largest runs produce well under 1 MiB
+const MAX_DKG_BODY_BYTES int64 = 2 << 20
+
 // ////////////////////////////
 // CONFIG CONSTANTS
 // ////////////////////////////
@@ -2,6 +2,10 @@ package types
 
 import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/dkg"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
 )
 
 type ObjectId = primitive.ObjectID
@@ -17,15 +21,17 @@ type User struct {
 }
 
 // LoginRequest struct
+// The password is decoded straight into locked memory, and the handler must close it.
 type LoginRequest struct {
-	Username string `json:"username" bson:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string                  `json:"username" bson:"username" binding:"required"`
+	Password *securemem.SecretBuffer `json:"password" binding:"required"`
 }
 
 // RegisterRequest struct
+// The password is decoded straight into locked memory, and the handler must close it.
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string                  `json:"username" binding:"required"`
+	Password *securemem.SecretBuffer `json:"password" binding:"required"`
 }
 
 // AuthResponse struct
@@ -33,3 +39,55 @@ type AuthResponse struct {
 	UserID string `json:"user_id"`
 	Token  string `json:"token"`
 }
+
+// RelaySubSharesRequest struct
+// The sub-shares are sealed on the old holder's device, so the server relays them without being able to read them.
+type RelaySubSharesRequest struct {
+	Redistribution string               `json:"redistribution" binding:"required"` // identifier agreed on by the holders
+	SubShares      []sss.SealedSubShare `json:"sub_shares" binding:"required"`
+}
+
+// FetchSubSharesRequest struct
+type FetchSubSharesRequest struct {
+	Redistribution string `json:"redistribution" binding:"required"`
+	To             byte   `json:"to" binding:"required"` // X value of the new holder
+}
+
+// SealedSubSharesResponse struct
+type SealedSubSharesResponse struct {
+	SubShares []sss.SealedSubShare `json:"sub_shares"`
+}
+
+// CombineRequest struct
+type CombineRequest struct {
+	Shares    []sss.Share `json:"shares" binding:"required"`
+	Threshold int         `json:"threshold" binding:"required"`
+}
+
+// CombineResponse struct
+type CombineResponse struct {
+	Secret   []byte `json:"secret"`
+	Rejected []int  `json:"rejected"` // X values of the custodians whose shares were found inconsistent
+}
+
+// DKGRequest struct
+type DKGRequest struct {
+	Participants int                  `json:"participants" binding:"required"`
+	Threshold    int                  `json:"threshold" binding:"required"`
+	Behaviors    map[int]dkg.Behavior `json:"behaviors"` // deviations from the protocol, by participant index
+}
+
+// DKGResponse struct
+type DKGResponse struct {
+	Transcript *dkg.Transcript `json:"transcript"`
+}
+
+// DKGReplayRequest struct
+type DKGReplayRequest struct {
+	Transcript *dkg.Transcript `json:"transcript" binding:"required"`
+}
+
+// DKGReplayResponse struct
+type DKGReplayResponse struct {
+	Checkpoints []dkg.Checkpoint `json:"checkpoints"`
+}
@@ -38,10 +38,12 @@ type DbCollectionType int
 
 const (
 	UserCollection DbCollectionType = iota
+	ShareCollection
 )
 
 var DbCollections = map[DbCollectionType]string{
-	UserCollection: "users",
+	UserCollection:  "users",
+	ShareCollection: "shares",
 }
 
 // QueryCollection: queries a named collection in the database based on some conditions.
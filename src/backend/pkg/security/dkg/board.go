@@ -0,0 +1,366 @@
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+
+	"filippo.io/edwards25519"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+// ErrNoQualifiedDealers: returned when every dealer is disqualified, leaving no key to generate.
+var ErrNoQualifiedDealers = errors.New("every dealer was disqualified")
+
+// board: the public view of a run, built from the broadcast messages alone. Every participant following the
+// protocol derives the same view, so a run keeps a single one and Replay rebuilds it from a transcript.
+type board struct {
+	n, k int
+
+	pedersen     map[int]*vss.Commitments  // Pedersen commitments of each dealer with a well-formed dealing
+	complaints   map[int]map[int]bool      // complainers against each dealer
+	answers      map[int]map[int]vss.Share // verified answers of each dealer, by complainer
+	qualified    map[int]bool              // dealers whose secrets make up the key
+	disqualified map[int]Round             // round at the end of which each dealer was disqualified
+
+	feldman   map[int]*vss.Commitments // Feldman commitments of each qualified dealer
+	exposed   map[int]bool             // qualified dealers whose secret is reconstructed in the open
+	publicKey *edwards25519.Point      // sum of secret*G over the qualified dealers, once known
+}
+
+// Checkpoint: struct to hold the public state of a run at the end of a round.
+type Checkpoint struct {
+	Round        Round  `json:"round"`
+	Qualified    []int  `json:"qualified"`
+	Disqualified []int  `json:"disqualified,omitempty"` // dealers disqualified in this round
+	Exposed      []int  `json:"exposed,omitempty"`      // qualified dealers whose secret is reconstructed in the open
+	PublicKey    []byte `json:"public_key,omitempty"`   // set once the key is known
+}
+
+// newBoard: creates an empty public view for n participants with threshold k.
+// Returns the board.
+func newBoard(n, k int) *board {
+	return &board{
+		n:            n,
+		k:            k,
+		pedersen:     make(map[int]*vss.Commitments),
+		complaints:   make(map[int]map[int]bool),
+		answers:      make(map[int]map[int]vss.Share),
+		qualified:    make(map[int]bool),
+		disqualified: make(map[int]Round),
+		feldman:      make(map[int]*vss.Commitments),
+		exposed:      make(map[int]bool),
+	}
+}
+
+// qualifiedList: returns the qualified dealers in order of index.
+func (b *board) qualifiedList() []int {
+	return slices.Sorted(maps.Keys(b.qualified))
+}
+
+// disqualify: removes a dealer from the qualified set.
+func (b *board) disqualify(dealer int, round Round) {
+	delete(b.qualified, dealer)
+	b.disqualified[dealer] = round
+}
+
+// validCommitments: checks that commitments have the given mode and one well-formed point per coefficient.
+// Returns whether they do.
+func (b *board) validCommitments(commitments *vss.Commitments, mode string) bool {
+	return commitments != nil && commitments.Mode == mode && len(commitments.Points) == b.k && commitments.Validate() == nil
+}
+
+// checkMessage: checks the envelope of a message of the given round.
+// Returns an error if the message is out of place or names unknown participants.
+func (b *board) checkMessage(message Message, round Round) error {
+	if message.Round != round {
+		return errors.New("message of the " + message.Round.String() + " round found in the " + round.String() + " round")
+	}
+	if message.From < 1 || message.From > b.n {
+		return errors.New("message from unknown participant " + strconv.Itoa(message.From))
+	}
+	if message.To < 0 || message.To > b.n || message.To == message.From {
+		return errors.New("message from participant " + strconv.Itoa(message.From) + " to invalid recipient " + strconv.Itoa(message.To))
+	}
+	if message.To != 0 && round != RoundDeal {
+		return errors.New("private message outside the deal round from participant " + strconv.Itoa(message.From))
+	}
+	if message.Accused < 0 || message.Accused > b.n || (message.Accused != 0 && message.Accused == message.From) {
+		return errors.New("message from participant " + strconv.Itoa(message.From) + " accuses invalid participant " + strconv.Itoa(message.Accused))
+	}
+	return nil
+}
+
+// maxMessages: bounds the number of messages an honest or deviating run sends in a round.
+// Returns n^2 for the deal round, where every dealer broadcasts once and sends n-1 private shares, n for the
+// extract round, and n(n-1) for the others, which hold at most one message per participant about each other one.
+func (b *board) maxMessages(round Round) int {
+	switch round {
+	case RoundDeal:
+		return b.n * b.n
+	case RoundExtract:
+		return b.n
+	default:
+		return b.n * (b.n - 1)
+	}
+}
+
+// apply: updates the view with the broadcast messages of a step, private messages carrying nothing public.
+// Returns an error if the step holds more messages than the round can produce, a message is malformed or the
+// protocol cannot continue.
+func (b *board) apply(step Step) error {
+	if len(step.Messages) > b.maxMessages(step.Round) {
+		return errors.New("round holds " + strconv.Itoa(len(step.Messages)) + " messages, at most " +
+			strconv.Itoa(b.maxMessages(step.Round)) + " are possible")
+	}
+	for _, message := range step.Messages {
+		if err := b.checkMessage(message, step.Round); err != nil {
+			return err
+		}
+	}
+
+	switch step.Round {
+	case RoundDeal:
+		b.applyDeal(step.Messages)
+	case RoundComplain:
+		b.applyComplain(step.Messages)
+	case RoundAnswer:
+		return b.applyAnswer(step.Messages)
+	case RoundExtract:
+		b.applyExtract(step.Messages)
+	case RoundExtractComplain:
+		b.applyExtractComplain(step.Messages)
+	case RoundReconstruct:
+		return b.applyReconstruct(step.Messages)
+	default:
+		return errors.New("unknown round " + strconv.Itoa(int(step.Round)))
+	}
+	return nil
+}
+
+// applyDeal: qualifies the dealers that broadcast exactly one well-formed set of Pedersen commitments.
+func (b *board) applyDeal(messages []Message) {
+	broadcasts := make(map[int]int)
+	for _, message := range messages {
+		if message.To != 0 || message.Commitments == nil {
+			continue
+		}
+		broadcasts[message.From]++
+		if b.validCommitments(message.Commitments, vss.PedersenMode) {
+			b.pedersen[message.From] = message.Commitments
+		}
+	}
+
+	for dealer := 1; dealer <= b.n; dealer++ {
+		if b.pedersen[dealer] != nil && broadcasts[dealer] == 1 {
+			b.qualified[dealer] = true
+		} else {
+			b.disqualified[dealer] = RoundDeal
+		}
+	}
+}
+
+// applyComplain: records the complaints against qualified dealers.
+func (b *board) applyComplain(messages []Message) {
+	for _, message := range messages {
+		if message.Accused == 0 || !b.qualified[message.Accused] {
+			continue
+		}
+		if b.complaints[message.Accused] == nil {
+			b.complaints[message.Accused] = make(map[int]bool)
+		}
+		b.complaints[message.Accused][message.From] = true
+	}
+}
+
+// applyAnswer: records the answers that match the dealer's commitments, then disqualifies the dealers with more
+// complaints than the degree of their polynomial or with a complaint left without a valid answer.
+// Returns ErrNoQualifiedDealers if no dealer is left.
+func (b *board) applyAnswer(messages []Message) error {
+	for _, message := range messages {
+		dealer, share := message.From, message.Share
+		if share == nil || !b.qualified[dealer] || !b.complaints[dealer][share.Index] {
+			continue
+		}
+		if vss.VerifyShare(*share, b.pedersen[dealer]) != nil {
+			continue
+		}
+		if b.answers[dealer] == nil {
+			b.answers[dealer] = make(map[int]vss.Share)
+		}
+		b.answers[dealer][share.Index] = *share
+	}
+
+	for _, dealer := range b.qualifiedList() {
+		complainers := b.complaints[dealer]
+		if len(complainers) >= b.k {
+			b.disqualify(dealer, RoundAnswer)
+			continue
+		}
+		for complainer := range complainers {
+			if _, ok := b.answers[dealer][complainer]; !ok {
+				b.disqualify(dealer, RoundAnswer)
+				break
+			}
+		}
+	}
+
+	if len(b.qualified) == 0 {
+		return ErrNoQualifiedDealers
+	}
+	return nil
+}
+
+// applyExtract: records the Feldman commitments of the qualified dealers, exposing those that broadcast none,
+// several, or malformed ones.
+func (b *board) applyExtract(messages []Message) {
+	broadcasts := make(map[int]int)
+	for _, message := range messages {
+		if message.Commitments == nil || !b.qualified[message.From] {
+			continue
+		}
+		broadcasts[message.From]++
+		if b.validCommitments(message.Commitments, vss.FeldmanMode) {
+			b.feldman[message.From] = message.Commitments
+		}
+	}
+
+	for dealer := range b.qualified {
+		if b.feldman[dealer] == nil || broadcasts[dealer] != 1 {
+			delete(b.feldman, dealer)
+			b.exposed[dealer] = true
+		}
+	}
+}
+
+// applyExtractComplain: exposes the dealers against which a participant revealed a share that matches the
+// Pedersen commitments but not the Feldman ones, which proves the Feldman commitments wrong.
+func (b *board) applyExtractComplain(messages []Message) {
+	for _, message := range messages {
+		dealer, share := message.Accused, message.Share
+		if !b.qualified[dealer] || b.exposed[dealer] || share == nil || share.Index != message.From {
+			continue
+		}
+		if vss.VerifyShare(*share, b.pedersen[dealer]) == nil && vss.VerifyShare(*share, b.feldman[dealer]) != nil {
+			b.exposed[dealer] = true
+		}
+	}
+}
+
+// applyReconstruct: reconstructs the secret of every exposed dealer from the revealed shares that match its
+// Pedersen commitments, then computes the public key.
+// Returns an error if an exposed dealer has fewer valid revealed shares than the threshold.
+func (b *board) applyReconstruct(messages []Message) error {
+	revealed := make(map[int]map[int]vss.Share)
+	for _, message := range messages {
+		dealer, share := message.Accused, message.Share
+		if !b.exposed[dealer] || share == nil || share.Index != message.From {
+			continue
+		}
+		if vss.VerifyShare(*share, b.pedersen[dealer]) != nil {
+			continue
+		}
+		if revealed[dealer] == nil {
+			revealed[dealer] = make(map[int]vss.Share)
+		}
+		revealed[dealer][share.Index] = *share
+	}
+
+	b.publicKey = edwards25519.NewIdentityPoint()
+	for _, dealer := range b.qualifiedList() {
+		part, err := b.part(dealer, revealed[dealer])
+		if err != nil {
+			return err
+		}
+		b.publicKey.Add(b.publicKey, part)
+	}
+	return nil
+}
+
+// part: computes secret*G for a qualified dealer, from its Feldman commitments or, if it was exposed, from its
+// revealed shares.
+// Returns the point and an error if the secret cannot be reconstructed.
+func (b *board) part(dealer int, revealed map[int]vss.Share) (*edwards25519.Point, error) {
+	if !b.exposed[dealer] {
+		// the commitments were validated when recorded
+		return edwards25519.NewIdentityPoint().SetBytes(b.feldman[dealer].Points[0])
+	}
+
+	if len(revealed) < b.k {
+		return nil, errors.New("only " + strconv.Itoa(len(revealed)) + " valid shares revealed for dealer " +
+			strconv.Itoa(dealer) + ", " + strconv.Itoa(b.k) + " are required")
+	}
+
+	shares := make([]vss.Share, 0, b.k)
+	for _, index := range slices.Sorted(maps.Keys(revealed))[:b.k] {
+		shares = append(shares, revealed[index])
+	}
+	encoded, err := vss.Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := edwards25519.NewScalar().SetCanonicalBytes(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return edwards25519.NewIdentityPoint().ScalarBaseMult(secret), nil
+}
+
+// checkpoint: summarizes the view at the end of a round.
+// Returns the checkpoint.
+func (b *board) checkpoint(round Round) Checkpoint {
+	checkpoint := Checkpoint{Round: round, Qualified: b.qualifiedList()}
+	for _, dealer := range slices.Sorted(maps.Keys(b.disqualified)) {
+		if b.disqualified[dealer] == round {
+			checkpoint.Disqualified = append(checkpoint.Disqualified, dealer)
+		}
+	}
+	checkpoint.Exposed = slices.Sorted(maps.Keys(b.exposed))
+	if b.publicKey != nil {
+		checkpoint.PublicKey = b.publicKey.Bytes()
+	}
+	return checkpoint
+}
+
+// Replay: rebuilds the public state of a run from its transcript, round by round, so that anybody can check the
+// outcome without the private shares.
+// Returns one checkpoint per round and an error if the transcript is malformed or its outcome does not follow
+// from its messages.
+func Replay(transcript *Transcript) ([]Checkpoint, error) {
+	if transcript == nil {
+		return nil, errors.New("transcript cannot be empty")
+	}
+	if err := validateParams(transcript.Participants, transcript.Threshold, nil); err != nil {
+		return nil, err
+	}
+	if transcript.Group != vss.GroupName {
+		return nil, errors.New("unsupported group: " + transcript.Group)
+	}
+	if len(transcript.Steps) != int(RoundReconstruct) {
+		return nil, errors.New("transcript must hold " + strconv.Itoa(int(RoundReconstruct)) + " rounds, got " +
+			strconv.Itoa(len(transcript.Steps)))
+	}
+
+	b := newBoard(transcript.Participants, transcript.Threshold)
+	checkpoints := make([]Checkpoint, 0, len(transcript.Steps))
+	for i, step := range transcript.Steps {
+		if step.Round != Round(i+1) {
+			return nil, errors.New("expected the " + Round(i+1).String() + " round, got the " + step.Round.String() + " round")
+		}
+		if err := b.apply(step); err != nil {
+			return nil, errors.New(step.Round.String() + " round: " + err.Error())
+		}
+		checkpoints = append(checkpoints, b.checkpoint(step.Round))
+	}
+
+	if !slices.Equal(b.qualifiedList(), transcript.Qualified) {
+		return nil, errors.New("transcript qualified set does not follow from its messages")
+	}
+	if !bytes.Equal(b.publicKey.Bytes(), transcript.PublicKey) {
+		return nil, errors.New("transcript public key does not follow from its messages")
+	}
+	return checkpoints, nil
+}
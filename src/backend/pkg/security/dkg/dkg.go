@@ -0,0 +1,204 @@
+// Package dkg simulates the Pedersen distributed key generation of Gennaro, Jarecki, Krawczyk and Rabin (GJKR)
+// over edwards25519, in which n participants end up with Shamir shares of a key that no participant ever held.
+//
+// Every participant deals a random secret with Pedersen verifiable secret sharing, and the key is the sum of
+// the secrets of the dealers that survive the complaint phase. Participants then publish Feldman commitments to
+// their secrets, from which the public key follows; a dealer caught publishing commitments that do not match its
+// dealing has its secret reconstructed in the open by the others, so it cannot bias the key.
+//
+// The participants run in process and exchange messages round by round. The public messages, with private shares
+// redacted, form a Transcript that Replay checks and steps through without any private input.
+package dkg
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+// MaxParticipants: maximum number of participants of a simulation, which exchanges O(n^2) messages.
+const MaxParticipants int = 64
+
+// Round: a round of the protocol.
+type Round int
+
+const (
+	RoundDeal            Round = iota + 1 // dealers broadcast Pedersen commitments and send shares privately
+	RoundComplain                         // recipients of bad shares complain against the dealer
+	RoundAnswer                           // dealers answer complaints by broadcasting the disputed shares
+	RoundExtract                          // qualified dealers broadcast Feldman commitments to their polynomial
+	RoundExtractComplain                  // recipients expose Feldman commitments that do not match their share
+	RoundReconstruct                      // participants reveal their shares of exposed dealers
+)
+
+// String: returns the name of the round.
+func (r Round) String() string {
+	switch r {
+	case RoundDeal:
+		return "deal"
+	case RoundComplain:
+		return "complain"
+	case RoundAnswer:
+		return "answer"
+	case RoundExtract:
+		return "extract"
+	case RoundExtractComplain:
+		return "extract-complain"
+	case RoundReconstruct:
+		return "reconstruct"
+	default:
+		return "round " + strconv.Itoa(int(r))
+	}
+}
+
+// Behavior: struct to describe how a participant deviates from the protocol. The zero value is an honest participant.
+type Behavior struct {
+	CorruptShares   []int `json:"corrupt_shares,omitempty"`   // participants sent a wrong share in the deal round
+	WithholdAnswers bool  `json:"withhold_answers,omitempty"` // ignores complaints instead of answering them
+	FalseComplaints []int `json:"false_complaints,omitempty"` // dealers complained against despite a valid share
+	CorruptFeldman  bool  `json:"corrupt_feldman,omitempty"`  // publishes Feldman commitments to a different secret
+}
+
+// Message: struct to hold a message of the protocol.
+type Message struct {
+	Round       Round            `json:"round"`
+	From        int              `json:"from"`
+	To          int              `json:"to"`                    // recipient, 0 for a broadcast
+	Accused     int              `json:"accused,omitempty"`     // dealer a complaint or revealed share is about
+	Commitments *vss.Commitments `json:"commitments,omitempty"` // commitments broadcast by a dealer
+	Share       *vss.Share       `json:"share,omitempty"`       // share dealt, answered or revealed; redacted from private messages in transcripts
+}
+
+// Step: struct to hold the messages of one round, in the order they were sent.
+type Step struct {
+	Round    Round     `json:"round"`
+	Messages []Message `json:"messages"`
+}
+
+// Transcript: struct to hold the public view of a run.
+type Transcript struct {
+	Participants int    `json:"participants"`
+	Threshold    int    `json:"threshold"`
+	Group        string `json:"group"`
+	Steps        []Step `json:"steps"`
+	Qualified    []int  `json:"qualified"`  // dealers whose secrets make up the key
+	PublicKey    []byte `json:"public_key"` // encoded point key*G
+}
+
+// Result: struct to hold the outcome of a run.
+type Result struct {
+	Transcript *Transcript
+	KeyShares  []vss.Share // share of the key of each participant, in order of index, without blinding values
+}
+
+// validateParams: checks the number of participants, the threshold and the behaviors of a run.
+// Returns an error if they are invalid.
+func validateParams(n, k int, behaviors map[int]Behavior) error {
+	if k < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return errors.New("number of participants cannot be less than the threshold")
+	}
+	if n > MaxParticipants {
+		return errors.New("number of participants cannot exceed " + strconv.Itoa(MaxParticipants))
+	}
+
+	for index, behavior := range behaviors {
+		if index < 1 || index > n {
+			return errors.New("behavior given for unknown participant " + strconv.Itoa(index))
+		}
+		for _, other := range slices.Concat(behavior.CorruptShares, behavior.FalseComplaints) {
+			if other < 1 || other > n || other == index {
+				return errors.New("participant " + strconv.Itoa(index) + " targets invalid participant " + strconv.Itoa(other))
+			}
+		}
+	}
+	return nil
+}
+
+// Run: runs the key generation among n participants with threshold k, participants not listed in behaviors
+// following the protocol.
+// Returns the result and an error if the parameters are invalid, the random generation fails, or too few
+// participants follow the protocol for it to complete.
+func Run(n, k int, behaviors map[int]Behavior) (*Result, error) {
+	if err := validateParams(n, k, behaviors); err != nil {
+		return nil, err
+	}
+
+	participants := make([]*participant, n)
+	for i := range participants {
+		participants[i] = newParticipant(i+1, behaviors[i+1])
+	}
+
+	transcript := &Transcript{Participants: n, Threshold: k, Group: vss.GroupName}
+	board := newBoard(n, k)
+
+	// post: delivers the private messages of a round, then records it and updates the public view
+	post := func(round Round, messages []Message) error {
+		step := Step{Round: round, Messages: make([]Message, len(messages))}
+		for i, message := range messages {
+			if message.To != 0 {
+				participants[message.To-1].receive(message)
+				message.Share = nil
+			}
+			step.Messages[i] = message
+		}
+		transcript.Steps = append(transcript.Steps, step)
+		return board.apply(step)
+	}
+
+	var messages []Message
+	for _, p := range participants {
+		dealt, err := p.deal(n, k)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, dealt...)
+	}
+	if err := post(RoundDeal, messages); err != nil {
+		return nil, err
+	}
+
+	rounds := []struct {
+		round Round
+		send  func(p *participant) []Message
+	}{
+		{RoundComplain, func(p *participant) []Message { return p.complain(board) }},
+		{RoundAnswer, func(p *participant) []Message { return p.answer(board) }},
+		{RoundExtract, func(p *participant) []Message { return p.extract(board) }},
+		{RoundExtractComplain, func(p *participant) []Message { return p.extractComplain(board) }},
+		{RoundReconstruct, func(p *participant) []Message { return p.reveal(board) }},
+	}
+	for _, r := range rounds {
+		messages = nil
+		for _, p := range participants {
+			messages = append(messages, r.send(p)...)
+		}
+		if err := post(r.round, messages); err != nil {
+			return nil, err
+		}
+
+		if r.round == RoundAnswer {
+			for _, p := range participants {
+				p.acceptAnswers(board)
+			}
+		}
+	}
+
+	transcript.Qualified = board.qualifiedList()
+	transcript.PublicKey = board.publicKey.Bytes()
+
+	keyShares := make([]vss.Share, n)
+	for i, p := range participants {
+		keyShare, err := p.keyShare(board)
+		if err != nil {
+			return nil, err
+		}
+		keyShares[i] = keyShare
+	}
+
+	return &Result{Transcript: transcript, KeyShares: keyShares}, nil
+}
@@ -0,0 +1,197 @@
+package dkg
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+
+	"filippo.io/edwards25519"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+// participant: a party of the key generation, acting on the public board and on the shares dealt to it.
+type participant struct {
+	index    int
+	behavior Behavior
+	dealing  *vss.Dealing
+	received map[int]vss.Share // share dealt to this participant by each dealer, its own included
+}
+
+// newParticipant: creates a participant with the given index and behavior.
+// Returns the participant.
+func newParticipant(index int, behavior Behavior) *participant {
+	return &participant{
+		index:    index,
+		behavior: behavior,
+		received: make(map[int]vss.Share),
+	}
+}
+
+// corruptShare: adds one to the value of a share, so that it no longer matches the commitments.
+// Returns the corrupted share.
+func corruptShare(share vss.Share) vss.Share {
+	value, err := edwards25519.NewScalar().SetCanonicalBytes(share.Value)
+	if err != nil {
+		panic(err) // unreachable: shares of a dealing are canonical
+	}
+	one := make([]byte, vss.ScalarLen)
+	one[0] = 1
+	increment, _ := edwards25519.NewScalar().SetCanonicalBytes(one)
+
+	share.Value = value.Add(value, increment).Bytes()
+	return share
+}
+
+// corruptCommitments: adds the base point to the first commitment, committing to a different secret.
+// Returns the corrupted commitments.
+func corruptCommitments(commitments *vss.Commitments) *vss.Commitments {
+	first, err := edwards25519.NewIdentityPoint().SetBytes(commitments.Points[0])
+	if err != nil {
+		panic(err) // unreachable: commitments of a dealing are valid points
+	}
+	commitments.Points[0] = first.Add(first, edwards25519.NewGeneratorPoint()).Bytes()
+	return commitments
+}
+
+// receive: stores a share sent privately to the participant.
+func (p *participant) receive(message Message) {
+	if message.Share != nil {
+		p.received[message.From] = *message.Share
+	}
+}
+
+// deal: draws a random secret, commits to a Pedersen dealing of it and shares it out.
+// Returns the broadcast commitments followed by one private share per other participant, and an error if the
+// random generation fails.
+func (p *participant) deal(n, k int) ([]Message, error) {
+	secret, err := vss.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	defer securemem.Wipe(secret)
+
+	p.dealing, err = vss.NewPedersenDealing(secret, k)
+	if err != nil {
+		return nil, err
+	}
+	p.received[p.index] = p.dealing.Share(p.index)
+
+	messages := []Message{{Round: RoundDeal, From: p.index, Commitments: p.dealing.PedersenCommitments()}}
+	for j := 1; j <= n; j++ {
+		if j == p.index {
+			continue
+		}
+		share := p.dealing.Share(j)
+		if slices.Contains(p.behavior.CorruptShares, j) {
+			share = corruptShare(share)
+		}
+		messages = append(messages, Message{Round: RoundDeal, From: p.index, To: j, Share: &share})
+	}
+	return messages, nil
+}
+
+// complain: checks the share of every qualified dealer against its Pedersen commitments.
+// Returns a complaint against each dealer whose share is missing or does not match.
+func (p *participant) complain(b *board) []Message {
+	var messages []Message
+	for _, dealer := range b.qualifiedList() {
+		if dealer == p.index {
+			continue
+		}
+		share, ok := p.received[dealer]
+		if !ok || vss.VerifyShare(share, b.pedersen[dealer]) != nil || slices.Contains(p.behavior.FalseComplaints, dealer) {
+			messages = append(messages, Message{Round: RoundComplain, From: p.index, Accused: dealer})
+		}
+	}
+	return messages
+}
+
+// answer: broadcasts the share of every participant that complained against this dealer.
+// Returns the answers.
+func (p *participant) answer(b *board) []Message {
+	if p.behavior.WithholdAnswers || !b.qualified[p.index] {
+		return nil
+	}
+
+	var messages []Message
+	for _, complainer := range slices.Sorted(maps.Keys(b.complaints[p.index])) {
+		share := p.dealing.Share(complainer)
+		messages = append(messages, Message{Round: RoundAnswer, From: p.index, Share: &share})
+	}
+	return messages
+}
+
+// acceptAnswers: replaces the disputed shares of this participant by the answers of the qualified dealers.
+func (p *participant) acceptAnswers(b *board) {
+	for dealer, answers := range b.answers {
+		if share, ok := answers[p.index]; ok && b.qualified[dealer] {
+			p.received[dealer] = share
+		}
+	}
+}
+
+// extract: broadcasts Feldman commitments to the dealing if this dealer is qualified.
+// Returns the commitments.
+func (p *participant) extract(b *board) []Message {
+	if !b.qualified[p.index] {
+		return nil
+	}
+
+	commitments := p.dealing.FeldmanCommitments()
+	if p.behavior.CorruptFeldman {
+		commitments = corruptCommitments(commitments)
+	}
+	return []Message{{Round: RoundExtract, From: p.index, Commitments: commitments}}
+}
+
+// extractComplain: checks the share of every qualified dealer against its Feldman commitments.
+// Returns a complaint, revealing the share, against each dealer whose share does not match.
+func (p *participant) extractComplain(b *board) []Message {
+	var messages []Message
+	for _, dealer := range b.qualifiedList() {
+		if dealer == p.index || b.exposed[dealer] {
+			continue
+		}
+		share, ok := p.received[dealer]
+		if ok && vss.VerifyShare(share, b.feldman[dealer]) != nil {
+			messages = append(messages, Message{Round: RoundExtractComplain, From: p.index, Accused: dealer, Share: &share})
+		}
+	}
+	return messages
+}
+
+// reveal: broadcasts the share of every exposed dealer, so that its secret can be reconstructed.
+// Returns the revealed shares.
+func (p *participant) reveal(b *board) []Message {
+	var messages []Message
+	for _, dealer := range slices.Sorted(maps.Keys(b.exposed)) {
+		if dealer == p.index {
+			continue
+		}
+		if share, ok := p.received[dealer]; ok {
+			messages = append(messages, Message{Round: RoundReconstruct, From: p.index, Accused: dealer, Share: &share})
+		}
+	}
+	return messages
+}
+
+// keyShare: adds up the shares dealt by the qualified dealers.
+// Returns the share of the key and an error if a share is missing.
+func (p *participant) keyShare(b *board) (vss.Share, error) {
+	sum := edwards25519.NewScalar()
+	for _, dealer := range b.qualifiedList() {
+		share, ok := p.received[dealer]
+		if !ok {
+			return vss.Share{}, errors.New("participant " + strconv.Itoa(p.index) + " has no share from dealer " + strconv.Itoa(dealer))
+		}
+		value, err := edwards25519.NewScalar().SetCanonicalBytes(share.Value)
+		if err != nil {
+			return vss.Share{}, err
+		}
+		sum.Add(sum, value)
+	}
+	return vss.Share{Index: p.index, Value: sum.Bytes()}, nil
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// The batch APIs spread the secrets of a batch over the workers, each secret being processed on a single
+// goroutine, and share the precomputation (X powers or Lagrange coefficients) between the secrets.
+// Random coefficients are drawn before the fan-out, one read per secret in batch order, so that a seeded engine
+// produces the same shares as splitting the secrets one after the other, whatever the scheduling.
+
+// batchError: prefixes an error with the position of the secret it concerns.
+// Returns the wrapped error.
+func batchError(i int, err error) error {
+	return errors.New("batch item " + strconv.Itoa(i) + ": " + err.Error())
+}
+
+// SplitBatch: splits many secrets with the same parameters, computing the powers of the X values once.
+// Returns the shares of every secret and an error if the parameters or a secret are invalid.
+func (e *Engine) SplitBatch(secrets [][]byte, n, k int) ([][]sss.Share, error) {
+	if err := validateSplit(n, k); err != nil {
+		return nil, err
+	}
+	for i, secret := range secrets {
+		if len(secret) == 0 {
+			return nil, batchError(i, errors.New("secret cannot be empty"))
+		}
+	}
+
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = byte(i + 1)
+	}
+	pows := powers(xs, k)
+
+	random, err := e.randomBatch(secrets, k)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]sss.Share, len(secrets))
+	e.parallel(len(secrets), 1, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out[i] = e.splitAt(secrets[i], random[i], xs, pows, k, math.MaxInt)
+		}
+	})
+
+	return out, nil
+}
+
+// randomBatch: draws the random coefficients of each secret in order, before any work is spread over goroutines.
+// Returns the coefficients of every secret and an error if the random generation fails.
+func (e *Engine) randomBatch(secrets [][]byte, k int) ([][]byte, error) {
+	random := make([][]byte, len(secrets))
+	for i, secret := range secrets {
+		r, err := e.random(len(secret), k)
+		if err != nil {
+			for _, drawn := range random[:i] {
+				securemem.Wipe(drawn)
+			}
+			return nil, batchError(i, err)
+		}
+		random[i] = r
+	}
+	return random, nil
+}
+
+// CombineBatch: reconstructs many secrets, computing the Lagrange coefficients once per distinct index set.
+// Returns the secrets and an error if the shares of a secret are malformed.
+func (e *Engine) CombineBatch(batch [][]sss.Share) ([][]byte, error) {
+	xsets := make([][]byte, len(batch))
+	for i, shares := range batch {
+		xs, err := validateShares(shares)
+		if err != nil {
+			return nil, batchError(i, err)
+		}
+		xsets[i] = xs
+	}
+
+	out := make([][]byte, len(batch))
+	e.parallel(len(batch), 1, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out[i] = e.combineWith(batch[i], e.coefficients(xsets[i]), math.MaxInt)
+		}
+	})
+
+	return out, nil
+}
+
+// RefreshBatch: re-randomizes the stored shares of many secrets in one pass, moving each set to the next epoch.
+// Each set receives the shares of a fresh random polynomial with a zero constant term, which has the same
+// distribution as the sum of the holders' contributions in sss.Refresh, so the secrets and thresholds are unchanged
+// while old and new shares can no longer be combined.
+// Returns the refreshed share sets and an error if a set is malformed.
+func (e *Engine) RefreshBatch(batch [][]sss.Share, k int) ([][]sss.Share, error) {
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+
+	xsets := make([][]byte, len(batch))
+	for i, shares := range batch {
+		xs, err := validateShares(shares)
+		if err != nil {
+			return nil, batchError(i, err)
+		}
+		// a higher degree than the holders can interpolate would leave the refreshed shares unable to reconstruct
+		if len(shares) < k {
+			return nil, batchError(i, errors.New("number of shares cannot be less than the threshold"))
+		}
+		if shares[0].Epoch == math.MaxUint32 {
+			return nil, batchError(i, errors.New("share epoch counter is exhausted"))
+		}
+		xsets[i] = xs
+	}
+
+	// most batches hold share sets at the same X values, so their powers are computed once
+	pows := make(map[string][][]byte)
+	for _, xs := range xsets {
+		if _, ok := pows[string(xs)]; !ok {
+			pows[string(xs)] = powers(xs, k)
+		}
+	}
+
+	zeros := make([][]byte, len(batch))
+	for i, shares := range batch {
+		zeros[i] = make([]byte, len(shares[0].Y))
+	}
+	random, err := e.randomBatch(zeros, k)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]sss.Share, len(batch))
+	e.parallel(len(batch), 1, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			shares := batch[i]
+			delta := e.splitAt(zeros[i], random[i], xsets[i], pows[string(xsets[i])], k, math.MaxInt)
+			for j, share := range shares {
+				mulAdd(delta[j].Y, share.Y, 1)
+				delta[j].Epoch = share.Epoch + 1
+			}
+			out[i] = delta
+		}
+	})
+
+	return out, nil
+}
@@ -0,0 +1,282 @@
+// Package engine provides a throughput-oriented implementation of Shamir's scheme over GF(2^8),
+// producing and consuming the same shares as package sss.
+//
+// Scaling a payload by a constant is bitsliced over eight bytes per machine word, which keeps it constant-time;
+// Lagrange coefficients are cached per index set; large payloads are cut into ranges evaluated by separate
+// goroutines; and the batch APIs share one precomputation across many secrets.
+package engine
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// DefaultParallelThreshold: payload size in bytes from which a single split or combine is spread over goroutines.
+const DefaultParallelThreshold int = 64 << 10
+
+// maxCachedSets: number of index sets whose Lagrange coefficients are kept before the cache is reset.
+const maxCachedSets int = 4096
+
+// Engine: struct to hold the worker configuration and the Lagrange coefficient cache.
+// An Engine is safe for concurrent use.
+type Engine struct {
+	workers           int          // number of goroutines used for one operation
+	parallelThreshold atomic.Int64 // payload size from which a single operation is parallelized
+	entropy           io.Reader    // source of the polynomial coefficients
+
+	mu    sync.RWMutex
+	cache map[string][]byte // index set, in order, to its Lagrange coefficients at 0
+}
+
+// New: creates an engine using the given number of goroutines, or GOMAXPROCS if workers is not positive,
+// drawing randomness from the entropy source set by the options.
+// Returns the engine.
+func New(workers int, opts ...entropy.Option) *Engine {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	e := &Engine{
+		workers: workers,
+		entropy: entropy.NewReader(opts...),
+		cache:   make(map[string][]byte),
+	}
+	e.parallelThreshold.Store(int64(DefaultParallelThreshold))
+	return e
+}
+
+// SetParallelThreshold: changes the payload size from which a single operation is spread over goroutines.
+// It may be called while other operations run; they use either the old or the new value.
+func (e *Engine) SetParallelThreshold(bytes int) {
+	e.parallelThreshold.Store(int64(max(bytes, 1)))
+}
+
+// parallel: runs fn over [0, n) cut into contiguous ranges, one per worker, once n reaches grain.
+// Smaller inputs run on the calling goroutine.
+func (e *Engine) parallel(n, grain int, fn func(lo, hi int)) {
+	workers := min(e.workers, n/max(grain, 1))
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	step := (n + workers - 1) / workers
+	for lo := 0; lo < n; lo += step {
+		hi := min(lo+step, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(lo, hi)
+		}()
+	}
+	wg.Wait()
+}
+
+// validateXs: checks that the X values are non-zero and distinct.
+// Returns an error naming the first bad X value.
+func validateXs(xs []byte) error {
+	var seen [256]bool
+	for _, x := range xs {
+		if x == 0 {
+			return errors.New("share X value cannot be 0")
+		}
+		if seen[x] {
+			return errors.New("duplicate share X value: " + strconv.Itoa(int(x)))
+		}
+		seen[x] = true
+	}
+	return nil
+}
+
+// validateShares: checks that the shares can be interpolated together, with the same rules as sss.Combine.
+// Returns the X values of the shares and an error if the shares are malformed.
+func validateShares(shares []sss.Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		if len(share.Y) == 0 {
+			return nil, errors.New("share Y value cannot be empty")
+		}
+		if len(share.Y) != len(shares[0].Y) {
+			return nil, errors.New("shares must all have the same length")
+		}
+		if share.Epoch != shares[0].Epoch {
+			return nil, errors.New("shares belong to different epochs")
+		}
+		xs[i] = share.X
+	}
+
+	return xs, validateXs(xs)
+}
+
+// lagrange: computes the Lagrange basis polynomials of the distinct non-zero points xs at 0.
+// Returns one coefficient per point.
+func lagrange(xs []byte) []byte {
+	coeffs := make([]byte, len(xs))
+	for i := range xs {
+		var num, den byte = 1, 1
+		for j := range xs {
+			if i != j {
+				num = gfMul(num, xs[j])
+				den = gfMul(den, xs[i]^xs[j])
+			}
+		}
+		coeffs[i] = gfMul(num, gfInv(den))
+	}
+	return coeffs
+}
+
+// coefficients: looks up or computes the Lagrange coefficients at 0 for an index set.
+// The returned slice is shared with the cache and must not be modified.
+// Returns the coefficients.
+func (e *Engine) coefficients(xs []byte) []byte {
+	key := string(xs)
+
+	e.mu.RLock()
+	coeffs, ok := e.cache[key]
+	e.mu.RUnlock()
+	if ok {
+		return coeffs
+	}
+
+	coeffs = lagrange(xs)
+
+	e.mu.Lock()
+	if len(e.cache) >= maxCachedSets {
+		clear(e.cache)
+	}
+	e.cache[key] = coeffs
+	e.mu.Unlock()
+
+	return coeffs
+}
+
+// Coefficients: returns the Lagrange coefficients at 0 for an index set, computing them once per set and order.
+// Combining shares at these X values is then the sum of coeffs[i] * Y_i.
+// Returns a copy of the coefficients and an error if the X values are zero or repeated.
+func (e *Engine) Coefficients(xs []byte) ([]byte, error) {
+	if err := validateXs(xs); err != nil {
+		return nil, err
+	}
+	return append([]byte{}, e.coefficients(xs)...), nil
+}
+
+// powers: computes x^j for every X value and j in [0, k).
+// Returns the powers, indexed by share then degree.
+func powers(xs []byte, k int) [][]byte {
+	out := make([][]byte, len(xs))
+	for i, x := range xs {
+		out[i] = make([]byte, k)
+		out[i][0] = 1
+		for j := 1; j < k; j++ {
+			out[i][j] = gfMul(out[i][j-1], x)
+		}
+	}
+	return out
+}
+
+// validateSplit: checks the parameters of a split.
+// Returns an error if they are invalid.
+func validateSplit(n, k int) error {
+	if k < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return errors.New("number of shares cannot be less than the threshold")
+	}
+	if n > sss.MaxShares {
+		return errors.New("number of shares cannot exceed 255")
+	}
+	return nil
+}
+
+// random: draws the k-1 random coefficients of every byte of a secret of the given length from the engine's source.
+// Returns the coefficients and an error if the random generation fails.
+func (e *Engine) random(length, k int) ([]byte, error) {
+	random := make([]byte, (k-1)*length)
+	if err := entropy.ReadFull(e.entropy, random); err != nil {
+		return nil, err
+	}
+	return random, nil
+}
+
+// splitAt: shares a secret at the given X values, whose powers up to k-1 are precomputed, with coefficients
+// drawn by random. The coefficients of degree j for all secret bytes are contiguous, so each share accumulates
+// k-1 payload-sized rows, split into ranges across goroutines for large payloads. The coefficients are wiped.
+// Returns one share per X value.
+func (e *Engine) splitAt(secret, random []byte, xs []byte, pows [][]byte, k int, grain int) []sss.Share {
+	defer securemem.Wipe(random)
+	length := len(secret)
+
+	shares := make([]sss.Share, len(xs))
+	for i, x := range xs {
+		shares[i] = sss.Share{X: x, Y: make([]byte, length)}
+	}
+
+	e.parallel(length, grain, func(lo, hi int) {
+		for i := range shares {
+			y := shares[i].Y[lo:hi]
+			copy(y, secret[lo:hi])
+			for j := 1; j < k; j++ {
+				mulAdd(y, random[(j-1)*length+lo:(j-1)*length+hi], pows[i][j])
+			}
+		}
+	})
+
+	return shares
+}
+
+// Split: splits a secret into n shares, any k of which can reconstruct it, exactly as sss.Split does.
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func (e *Engine) Split(secret []byte, n, k int) ([]sss.Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if err := validateSplit(n, k); err != nil {
+		return nil, err
+	}
+
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = byte(i + 1)
+	}
+
+	random, err := e.random(len(secret), k)
+	if err != nil {
+		return nil, err
+	}
+	return e.splitAt(secret, random, xs, powers(xs, k), k, int(e.parallelThreshold.Load())), nil
+}
+
+// combineWith: interpolates shares at 0 with precomputed Lagrange coefficients.
+// Returns the secret.
+func (e *Engine) combineWith(shares []sss.Share, coeffs []byte, grain int) []byte {
+	secret := make([]byte, len(shares[0].Y))
+	e.parallel(len(secret), grain, func(lo, hi int) {
+		for i, share := range shares {
+			mulAdd(secret[lo:hi], share.Y[lo:hi], coeffs[i])
+		}
+	})
+	return secret
+}
+
+// Combine: reconstructs a secret from its shares, exactly as sss.Combine does.
+// Returns the secret and an error if the shares are malformed.
+func (e *Engine) Combine(shares []sss.Share) ([]byte, error) {
+	xs, err := validateShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	return e.combineWith(shares, e.coefficients(xs), int(e.parallelThreshold.Load())), nil
+}
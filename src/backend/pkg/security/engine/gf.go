@@ -0,0 +1,64 @@
+package engine
+
+import "encoding/binary"
+
+// Field arithmetic is constant-time, as in package sss: a product table would be indexed by share bytes and leak
+// them through the cache, so payloads are instead multiplied eight bytes at a time, bitsliced across a machine word.
+
+// fieldReduction: the low byte of the reduction polynomial x^8 + x^4 + x^3 + x + 1, the same field as package sss.
+const fieldReduction byte = 0x1b
+
+const (
+	lowBits  uint64 = 0x7f7f7f7f7f7f7f7f // all but the top bit of every byte
+	highBits uint64 = 0x8080808080808080 // the top bit of every byte
+)
+
+// gfMul: multiplies two elements of GF(2^8) without branches or table lookups.
+// Returns the product.
+func gfMul(a, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ fieldReduction&-(a>>7)
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv: inverts an element of GF(2^8) as a^254 with a fixed chain of squarings and multiplications.
+// Returns the inverse, or 0 for the zero element.
+func gfInv(a byte) byte {
+	square := gfMul(a, a)
+	inv := square
+	for range 6 {
+		square = gfMul(square, square)
+		inv = gfMul(inv, square)
+	}
+	return inv
+}
+
+// mulWord: multiplies each of the eight bytes packed in v by c.
+// Returns the packed products.
+func mulWord(v uint64, c byte) uint64 {
+	var p uint64
+	for i := range 8 {
+		p ^= v & -uint64(c>>i&1)
+		// double every byte at once, reducing those whose top bit overflows
+		v = (v&lowBits)<<1 ^ (v&highBits)>>7*uint64(fieldReduction)
+	}
+	return p
+}
+
+// mulAdd: adds c * src to dst byte by byte, both of the same length.
+func mulAdd(dst, src []byte, c byte) {
+	src = src[:len(dst)]
+
+	words := len(dst) &^ 7
+	for i := 0; i < words; i += 8 {
+		p := mulWord(binary.LittleEndian.Uint64(src[i:]), c)
+		binary.LittleEndian.PutUint64(dst[i:], binary.LittleEndian.Uint64(dst[i:])^p)
+	}
+	for i := words; i < len(dst); i++ {
+		dst[i] ^= gfMul(src[i], c)
+	}
+}
@@ -0,0 +1,44 @@
+package entropy
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// drbgKeyLen: length of the ChaCha20 key, which is also the DRBG state.
+const drbgKeyLen int = chacha20.KeySize
+
+// DRBG: deterministic random bit generator built on the ChaCha20 keystream.
+// After every read the first 32 bytes of keystream become the next key and are never output (fast key erasure),
+// so earlier output cannot be recomputed from the current state. A DRBG is safe for concurrent use.
+type DRBG struct {
+	mu  sync.Mutex
+	key [drbgKeyLen]byte
+}
+
+// NewDRBG: creates a generator from a seed of any length, which is hashed into the initial key.
+// Returns the generator.
+func NewDRBG(seed []byte) *DRBG {
+	return &DRBG{key: sha256.Sum256(seed)}
+}
+
+// Read: fills b with the next output of the generator.
+// Returns len(b) and a nil error.
+func (d *DRBG) Read(b []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var nonce [chacha20.NonceSize]byte
+	stream, err := chacha20.NewUnauthenticatedCipher(d.key[:], nonce[:])
+	if err != nil {
+		return 0, err
+	}
+
+	clear(d.key[:])
+	stream.XORKeyStream(d.key[:], d.key[:])
+	clear(b)
+	stream.XORKeyStream(b, b)
+	return len(b), nil
+}
@@ -0,0 +1,121 @@
+// Package entropy provides the randomness used for keys, salts, nonces and polynomial coefficients.
+//
+// Components read from an injectable io.Reader rather than calling crypto/rand directly. Constructors take
+// Options, and package-level functions read from the process-wide default source, which is crypto/rand unless
+// replaced with SetDefault. Deterministic known-answer tests use a seeded DRBG. Production deployments can wrap
+// the operating system source in SP 800-90B style health tests.
+package entropy
+
+import (
+	"crypto/rand"
+	"io"
+	"sync/atomic"
+)
+
+// defaultSource: the process-wide source, holding an io.Reader.
+var defaultSource atomic.Value
+
+func init() {
+	defaultSource.Store(&source{rand.Reader})
+}
+
+// source: boxes a reader so that readers of different concrete types can share the atomic value.
+type source struct {
+	io.Reader
+}
+
+// Default: returns the process-wide entropy source.
+func Default() io.Reader {
+	return defaultSource.Load().(*source).Reader
+}
+
+// SetDefault: replaces the process-wide entropy source, e.g. with a health-checked source at startup or a DRBG in
+// tests. A nil reader restores crypto/rand.
+// Returns the previous source, so that tests can restore it.
+func SetDefault(r io.Reader) io.Reader {
+	if r == nil {
+		r = rand.Reader
+	}
+	return defaultSource.Swap(&source{r}).(*source).Reader
+}
+
+// Read: fills b from the process-wide source.
+// Returns an error if the source fails or runs dry.
+func Read(b []byte) error {
+	return ReadFull(nil, b)
+}
+
+// ReadFull: fills b from r, or from the process-wide source if r is nil.
+// Returns an error if the source fails or runs dry.
+func ReadFull(r io.Reader, b []byte) error {
+	if r == nil {
+		r = Default()
+	}
+	_, err := io.ReadFull(r, b)
+	return err
+}
+
+// defaultReader: reads from whatever the process-wide source is at the time of the read.
+type defaultReader struct{}
+
+func (defaultReader) Read(b []byte) (int, error) {
+	return Default().Read(b)
+}
+
+// Option: configures the entropy source of a component.
+type Option func(*config)
+
+// config: struct to hold the entropy configuration built from Options.
+type config struct {
+	reader io.Reader
+	err    error // deferred to the first read, since constructors taking options do not fail
+}
+
+// WithReader: makes the component read from r.
+func WithReader(r io.Reader) Option {
+	return func(c *config) {
+		c.reader = r
+	}
+}
+
+// WithSeed: makes the component read from a ChaCha20 DRBG seeded with seed, so that its output is reproducible.
+// Meant for known-answer tests, never for production keys.
+func WithSeed(seed []byte) Option {
+	return func(c *config) {
+		c.reader = NewDRBG(seed)
+	}
+}
+
+// WithHealthChecks: runs the continuous health tests over the source configured so far, assuming minEntropy bits
+// of min-entropy per byte.
+func WithHealthChecks(minEntropy float64) Option {
+	return func(c *config) {
+		if c.reader == nil {
+			c.reader = defaultReader{}
+		}
+		c.reader, c.err = NewHealthChecked(c.reader, minEntropy)
+	}
+}
+
+// failedReader: a source whose configuration failed, returning the error on every read.
+type failedReader struct {
+	err error
+}
+
+func (r failedReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// NewReader: builds the entropy source described by the options.
+// Without options the source follows the process-wide default, including later calls to SetDefault.
+// Returns the source; a configuration error is returned by its reads.
+func NewReader(opts ...Option) io.Reader {
+	c := &config{reader: defaultReader{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.err != nil {
+		return failedReader{c.err}
+	}
+	return c.reader
+}
@@ -0,0 +1,145 @@
+package entropy
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// The health tests follow NIST SP 800-90B section 4.4, with each byte of the source as a sample: the repetition
+// count test catches a source stuck on one value, the adaptive proportion test one value becoming too frequent.
+// Both run over startupSamples bytes before the first output and then over every byte handed out.
+
+// ErrHealthTest: returned by a health-checked source once a test has failed. The failure is permanent, since a
+// source that failed cannot be trusted again without a restart.
+var ErrHealthTest = errors.New("entropy source failed a health test")
+
+const (
+	// healthAlpha: false positive probability per sample, the strictest of the 2^-20 to 2^-40 range recommended by
+	// SP 800-90B, so that a sound source practically never trips the tests.
+	healthAlpha float64 = 0x1p-40
+
+	// aptWindow: window size of the adaptive proportion test for non-binary samples.
+	aptWindow int = 512
+
+	// startupSamples: number of samples tested and discarded before the first output.
+	startupSamples int = 1024
+)
+
+// HealthChecked: entropy source wrapped in continuous health tests. It is safe for concurrent use.
+type HealthChecked struct {
+	mu     sync.Mutex
+	src    io.Reader
+	err    error // latched failure
+	tested bool  // whether the start-up tests ran
+
+	rctCutoff int  // repetition count test cutoff
+	last      byte // last sample seen by the repetition count test
+	repeats   int  // length of the current run of last
+
+	aptCutoff int  // adaptive proportion test cutoff
+	reference byte // first sample of the current window
+	matches   int  // occurrences of reference in the current window
+	position  int  // position in the current window
+}
+
+// NewHealthChecked: wraps src in health tests calibrated for minEntropy bits of min-entropy per byte, 8 meaning
+// full entropy as from the operating system generator.
+// Returns the source and an error if minEntropy is not in (0, 8].
+func NewHealthChecked(src io.Reader, minEntropy float64) (*HealthChecked, error) {
+	if !(minEntropy > 0 && minEntropy <= 8) {
+		return nil, errors.New("min-entropy per byte must be in (0, 8], got " + strconv.FormatFloat(minEntropy, 'g', -1, 64))
+	}
+
+	return &HealthChecked{
+		src:       src,
+		rctCutoff: 1 + int(math.Ceil(-math.Log2(healthAlpha)/minEntropy)),
+		aptCutoff: 1 + critBinom(aptWindow, math.Exp2(-minEntropy), healthAlpha),
+	}, nil
+}
+
+// critBinom: finds the smallest k such that a binomial variable with n trials of probability p exceeds k with
+// probability at most alpha, the CRITBINOM(n, p, 1 - alpha) of SP 800-90B. The tail is summed from the top so that
+// the tiny probabilities involved keep their precision.
+// Returns k.
+func critBinom(n int, p, alpha float64) int {
+	pmf := make([]float64, n+1)
+	for k := range pmf {
+		lchoose, _ := math.Lgamma(float64(n + 1))
+		a, _ := math.Lgamma(float64(k + 1))
+		b, _ := math.Lgamma(float64(n - k + 1))
+		pmf[k] = math.Exp(lchoose - a - b + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+	}
+
+	tail := 0.0
+	for k := n; k >= 0; k-- {
+		if tail+pmf[k] > alpha {
+			return k
+		}
+		tail += pmf[k]
+	}
+	return 0
+}
+
+// sample: feeds one byte to both tests.
+// Returns false if a test fails.
+func (h *HealthChecked) sample(s byte) bool {
+	if h.repeats > 0 && s == h.last {
+		h.repeats++
+	} else {
+		h.last, h.repeats = s, 1
+	}
+	if h.repeats >= h.rctCutoff {
+		return false
+	}
+
+	if h.position == 0 {
+		h.reference, h.matches = s, 0
+	}
+	if s == h.reference {
+		h.matches++
+	}
+	h.position = (h.position + 1) % aptWindow
+	return h.matches < h.aptCutoff
+}
+
+// fill: reads from the source into b and tests every byte.
+// Returns an error if the source fails or a test fails, which is then latched.
+func (h *HealthChecked) fill(b []byte) error {
+	if _, err := io.ReadFull(h.src, b); err != nil {
+		return err
+	}
+	for _, s := range b {
+		if !h.sample(s) {
+			clear(b)
+			h.err = ErrHealthTest
+			return h.err
+		}
+	}
+	return nil
+}
+
+// Read: fills b from the source once the start-up tests passed, testing every byte it returns.
+// Returns len(b), or ErrHealthTest once a test has failed.
+func (h *HealthChecked) Read(b []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.err != nil {
+		return 0, h.err
+	}
+	if !h.tested {
+		startup := make([]byte, startupSamples)
+		if err := h.fill(startup); err != nil {
+			return 0, err
+		}
+		h.tested = true
+	}
+
+	if err := h.fill(b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
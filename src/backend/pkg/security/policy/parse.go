@@ -0,0 +1,298 @@
+package policy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind: type of a node in a policy tree.
+type Kind int
+
+const (
+	Leaf Kind = iota
+	And
+	Or
+	Threshold
+)
+
+// Node: struct to hold a node of a monotone access policy.
+type Node struct {
+	Kind      Kind    // leaf, AND, OR or threshold gate
+	Label     string  // holder label, only set on leaves
+	Threshold int     // number of children needed, only set on threshold gates
+	Children  []*Node // operands of the gate, empty on leaves
+}
+
+// String: formats the policy in the syntax accepted by Parse.
+// Returns the policy expression.
+func (n *Node) String() string {
+	switch n.Kind {
+	case Leaf:
+		return n.Label
+	case Threshold:
+		parts := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			parts[i] = child.String()
+		}
+		return strconv.Itoa(n.Threshold) + "-of(" + strings.Join(parts, ", ") + ")"
+	}
+
+	op := " AND "
+	if n.Kind == Or {
+		op = " OR "
+	}
+	parts := make([]string, len(n.Children))
+	for i, child := range n.Children {
+		parts[i] = child.String()
+		if child.Kind == And || child.Kind == Or {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, op)
+}
+
+// Labels: collects the distinct holder labels of the policy in order of first appearance.
+// Returns the labels.
+func (n *Node) Labels() []string {
+	var labels []string
+	seen := make(map[string]bool)
+
+	var walk func(*Node)
+	walk = func(node *Node) {
+		if node.Kind == Leaf {
+			if !seen[node.Label] {
+				seen[node.Label] = true
+				labels = append(labels, node.Label)
+			}
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return labels
+}
+
+// tokenKind: type of a lexical token of a policy expression.
+type tokenKind int
+
+const (
+	tokenLabel tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenThreshold
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenEnd
+)
+
+// token: struct to hold a lexical token and its position in the expression.
+type token struct {
+	kind  tokenKind
+	text  string
+	value int // k of a "k-of" token
+	pos   int
+}
+
+// isLabelRune: checks whether r may appear in a holder label.
+// Returns true for letters, digits, '_', '.', '@' and '-'.
+func isLabelRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.@-", r)
+}
+
+// isThreshold: checks whether the label ending at runes[end] is a "k-of" gate, i.e. decimal digits followed by
+// "-of" and an opening parenthesis, so that labels such as "chief-of" stay labels.
+// Returns true if the label is a threshold gate.
+func isThreshold(text string, runes []rune, end int) bool {
+	digits := text[:len(text)-len("-of")]
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return false
+	}
+	for end < len(runes) && unicode.IsSpace(runes[end]) {
+		end++
+	}
+	return end < len(runes) && runes[end] == '('
+}
+
+// tokenize: splits a policy expression into tokens.
+// AND and OR are case-insensitive keywords, "k-of" followed by a parenthesis introduces a threshold gate.
+// Returns the tokens and an error if the expression contains an invalid character.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case isLabelRune(r):
+			start := i
+			for i < len(runes) && isLabelRune(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+
+			switch upper := strings.ToUpper(text); {
+			case upper == "AND":
+				tokens = append(tokens, token{kind: tokenAnd, text: text, pos: start})
+			case upper == "OR":
+				tokens = append(tokens, token{kind: tokenOr, text: text, pos: start})
+			case strings.HasSuffix(upper, "-OF") && isThreshold(text, runes, i):
+				k, err := strconv.Atoi(text[:len(text)-3])
+				if err != nil || k < 1 {
+					return nil, errors.New("invalid threshold at position " + strconv.Itoa(start) + ": " + text)
+				}
+				tokens = append(tokens, token{kind: tokenThreshold, text: text, value: k, pos: start})
+			default:
+				tokens = append(tokens, token{kind: tokenLabel, text: text, pos: start})
+			}
+		default:
+			return nil, errors.New("unexpected character at position " + strconv.Itoa(i) + ": " + string(r))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, text: "end of expression", pos: len(runes)}), nil
+}
+
+// parser: recursive-descent parser over the tokens of a policy expression.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek: returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next: consumes and returns the current token.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+// expect: consumes the current token if it has the given kind.
+// Returns an error naming the unexpected token otherwise.
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return errors.New("expected " + what + " at position " + strconv.Itoa(t.pos) + ", found " + t.text)
+	}
+	return nil
+}
+
+// parseOr: parses expr := and ("OR" and)*.
+// Returns the node and an error if the expression is malformed.
+func (p *parser) parseOr() (*Node, error) {
+	return p.parseChain(tokenOr, Or, p.parseAnd)
+}
+
+// parseAnd: parses and := operand ("AND" operand)*.
+// Returns the node and an error if the expression is malformed.
+func (p *parser) parseAnd() (*Node, error) {
+	return p.parseChain(tokenAnd, And, p.parseOperand)
+}
+
+// parseChain: parses a chain of operands joined by the same operator into a single flat gate.
+// Returns the operand itself when there is no operator, and an error if the expression is malformed.
+func (p *parser) parseChain(op tokenKind, kind Kind, operand func() (*Node, error)) (*Node, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != op {
+		return first, nil
+	}
+
+	node := &Node{Kind: kind, Children: []*Node{first}}
+	for p.peek().kind == op {
+		p.next()
+		child, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// parseOperand: parses operand := label | "(" expr ")" | k-of "(" expr ("," expr)* ")".
+// Returns the node and an error if the expression is malformed.
+func (p *parser) parseOperand() (*Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLabel:
+		return &Node{Kind: Leaf, Label: t.text}, nil
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenClose, "')'"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case tokenThreshold:
+		if err := p.expect(tokenOpen, "'('"); err != nil {
+			return nil, err
+		}
+		node := &Node{Kind: Threshold, Threshold: t.value}
+		for {
+			child, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(tokenClose, "')'"); err != nil {
+			return nil, err
+		}
+		if node.Threshold > len(node.Children) {
+			return nil, errors.New("threshold " + t.text + " at position " + strconv.Itoa(t.pos) + " exceeds its " + strconv.Itoa(len(node.Children)) + " operands")
+		}
+		return node, nil
+	}
+
+	return nil, errors.New("expected a label, '(' or threshold at position " + strconv.Itoa(t.pos) + ", found " + t.text)
+}
+
+// Parse: parses a policy expression such as "(alice AND bob) OR 2-of(carol, dave, erin)".
+// AND binds tighter than OR, and "k-of(...)" requires any k of its comma-separated operands.
+// Returns the policy tree and an error if the expression is malformed.
+func Parse(expr string) (*Node, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenEnd, "end of expression"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
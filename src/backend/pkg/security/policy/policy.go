@@ -0,0 +1,259 @@
+package policy
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// Share: struct to hold the share of one leaf of a Benaloh-Leichter split.
+// A holder appearing several times in the policy receives one share per occurrence.
+type Share struct {
+	Label string `json:"label" bson:"label"` // holder the share belongs to
+	Path  string `json:"path" bson:"path"`   // position of the leaf in the policy tree, e.g. "0.1.2"
+	Value []byte `json:"value" bson:"value"` // value shared down to the leaf
+}
+
+// Result: struct to hold the outcome of a policy reconstruction.
+type Result struct {
+	Secret    []byte // reconstructed secret
+	Satisfied *Node  // the branch of the policy that was satisfied, pruned to the operands actually used
+}
+
+// validate: checks that a policy tree is well-formed.
+// Returns an error if a gate has no operands, a threshold is out of range or a leaf has no label.
+func (n *Node) validate() error {
+	switch n.Kind {
+	case Leaf:
+		if n.Label == "" {
+			return errors.New("policy leaves must have a label")
+		}
+		return nil
+	case And, Or:
+	case Threshold:
+		if n.Threshold < 1 || n.Threshold > len(n.Children) {
+			return errors.New("threshold gate must require between 1 and " + strconv.Itoa(len(n.Children)) + " operands")
+		}
+		if len(n.Children) > sss.MaxShares {
+			return errors.New("threshold gate cannot have more than 255 operands")
+		}
+	default:
+		return errors.New("unknown policy node kind: " + strconv.Itoa(int(n.Kind)))
+	}
+
+	if len(n.Children) == 0 {
+		return errors.New("policy gates must have at least 1 operand")
+	}
+	for _, child := range n.Children {
+		if child == nil {
+			return errors.New("policy gates cannot have nil operands")
+		}
+		if err := child.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childPath: computes the path of the i-th operand of the node at path.
+// Returns the child path.
+func childPath(path string, i int) string {
+	return path + "." + strconv.Itoa(i)
+}
+
+// Split: shares a secret according to a monotone policy with the Benaloh-Leichter construction.
+// AND gates split their value into XOR shares, threshold gates into Shamir shares, and OR gates copy it to every operand.
+// Returns one share per policy leaf and an error if the policy is malformed or the split fails.
+func Split(policy *Node, secret []byte) ([]Share, error) {
+	if policy == nil {
+		return nil, errors.New("policy cannot be nil")
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	var shares []Share
+	if err := split(policy, "0", secret, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// split: shares value down the subtree rooted at node, appending the leaf shares.
+// Returns an error if the random generation or a Shamir split fails.
+func split(node *Node, path string, value []byte, shares *[]Share) error {
+	switch {
+	case node.Kind == Leaf:
+		*shares = append(*shares, Share{Label: node.Label, Path: path, Value: slices.Clone(value)})
+		return nil
+
+	case node.Kind == Or, node.Kind == Threshold && node.Threshold == 1:
+		for i, child := range node.Children {
+			if err := split(child, childPath(path, i), value, shares); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case node.Kind == And:
+		last := slices.Clone(value)
+		for i, child := range node.Children[:len(node.Children)-1] {
+			mask := make([]byte, len(value))
+			if err := entropy.Read(mask); err != nil {
+				return err
+			}
+			for b := range last {
+				last[b] ^= mask[b]
+			}
+			if err := split(child, childPath(path, i), mask, shares); err != nil {
+				return err
+			}
+		}
+		return split(node.Children[len(node.Children)-1], childPath(path, len(node.Children)-1), last, shares)
+	}
+
+	// threshold gates with k >= 2: operand i receives the Shamir share at x = i+1
+	shamirShares, err := sss.Split(value, len(node.Children), node.Threshold)
+	if err != nil {
+		return err
+	}
+	for i, child := range node.Children {
+		if err := split(child, childPath(path, i), shamirShares[i].Y, shares); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Combine: reconstructs a secret from labelled leaf shares and reports which branch of the policy they satisfy.
+// OR gates and threshold gates use their operands in policy order, so the reported branch is the first one satisfied.
+// Returns the result, an *sss.AccessError if the shares do not satisfy the policy, or another error if they are malformed.
+func Combine(policy *Node, shares []Share) (*Result, error) {
+	if policy == nil {
+		return nil, errors.New("policy cannot be nil")
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]Share, len(shares))
+	for _, share := range shares {
+		if prev, ok := byPath[share.Path]; ok && !slices.Equal(prev.Value, share.Value) {
+			return nil, errors.New("conflicting shares for policy leaf " + share.Path)
+		}
+		byPath[share.Path] = share
+	}
+
+	secret, satisfied, err := combine(policy, "0", byPath)
+	if err != nil {
+		return nil, err
+	}
+	if satisfied == nil {
+		return nil, &sss.AccessError{Reason: "policy " + policy.String() + " is not satisfied by " + describeHolders(shares)}
+	}
+
+	return &Result{Secret: secret, Satisfied: satisfied}, nil
+}
+
+// combine: reconstructs the value of the subtree rooted at node from the available leaf shares.
+// Returns the value and the satisfied branch, a nil branch if the subtree is not satisfied, and an error if shares are malformed.
+func combine(node *Node, path string, byPath map[string]Share) ([]byte, *Node, error) {
+	if node.Kind == Leaf {
+		share, ok := byPath[path]
+		if !ok {
+			return nil, nil, nil
+		}
+		if share.Label != node.Label {
+			return nil, nil, errors.New("share for policy leaf " + path + " belongs to " + share.Label + ", expected " + node.Label)
+		}
+		return share.Value, &Node{Kind: Leaf, Label: node.Label}, nil
+	}
+
+	needed := len(node.Children)
+	switch {
+	case node.Kind == Or:
+		needed = 1
+	case node.Kind == Threshold:
+		needed = node.Threshold
+	}
+
+	var (
+		values [][]byte
+		xs     []byte
+		used   []*Node
+	)
+	for i, child := range node.Children {
+		value, branch, err := combine(child, childPath(path, i), byPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if branch == nil {
+			if node.Kind == And {
+				return nil, nil, nil
+			}
+			continue
+		}
+
+		values = append(values, value)
+		xs = append(xs, byte(i+1))
+		used = append(used, branch)
+		if len(values) == needed {
+			break
+		}
+	}
+	if len(values) < needed {
+		return nil, nil, nil
+	}
+
+	switch {
+	case needed == 1:
+		if node.Kind == And {
+			return values[0], &Node{Kind: And, Children: used}, nil
+		}
+		return values[0], used[0], nil
+
+	case node.Kind == And:
+		secret := slices.Clone(values[0])
+		for _, value := range values[1:] {
+			if len(value) != len(secret) {
+				return nil, nil, errors.New("shares under policy node " + path + " have different lengths")
+			}
+			for b := range secret {
+				secret[b] ^= value[b]
+			}
+		}
+		return secret, &Node{Kind: And, Children: used}, nil
+	}
+
+	shamirShares := make([]sss.Share, len(values))
+	for i := range values {
+		shamirShares[i] = sss.Share{X: xs[i], Y: values[i]}
+	}
+	secret, err := sss.Combine(shamirShares)
+	if err != nil {
+		return nil, nil, errors.New("policy node " + path + ": " + err.Error())
+	}
+	return secret, &Node{Kind: Threshold, Threshold: node.Threshold, Children: used}, nil
+}
+
+// describeHolders: lists the distinct labels of the supplied shares for error messages.
+// Returns the labels, or "no shares" if there are none.
+func describeHolders(shares []Share) string {
+	var labels []string
+	for _, share := range shares {
+		if !slices.Contains(labels, share.Label) {
+			labels = append(labels, share.Label)
+		}
+	}
+	if len(labels) == 0 {
+		return "no shares"
+	}
+	return strings.Join(labels, ", ")
+}
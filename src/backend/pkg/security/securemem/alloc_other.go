@@ -0,0 +1,16 @@
+//go:build !unix
+
+package securemem
+
+// allocate: falls back to a heap allocation, which is zeroed on Close but neither locked nor guarded.
+// Returns the allocation twice, false, and a nil error.
+func allocate(size int) ([]byte, []byte, bool, error) {
+	data := make([]byte, size)
+	return data, data, false, nil
+}
+
+// release: leaves the zeroed allocation to the garbage collector.
+// Returns nil.
+func release(region []byte, locked bool) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build unix
+
+package securemem
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// allocate: maps the pages holding size bytes between two inaccessible guard pages and locks them in memory.
+// The secret is placed at the end of its pages, so that an overrun faults on the trailing guard page.
+// Returns the whole mapping, the secret slice, whether the pages are locked, and an error if the mapping fails.
+func allocate(size int) ([]byte, []byte, bool, error) {
+	page := os.Getpagesize()
+	inner := (size + page - 1) / page * page
+
+	region, err := unix.Mmap(-1, 0, inner+2*page, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if err := unix.Mprotect(region[:page], unix.PROT_NONE); err != nil {
+		_ = unix.Munmap(region)
+		return nil, nil, false, err
+	}
+	if err := unix.Mprotect(region[page+inner:], unix.PROT_NONE); err != nil {
+		_ = unix.Munmap(region)
+		return nil, nil, false, err
+	}
+
+	pages := region[page : page+inner]
+	excludeFromDump(pages)
+	locked := unix.Mlock(pages) == nil
+
+	return region, pages[inner-size:], locked, nil
+}
+
+// release: unlocks and unmaps a mapping made by allocate.
+// Returns an error if the mapping cannot be removed.
+func release(region []byte, locked bool) error {
+	page := os.Getpagesize()
+	if locked {
+		_ = unix.Munlock(region[page : len(region)-page])
+	}
+	return unix.Munmap(region)
+}
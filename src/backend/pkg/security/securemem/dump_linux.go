@@ -0,0 +1,8 @@
+package securemem
+
+import "golang.org/x/sys/unix"
+
+// excludeFromDump: keeps the pages out of core dumps.
+func excludeFromDump(pages []byte) {
+	_ = unix.Madvise(pages, unix.MADV_DONTDUMP)
+}
@@ -0,0 +1,6 @@
+//go:build unix && !linux
+
+package securemem
+
+// excludeFromDump: does nothing, core dump exclusion being Linux-specific.
+func excludeFromDump(pages []byte) {}
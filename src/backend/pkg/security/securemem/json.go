@@ -0,0 +1,134 @@
+package securemem
+
+import (
+	"errors"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UnmarshalJSON: decodes a JSON string straight into locked memory, so that a request field such as a password
+// never exists as a Go string. The raw JSON still passes through the decoder's own buffer.
+// Returns an error if the value is not a non-empty JSON string or the memory cannot be mapped.
+func (b *SecretBuffer) UnmarshalJSON(data []byte) error {
+	size, err := unquote(data, nil)
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		return errors.New("secret cannot be empty")
+	}
+
+	if err := b.Close(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.init(size); err != nil {
+		return err
+	}
+	_, err = unquote(data, b.data)
+	return err
+}
+
+// unquote: decodes the JSON string literal raw into dst, or only measures it if dst is nil.
+// Invalid surrogate escapes decode to U+FFFD, as with encoding/json.
+// Returns the decoded length and an error if raw is not a valid JSON string.
+func unquote(raw, dst []byte) (int, error) {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return 0, errors.New("secret must be a JSON string")
+	}
+	s := raw[1 : len(raw)-1]
+
+	var n int
+	var scratch [utf8.UTFMax]byte
+	defer Wipe(scratch[:])
+
+	emit := func(p []byte) {
+		if dst != nil {
+			copy(dst[n:], p)
+		}
+		n += len(p)
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < 0x20 || c == '"' {
+			return 0, errors.New("secret is not a valid JSON string")
+		}
+		if c != '\\' {
+			scratch[0] = c
+			emit(scratch[:1])
+			i++
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return 0, errors.New("secret ends in an incomplete escape")
+		}
+		switch s[i+1] {
+		case '"', '\\', '/':
+			scratch[0] = s[i+1]
+		case 'b':
+			scratch[0] = '\b'
+		case 'f':
+			scratch[0] = '\f'
+		case 'n':
+			scratch[0] = '\n'
+		case 'r':
+			scratch[0] = '\r'
+		case 't':
+			scratch[0] = '\t'
+		case 'u':
+			r, ok := hexRune(s[i+2:])
+			if !ok {
+				return 0, errors.New("secret contains an invalid unicode escape")
+			}
+			i += 6
+			if high := r; utf16.IsSurrogate(high) {
+				r = utf8.RuneError
+				if i+1 < len(s) && s[i] == '\\' && s[i+1] == 'u' {
+					if low, ok := hexRune(s[i+2:]); ok {
+						if decoded := utf16.DecodeRune(high, low); decoded != utf8.RuneError {
+							r = decoded
+							i += 6
+						}
+					}
+				}
+			}
+			emit(scratch[:utf8.EncodeRune(scratch[:], r)])
+			continue
+		default:
+			return 0, errors.New("secret contains an invalid escape")
+		}
+		emit(scratch[:1])
+		i += 2
+	}
+
+	return n, nil
+}
+
+// hexRune: parses the four hexadecimal digits of a \u escape at the start of s.
+// Returns the code unit and false if s does not start with four hexadecimal digits.
+func hexRune(s []byte) (rune, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+
+	var r rune
+	for _, c := range s[:4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c -= 'a' - 10
+		case 'A' <= c && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
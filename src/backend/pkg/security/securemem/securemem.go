@@ -0,0 +1,117 @@
+// Package securemem keeps secrets out of the garbage-collected heap.
+//
+// A SecretBuffer lives in its own memory mapping. The pages are locked so they are never swapped. Guard pages
+// on both sides fault on overruns. The contents are zeroed when the buffer is closed, instead of lingering
+// until the collector reuses the memory. On platforms without mmap the buffer falls back to an ordinary heap
+// allocation that is still zeroed on Close.
+package securemem
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// ErrClosed: returned when a closed SecretBuffer is used.
+var ErrClosed = errors.New("secret buffer is closed")
+
+// SecretBuffer: struct to hold a secret in locked memory outside the garbage-collected heap.
+// A SecretBuffer must be closed once the secret is no longer needed; a finalizer closes forgotten buffers,
+// but only whenever the collector gets to them.
+type SecretBuffer struct {
+	mu     sync.Mutex
+	region []byte // whole allocation, guard pages included
+	data   []byte // the secret, ending right before the trailing guard page
+	locked bool   // whether the pages are locked in memory
+}
+
+// New: allocates a zeroed secret buffer of the given size.
+// Returns the buffer and an error if the size is not positive or the memory cannot be mapped.
+func New(size int) (*SecretBuffer, error) {
+	if size <= 0 {
+		return nil, errors.New("secret buffer size must be positive, got " + strconv.Itoa(size))
+	}
+
+	b := &SecretBuffer{}
+	if err := b.init(size); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// init: allocates the memory of an empty buffer.
+// Returns an error if the memory cannot be mapped.
+func (b *SecretBuffer) init(size int) error {
+	region, data, locked, err := allocate(size)
+	if err != nil {
+		return err
+	}
+
+	b.region, b.data, b.locked = region, data, locked
+	runtime.SetFinalizer(b, (*SecretBuffer).Close)
+	return nil
+}
+
+// FromBytes: moves a secret into a new buffer, wiping the source slice.
+// Returns the buffer and an error if the secret is empty or the memory cannot be mapped.
+func FromBytes(src []byte) (*SecretBuffer, error) {
+	b, err := New(len(src))
+	if err != nil {
+		return nil, err
+	}
+	copy(b.data, src)
+	Wipe(src)
+	return b, nil
+}
+
+// Bytes: returns the secret. The slice aliases the locked memory and must not be used after Close.
+// Returns nil once the buffer is closed.
+func (b *SecretBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+// Len: returns the length of the secret, or 0 once the buffer is closed.
+func (b *SecretBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.data)
+}
+
+// Locked: reports whether the secret is locked in memory. Locking fails when the process exceeds its
+// locked memory limit (RLIMIT_MEMLOCK), in which case the buffer still works but may be swapped out.
+func (b *SecretBuffer) Locked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.locked
+}
+
+// Close: zeroes the secret and releases its memory. Closing a closed or nil buffer does nothing.
+// Returns an error if the memory cannot be released.
+func (b *SecretBuffer) Close() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.data == nil {
+		return nil
+	}
+
+	Wipe(b.data)
+	err := release(b.region, b.locked)
+	b.region, b.data, b.locked = nil, nil, false
+	runtime.SetFinalizer(b, nil)
+	return err
+}
+
+// Wipe: zeroes a slice holding sensitive material, such as a decoded request field or a heap copy of a share.
+func Wipe(data []byte) {
+	clear(data)
+	// keeps the stores from being treated as dead when data is not read again
+	runtime.KeepAlive(data)
+}
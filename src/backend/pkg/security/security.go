@@ -1,12 +1,15 @@
 package security
 
 import (
-	"bytes"
-	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
+	"io"
 
 	"golang.org/x/crypto/argon2"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
 )
 
 // Argon2idHash: struct to hold the Argon2id hash configuration.
@@ -16,6 +19,8 @@ type Argon2idHash struct {
 	Threads uint8  // number of threads
 	KeyLen  uint32 // key length
 	SaltLen uint32 // salt length
+
+	entropy io.Reader // source of the salts, the process-wide default if nil
 }
 
 // HashSalt: struct to hold the hash and salt.
@@ -24,25 +29,25 @@ type HashSalt struct {
 	Salt []byte // salt used for hashing
 }
 
-// NewArgon2idHash: creates a new Argon2id hash.
+// NewArgon2idHash: creates a new Argon2id hash, drawing salts from the entropy source set by the options.
 // Returns the Argon2id hash.
-func NewArgon2idHash(time, memory uint32, threads uint8, keyLen, saltLen uint32) *Argon2idHash {
+func NewArgon2idHash(time, memory uint32, threads uint8, keyLen, saltLen uint32, opts ...entropy.Option) *Argon2idHash {
 	return &Argon2idHash{
 		Time:    time,
 		Memory:  memory,
 		Threads: threads,
 		KeyLen:  keyLen,
 		SaltLen: saltLen,
+		entropy: entropy.NewReader(opts...),
 	}
 }
 
 // secret: generates a random secret.
 // Returns the secret and an error if the secret generation fails.
-func secret(len uint32) ([]byte, error) {
+func (a *Argon2idHash) secret(len uint32) ([]byte, error) {
 	secretBytes := make([]byte, len)
 
-	_, err := rand.Read(secretBytes)
-	if err != nil {
+	if err := entropy.ReadFull(a.entropy, secretBytes); err != nil {
 		return nil, err
 	}
 
@@ -55,27 +60,51 @@ func (a *Argon2idHash) GenerateHash(password, salt []byte) (*HashSalt, error) {
 	var err error
 
 	if len(salt) == 0 {
-		salt, err = secret(a.SaltLen)
+		salt, err = a.secret(a.SaltLen)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	hash := hex.EncodeToString(argon2.IDKey(password, salt, a.Time, a.Memory, a.Threads, a.KeyLen))
-	return &HashSalt{Hash: []byte(hash), Salt: salt}, nil
+	// the key is hex-encoded in place of a string conversion, so no immutable copy of it is left behind
+	key := argon2.IDKey(password, salt, a.Time, a.Memory, a.Threads, a.KeyLen)
+	defer securemem.Wipe(key)
+
+	hash := make([]byte, hex.EncodedLen(len(key)))
+	hex.Encode(hash, key)
+	return &HashSalt{Hash: hash, Salt: salt}, nil
+}
+
+// GenerateHashBuffer: generates a hash for a password held in a secret buffer, as GenerateHash does.
+// Returns the hash and an error if the buffer is closed or the hash generation fails.
+func (a *Argon2idHash) GenerateHashBuffer(password *securemem.SecretBuffer, salt []byte) (*HashSalt, error) {
+	if password.Len() == 0 {
+		return nil, securemem.ErrClosed
+	}
+	return a.GenerateHash(password.Bytes(), salt)
 }
 
-// ComparePasswords: compares a password and a hash.
+// ComparePasswords: compares a password and a hash in constant time.
 // Returns an error if the passwords do not match.
 func (a *Argon2idHash) ComparePasswords(password, salt, hash []byte) error {
 	hashSalt, err := a.GenerateHash(password, salt)
 	if err != nil {
 		return err
 	}
+	defer securemem.Wipe(hashSalt.Hash)
 
-	if !bytes.Equal(hash, hashSalt.Hash) {
+	if subtle.ConstantTimeCompare(hash, hashSalt.Hash) != 1 {
 		return errors.New("authentication failed: password verification failed")
 	}
 
 	return nil
 }
+
+// ComparePasswordBuffer: compares a password held in a secret buffer and a hash, as ComparePasswords does.
+// Returns an error if the buffer is closed or the passwords do not match.
+func (a *Argon2idHash) ComparePasswordBuffer(password *securemem.SecretBuffer, salt, hash []byte) error {
+	if password.Len() == 0 {
+		return securemem.ErrClosed
+	}
+	return a.ComparePasswords(password.Bytes(), salt, hash)
+}
@@ -0,0 +1,67 @@
+package slip39
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// baseIterationCount: total PBKDF2 iterations of the Feistel network at iteration exponent 0.
+const baseIterationCount int = 10000
+
+// roundCount: number of rounds of the Feistel network.
+const roundCount int = 4
+
+// feistelSalt: computes the salt prefix of the round function.
+// Non-extendable shares bind the encryption to the identifier, extendable ones use an empty prefix.
+// Returns the salt prefix.
+func feistelSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return binary.BigEndian.AppendUint16([]byte(customizationString), identifier)
+}
+
+// roundFunction: computes F(i, R) = PBKDF2-HMAC-SHA256(i || passphrase, salt || R) with len(R) output bytes.
+// Returns the round output.
+func roundFunction(i int, passphrase []byte, exponent int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterationCount / roundCount) << exponent
+
+	key, err := pbkdf2.Key(sha256.New, string(password), append(append([]byte{}, salt...), r...), iterations, len(r))
+	if err != nil {
+		// only reachable with FIPS-140 restrictions on short passwords or salts, which SLIP-0039 cannot honour
+		panic("slip39: PBKDF2 failed: " + err.Error())
+	}
+	return key
+}
+
+// feistel: runs the 4-round Feistel network over an even-length secret, with the rounds in the given order.
+// Returns R || L of the last round.
+func feistel(secret, passphrase []byte, exponent int, salt []byte, rounds []int) []byte {
+	half := len(secret) / 2
+	l := append([]byte{}, secret[:half]...)
+	r := append([]byte{}, secret[half:]...)
+
+	for _, i := range rounds {
+		f := roundFunction(i, passphrase, exponent, salt, r)
+		for b := range l {
+			l[b] ^= f[b]
+		}
+		l, r = r, l
+	}
+
+	return append(r, l...)
+}
+
+// encrypt: encrypts the master secret with the passphrase.
+// Returns the encrypted master secret.
+func encrypt(masterSecret, passphrase []byte, exponent int, identifier uint16, extendable bool) []byte {
+	return feistel(masterSecret, passphrase, exponent, feistelSalt(identifier, extendable), []int{0, 1, 2, 3})
+}
+
+// decrypt: decrypts the encrypted master secret with the passphrase.
+// Returns the master secret.
+func decrypt(encrypted, passphrase []byte, exponent int, identifier uint16, extendable bool) []byte {
+	return feistel(encrypted, passphrase, exponent, feistelSalt(identifier, extendable), []int{3, 2, 1, 0})
+}
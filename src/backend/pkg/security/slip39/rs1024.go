@@ -0,0 +1,64 @@
+package slip39
+
+// customizationString: checksum customization for shares without the extendable backup flag.
+const customizationString string = "shamir"
+
+// customizationStringExtendable: checksum customization for shares with the extendable backup flag.
+const customizationStringExtendable string = "shamir_extendable"
+
+// checksumWords: number of words of the RS1024 checksum.
+const checksumWords int = 3
+
+// rs1024Generator: generator of the Reed-Solomon code over GF(1024) used for the checksum.
+var rs1024Generator = [10]uint32{
+	0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+	0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+}
+
+// customization: picks the checksum customization string for a share.
+// Returns the customization string as 10-bit values.
+func customization(extendable bool) []int {
+	cs := customizationString
+	if extendable {
+		cs = customizationStringExtendable
+	}
+
+	values := make([]int, len(cs))
+	for i := range len(cs) {
+		values[i] = int(cs[i])
+	}
+	return values
+}
+
+// rs1024Polymod: computes the checksum polynomial of a sequence of 10-bit values.
+// Returns the checksum state.
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ uint32(v)
+		for i, g := range rs1024Generator {
+			chk ^= g & -(top >> i & 1)
+		}
+	}
+	return chk
+}
+
+// rs1024Checksum: computes the 3-word checksum of the share data.
+// Returns the checksum words.
+func rs1024Checksum(data []int, extendable bool) []int {
+	values := append(customization(extendable), data...)
+	polymod := rs1024Polymod(append(values, make([]int, checksumWords)...)) ^ 1
+
+	checksum := make([]int, checksumWords)
+	for i := range checksum {
+		checksum[i] = int(polymod>>(10*(checksumWords-1-i))) & (wordCount - 1)
+	}
+	return checksum
+}
+
+// rs1024Verify: checks the checksum at the end of the share words.
+// Returns true if the checksum is valid.
+func rs1024Verify(words []int, extendable bool) bool {
+	return rs1024Polymod(append(customization(extendable), words...)) == 1
+}
@@ -0,0 +1,144 @@
+package slip39
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Bit layout of a share, see SLIP-0039.
+const (
+	radixBits             int = 10 // bits encoded by one word
+	identifierBits        int = 15 // random identifier common to all shares of a secret
+	extendableBits        int = 1  // extendable backup flag
+	iterationExponentBits int = 4  // exponent of the PBKDF2 iteration count
+	maxShareCount         int = 16 // 4-bit group and member indices
+
+	metadataWords  int = 4 + checksumWords // identifier/flag/exponent and share parameters take 2 words each
+	minSecretBytes int = 16
+	minWords       int = metadataWords + (minSecretBytes*8+radixBits-1)/radixBits
+)
+
+// ErrInvalidChecksum: returned when a mnemonic's RS1024 checksum does not verify, usually because of a mistyped word.
+var ErrInvalidChecksum = errors.New("invalid mnemonic checksum")
+
+// Share: struct to hold a decoded SLIP-0039 share.
+type Share struct {
+	Identifier        uint16 // random 15-bit identifier common to all shares of a secret
+	Extendable        bool   // whether the encryption is independent of the identifier
+	IterationExponent int    // PBKDF2 iteration count is 2500 << exponent per round
+	GroupIndex        int    // index of the group the share belongs to
+	GroupThreshold    int    // number of groups needed to reconstruct
+	GroupCount        int    // total number of groups
+	MemberIndex       int    // index of the share inside its group
+	MemberThreshold   int    // number of shares of the group needed to reconstruct the group secret
+	Value             []byte // share value
+}
+
+// intToWords: splits the low count*10 bits of v into 10-bit words, most significant first.
+// Returns the word indices.
+func intToWords(v *big.Int, count int) []int {
+	words := make([]int, count)
+	mask := big.NewInt(int64(wordCount - 1))
+	tmp := new(big.Int)
+
+	for i := range words {
+		tmp.Rsh(v, uint(radixBits*(count-1-i)))
+		words[i] = int(tmp.And(tmp, mask).Int64())
+	}
+	return words
+}
+
+// wordsToInt: joins 10-bit words, most significant first.
+// Returns the integer.
+func wordsToInt(words []int) *big.Int {
+	v := new(big.Int)
+	for _, w := range words {
+		v.Lsh(v, uint(radixBits))
+		v.Or(v, big.NewInt(int64(w)))
+	}
+	return v
+}
+
+// words: encodes the share as word indices, including the checksum.
+// Returns the word indices.
+func (s *Share) words() []int {
+	idExp := int(s.Identifier)<<(extendableBits+iterationExponentBits) | s.IterationExponent
+	if s.Extendable {
+		idExp |= 1 << iterationExponentBits
+	}
+	params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+
+	valueWords := (len(s.Value)*8 + radixBits - 1) / radixBits
+	data := []int{idExp >> radixBits, idExp & (wordCount - 1), params >> radixBits, params & (wordCount - 1)}
+	data = append(data, intToWords(new(big.Int).SetBytes(s.Value), valueWords)...)
+
+	return append(data, rs1024Checksum(data, s.Extendable)...)
+}
+
+// Mnemonic: encodes the share as a space-separated SLIP-0039 mnemonic.
+// Returns the mnemonic.
+func (s *Share) Mnemonic() string {
+	indices := s.words()
+	words := make([]string, len(indices))
+	for i, index := range indices {
+		words[i] = wordlist[index]
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseShare: decodes a SLIP-0039 mnemonic, checking its length, checksum and padding.
+// Words are matched case-insensitively and may be separated by any whitespace.
+// Returns the share, ErrInvalidChecksum if the checksum does not verify, or another error if the mnemonic is malformed.
+func ParseShare(mnemonic string) (*Share, error) {
+	fields := strings.Fields(strings.ToLower(mnemonic))
+	if len(fields) < minWords {
+		return nil, errors.New("mnemonic must have at least " + strconv.Itoa(minWords) + " words")
+	}
+
+	words := make([]int, len(fields))
+	for i, field := range fields {
+		index, ok := wordIndex[field]
+		if !ok {
+			return nil, errors.New("unknown mnemonic word: " + field)
+		}
+		words[i] = index
+	}
+
+	padding := radixBits * (len(words) - metadataWords) % 16
+	if padding > 8 {
+		return nil, errors.New("invalid mnemonic length")
+	}
+
+	idExp := words[0]<<radixBits | words[1]
+	extendable := (idExp>>iterationExponentBits)&1 == 1
+	if !rs1024Verify(words, extendable) {
+		return nil, ErrInvalidChecksum
+	}
+
+	params := words[2]<<radixBits | words[3]
+	s := &Share{
+		Identifier:        uint16(idExp >> (extendableBits + iterationExponentBits)),
+		Extendable:        extendable,
+		IterationExponent: idExp & (1<<iterationExponentBits - 1),
+		GroupIndex:        params >> 16,
+		GroupThreshold:    (params>>12)&0xf + 1,
+		GroupCount:        (params>>8)&0xf + 1,
+		MemberIndex:       (params >> 4) & 0xf,
+		MemberThreshold:   params&0xf + 1,
+	}
+	if s.GroupCount < s.GroupThreshold {
+		return nil, errors.New("group threshold cannot be greater than the group count")
+	}
+
+	valueWords := words[4 : len(words)-checksumWords]
+	value := wordsToInt(valueWords)
+	byteCount := (radixBits*len(valueWords) - padding) / 8
+	if value.BitLen() > byteCount*8 {
+		return nil, errors.New("invalid mnemonic padding")
+	}
+	s.Value = value.FillBytes(make([]byte, byteCount))
+
+	return s, nil
+}
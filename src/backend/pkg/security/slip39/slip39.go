@@ -0,0 +1,292 @@
+package slip39
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// Evaluation points reserved by SLIP-0039 for the shared secret and its digest.
+const (
+	secretIndex byte = 255
+	digestIndex byte = 254
+	digestLen   int  = 4
+)
+
+// MaxIterationExponent: largest iteration exponent that fits in a share.
+const MaxIterationExponent int = 1<<iterationExponentBits - 1
+
+// Group: struct to describe one group of a two-level split.
+type Group struct {
+	Threshold int // number of member shares needed to reconstruct the group secret
+	Count     int // number of member shares in the group
+}
+
+// random: generates cryptographically secure random bytes.
+// Returns the bytes and an error if the random generation fails.
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if err := entropy.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// secretDigest: computes the first 4 bytes of HMAC-SHA256(randomPart, secret).
+// Returns the digest.
+func secretDigest(randomPart, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLen]
+}
+
+// splitSecret: splits a secret into count shares at x = 0..count-1, any threshold of which recover it.
+// The polynomial also passes through the secret at x = 255 and a digest of it at x = 254.
+// Returns the share values and an error if the random generation fails.
+func splitSecret(threshold, count int, secret []byte) ([][]byte, error) {
+	values := make([][]byte, count)
+	if threshold == 1 {
+		for i := range values {
+			values[i] = append([]byte{}, secret...)
+		}
+		return values, nil
+	}
+
+	randomCount := threshold - 2
+	xs := make([]byte, 0, threshold)
+	ys := make([][]byte, 0, threshold)
+	for i := range randomCount {
+		value, err := random(len(secret))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		xs = append(xs, byte(i))
+		ys = append(ys, value)
+	}
+
+	randomPart, err := random(len(secret) - digestLen)
+	if err != nil {
+		return nil, err
+	}
+	xs = append(xs, digestIndex, secretIndex)
+	ys = append(ys, append(secretDigest(randomPart, secret), randomPart...), secret)
+
+	for i := randomCount; i < count; i++ {
+		if values[i], err = sss.Interpolate(xs, ys, byte(i)); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// recoverSecret: recovers a secret from threshold shares and checks its digest.
+// Returns the secret and an error if the shares are invalid or the digest does not match.
+func recoverSecret(threshold int, xs []byte, ys [][]byte) ([]byte, error) {
+	if threshold == 1 {
+		return ys[0], nil
+	}
+
+	secret, err := sss.Interpolate(xs, ys, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+	digestShare, err := sss.Interpolate(xs, ys, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(digestShare[:digestLen], secretDigest(digestShare[digestLen:], secret)) {
+		return nil, errors.New("invalid digest of the shared secret")
+	}
+	return secret, nil
+}
+
+// validatePassphrase: checks that the passphrase only contains printable ASCII characters.
+// Returns an error if it does not.
+func validatePassphrase(passphrase []byte) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return errors.New("passphrase must only contain printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+// GenerateMnemonics: splits a master secret into SLIP-0039 mnemonics with two-level thresholds.
+// The master secret is encrypted with the passphrase, split between the groups, and each group secret is split between its members.
+// Returns one list of mnemonics per group and an error if the parameters are invalid or the random generation fails.
+func GenerateMnemonics(groupThreshold int, groups []Group, masterSecret, passphrase []byte, extendable bool, iterationExponent int) ([][]string, error) {
+	if len(masterSecret) < minSecretBytes {
+		return nil, errors.New("master secret must be at least " + strconv.Itoa(minSecretBytes*8) + " bits")
+	}
+	if len(masterSecret)%2 != 0 {
+		return nil, errors.New("master secret length must be an even number of bytes")
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if iterationExponent < 0 || iterationExponent > MaxIterationExponent {
+		return nil, errors.New("iteration exponent must be between 0 and " + strconv.Itoa(MaxIterationExponent))
+	}
+	if len(groups) < 1 || len(groups) > maxShareCount {
+		return nil, errors.New("number of groups must be between 1 and " + strconv.Itoa(maxShareCount))
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, errors.New("group threshold must be between 1 and the number of groups")
+	}
+	for i, group := range groups {
+		if group.Count < 1 || group.Count > maxShareCount {
+			return nil, errors.New("group " + strconv.Itoa(i) + " must have between 1 and " + strconv.Itoa(maxShareCount) + " members")
+		}
+		if group.Threshold < 1 || group.Threshold > group.Count {
+			return nil, errors.New("group " + strconv.Itoa(i) + " threshold must be between 1 and its member count")
+		}
+		if group.Threshold == 1 && group.Count > 1 {
+			return nil, errors.New("group " + strconv.Itoa(i) + " has several members with threshold 1, use a 1-of-1 group instead")
+		}
+	}
+
+	idBytes, err := random(2)
+	if err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(idBytes) & (1<<identifierBits - 1)
+
+	encrypted := encrypt(masterSecret, passphrase, iterationExponent, identifier, extendable)
+	groupSecrets, err := splitSecret(groupThreshold, len(groups), encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(groups))
+	for groupIndex, group := range groups {
+		memberValues, err := splitSecret(group.Threshold, group.Count, groupSecrets[groupIndex])
+		if err != nil {
+			return nil, err
+		}
+
+		for memberIndex, value := range memberValues {
+			share := Share{
+				Identifier:        identifier,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        groupIndex,
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       memberIndex,
+				MemberThreshold:   group.Threshold,
+				Value:             value,
+			}
+			mnemonics[groupIndex] = append(mnemonics[groupIndex], share.Mnemonic())
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// memberGroup: struct to collect the shares of one group during reconstruction.
+type memberGroup struct {
+	threshold int
+	shares    map[int][]byte
+}
+
+// CombineMnemonics: reconstructs a master secret from SLIP-0039 mnemonics and the passphrase.
+// Exactly group-threshold groups must be present, each with exactly its member threshold of shares.
+// Any passphrase decrypts to some master secret; SLIP-0039 gives no way to detect a wrong one.
+// Returns the master secret and an error if a mnemonic is invalid or the set does not satisfy the thresholds.
+func CombineMnemonics(mnemonics []string, passphrase []byte) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, errors.New("at least 1 mnemonic is required")
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	var first *Share
+	groups := make(map[int]*memberGroup)
+	for _, mnemonic := range mnemonics {
+		share, err := ParseShare(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			first = share
+		} else if share.Identifier != first.Identifier || share.Extendable != first.Extendable ||
+			share.IterationExponent != first.IterationExponent {
+			return nil, errors.New("mnemonics belong to different master secrets")
+		} else if share.GroupThreshold != first.GroupThreshold || share.GroupCount != first.GroupCount {
+			return nil, errors.New("mnemonics have different group parameters")
+		}
+		if len(share.Value) != len(first.Value) {
+			return nil, errors.New("mnemonics must all have the same length")
+		}
+
+		group, ok := groups[share.GroupIndex]
+		if !ok {
+			group = &memberGroup{threshold: share.MemberThreshold, shares: make(map[int][]byte)}
+			groups[share.GroupIndex] = group
+		}
+		if share.MemberThreshold != group.threshold {
+			return nil, errors.New("mnemonics in group " + strconv.Itoa(share.GroupIndex) + " have different member thresholds")
+		}
+		if prev, ok := group.shares[share.MemberIndex]; ok && !bytes.Equal(prev, share.Value) {
+			return nil, errors.New("mnemonics in group " + strconv.Itoa(share.GroupIndex) + " share a member index")
+		}
+		group.shares[share.MemberIndex] = share.Value
+	}
+
+	if len(first.Value) < minSecretBytes || len(first.Value)%2 != 0 {
+		return nil, errors.New("invalid master secret length")
+	}
+	if len(groups) < first.GroupThreshold {
+		return nil, errors.New("insufficient mnemonic groups: need " + strconv.Itoa(first.GroupThreshold) + ", have " + strconv.Itoa(len(groups)))
+	}
+	if len(groups) != first.GroupThreshold {
+		return nil, errors.New("wrong number of mnemonic groups: expected " + strconv.Itoa(first.GroupThreshold) + ", have " + strconv.Itoa(len(groups)))
+	}
+
+	groupIndices := make([]int, 0, len(groups))
+	for index := range groups {
+		groupIndices = append(groupIndices, index)
+	}
+	sort.Ints(groupIndices)
+
+	groupXs := make([]byte, 0, len(groups))
+	groupYs := make([][]byte, 0, len(groups))
+	for _, groupIndex := range groupIndices {
+		group := groups[groupIndex]
+		if len(group.shares) != group.threshold {
+			return nil, errors.New("wrong number of mnemonics in group " + strconv.Itoa(groupIndex) +
+				": expected " + strconv.Itoa(group.threshold) + ", have " + strconv.Itoa(len(group.shares)))
+		}
+
+		xs := make([]byte, 0, len(group.shares))
+		ys := make([][]byte, 0, len(group.shares))
+		for memberIndex, value := range group.shares {
+			xs = append(xs, byte(memberIndex))
+			ys = append(ys, value)
+		}
+
+		secret, err := recoverSecret(group.threshold, xs, ys)
+		if err != nil {
+			return nil, err
+		}
+		groupXs = append(groupXs, byte(groupIndex))
+		groupYs = append(groupYs, secret)
+	}
+
+	encrypted, err := recoverSecret(first.GroupThreshold, groupXs, groupYs)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(encrypted, passphrase, first.IterationExponent, first.Identifier, first.Extendable), nil
+}
@@ -0,0 +1,27 @@
+package slip39
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// wordCount: number of words in the SLIP-0039 wordlist, one per 10-bit value.
+const wordCount int = 1 << radixBits
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+// wordlist: SLIP-0039 words in index order.
+var wordlist = strings.Fields(wordlistFile)
+
+// wordIndex: index of every word of the wordlist.
+var wordIndex = make(map[string]int, wordCount)
+
+func init() {
+	if len(wordlist) != wordCount {
+		panic("slip39: wordlist must contain 1024 words")
+	}
+	for i, word := range wordlist {
+		wordIndex[word] = i
+	}
+}
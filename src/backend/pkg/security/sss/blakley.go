@@ -0,0 +1,403 @@
+package sss
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"filippo.io/bigmod"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Hyperplane: struct to hold a Blakley share.
+// The share is the hyperplane Coeffs[0]*x_1 + ... + Coeffs[k-1]*x_k = Const in GF(p)^k,
+// which passes through the point whose first coordinate is the secret.
+type Hyperplane struct {
+	Prime  *big.Int   `json:"prime" bson:"prime"`   // modulus of the field
+	Index  int        `json:"index" bson:"index"`   // share number, never 0
+	Coeffs []*big.Int `json:"coeffs" bson:"coeffs"` // normal vector of the hyperplane, one coefficient per dimension
+	Const  *big.Int   `json:"const" bson:"const"`   // right-hand side of the hyperplane equation
+}
+
+// hyperplaneDocument: BSON form of a Hyperplane.
+// BSON has no big integer type, so they are stored as hexadecimal strings as in primeShareDocument.
+type hyperplaneDocument struct {
+	Prime  string   `bson:"prime"`
+	Index  int      `bson:"index"`
+	Coeffs []string `bson:"coeffs"`
+	Const  string   `bson:"const"`
+}
+
+// bigToHex: encodes a big integer as a hexadecimal string, nil as the empty string.
+// Returns the string.
+func bigToHex(x *big.Int) string {
+	if x == nil {
+		return ""
+	}
+	return x.Text(16)
+}
+
+// hexToBig: decodes a hexadecimal string written by bigToHex, the empty string as nil.
+// Returns the big integer and an error if the string is not hexadecimal.
+func hexToBig(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		return nil, errors.New("invalid hexadecimal integer in share document")
+	}
+	return x, nil
+}
+
+// MarshalBSON: serializes the share to BSON, allowing it to be stored through the mongo client.
+// Returns the BSON bytes and an error if the encoding fails.
+func (h Hyperplane) MarshalBSON() ([]byte, error) {
+	doc := hyperplaneDocument{
+		Prime:  bigToHex(h.Prime),
+		Index:  h.Index,
+		Coeffs: make([]string, len(h.Coeffs)),
+		Const:  bigToHex(h.Const),
+	}
+	for i, c := range h.Coeffs {
+		doc.Coeffs[i] = bigToHex(c)
+	}
+	return bson.Marshal(doc)
+}
+
+// UnmarshalBSON: deserializes the share from BSON.
+// Returns an error if the BSON or one of its integers is malformed.
+func (h *Hyperplane) UnmarshalBSON(data []byte) error {
+	var doc hyperplaneDocument
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	prime, err := hexToBig(doc.Prime)
+	if err != nil {
+		return err
+	}
+	constant, err := hexToBig(doc.Const)
+	if err != nil {
+		return err
+	}
+	coeffs := make([]*big.Int, len(doc.Coeffs))
+	for i, c := range doc.Coeffs {
+		if coeffs[i], err = hexToBig(c); err != nil {
+			return err
+		}
+	}
+
+	h.Prime, h.Index, h.Coeffs, h.Const = prime, doc.Index, coeffs, constant
+	return nil
+}
+
+// SplitBlakley: splits a secret field element into n hyperplanes in GF(prime)^k, any k of which meet in a single point.
+// The secret is the first coordinate of a point whose other coordinates are random,
+// and every share is a random hyperplane through that point.
+// Any k random hyperplanes are independent except with probability about k/prime, so the prime should be large.
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func SplitBlakley(secret *big.Int, n, k int, prime *big.Int) ([]Hyperplane, error) {
+	if err := validatePrime(prime); err != nil {
+		return nil, err
+	}
+	if !inField(secret, prime) {
+		return nil, errors.New("secret must be an element of the field")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+
+	p := new(big.Int).Set(prime)
+	f, err := newPrimeField(p)
+	if err != nil {
+		return nil, err
+	}
+
+	point := make([]*bigmod.Nat, k)
+	if point[0], err = f.element(secret); err != nil {
+		return nil, err
+	}
+	for j := 1; j < k; j++ {
+		coord, err := randomFieldElement(p)
+		if err != nil {
+			return nil, err
+		}
+		if point[j], err = f.element(coord); err != nil {
+			return nil, err
+		}
+	}
+
+	shares := make([]Hyperplane, n)
+	for i := range shares {
+		coeffs := make([]*big.Int, k)
+		for j := range coeffs {
+			coeff, err := randomFieldElement(p)
+			if err != nil {
+				return nil, err
+			}
+			coeffs[j] = coeff
+		}
+		normal, err := f.elements(coeffs)
+		if err != nil {
+			return nil, err
+		}
+
+		shares[i] = Hyperplane{
+			Prime:  p,
+			Index:  i + 1,
+			Coeffs: coeffs,
+			Const:  f.toInt(f.dot(normal, point)),
+		}
+	}
+
+	return shares, nil
+}
+
+// inField: checks whether v is an element of GF(p).
+// Returns true if 0 <= v < p.
+func inField(v, p *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.Cmp(p) < 0
+}
+
+// validateHyperplanes: checks that the hyperplanes live in the same space and can be intersected.
+// Returns the dimension and an error if the shares are malformed.
+func validateHyperplanes(shares []Hyperplane) (int, error) {
+	if len(shares) == 0 {
+		return 0, errors.New("at least 1 share is required")
+	}
+
+	prime := shares[0].Prime
+	if err := validatePrime(prime); err != nil {
+		return 0, err
+	}
+
+	k := len(shares[0].Coeffs)
+	if k < 2 {
+		return 0, errors.New("hyperplanes must have at least 2 dimensions")
+	}
+	if len(shares) < k {
+		return 0, errors.New("not enough shares: have " + strconv.Itoa(len(shares)) + ", need " + strconv.Itoa(k))
+	}
+
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.Prime == nil || share.Prime.Cmp(prime) != 0 {
+			return 0, errors.New("shares must all use the same prime")
+		}
+		if share.Index <= 0 {
+			return 0, errors.New("share index must be positive")
+		}
+		if seen[share.Index] {
+			return 0, errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+
+		if len(share.Coeffs) != k {
+			return 0, errors.New("shares must all have the same dimension")
+		}
+		for _, v := range share.Coeffs {
+			if !inField(v, prime) {
+				return 0, errors.New("hyperplane coefficient is outside the field")
+			}
+		}
+		if !inField(share.Const, prime) {
+			return 0, errors.New("hyperplane constant is outside the field")
+		}
+	}
+
+	return k, nil
+}
+
+// intersect: computes the intersection point of the first k hyperplanes by Gaussian elimination in the field.
+// Returns the point and an error if the hyperplanes do not meet in a single point.
+func intersect(f *primeField, shares []Hyperplane, k int) ([]*bigmod.Nat, error) {
+	a := make([][]*bigmod.Nat, k)
+	b := make([]*bigmod.Nat, k)
+	for i := 0; i < k; i++ {
+		row, err := f.elements(shares[i].Coeffs)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = row
+		if b[i], err = f.element(shares[i].Const); err != nil {
+			return nil, err
+		}
+	}
+
+	point, err := f.solve(a, b)
+	if err != nil {
+		return nil, errors.New("hyperplanes are not in general position: " + err.Error())
+	}
+	return point, nil
+}
+
+// CombineBlakley: reconstructs a secret from k hyperplanes by solving the linear system they form modulo p.
+// Only the first k shares are used.
+// Returns the secret and an error if the shares are malformed or do not meet in a single point.
+func CombineBlakley(shares []Hyperplane) (*big.Int, error) {
+	k, err := validateHyperplanes(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := newPrimeField(shares[0].Prime)
+	if err != nil {
+		return nil, err
+	}
+
+	point, err := intersect(f, shares, k)
+	if err != nil {
+		return nil, err
+	}
+	return f.toInt(point[0]), nil
+}
+
+// blakleyScheme: Scheme adapter for the Blakley implementation.
+type blakleyScheme struct {
+	prime string // name of the shipped prime the scheme works over
+}
+
+// modulus: returns the prime the scheme works over.
+func (s *blakleyScheme) modulus() *big.Int {
+	return namedPrimes[s.prime]
+}
+
+// Name: returns the registry name of the scheme.
+func (s *blakleyScheme) Name() string {
+	return "blakley"
+}
+
+// Params: returns the public parameters of the scheme.
+func (s *blakleyScheme) Params() SchemeParams {
+	p := s.modulus()
+	return SchemeParams{
+		Field:        "GF(p)^k " + s.prime,
+		MaxShares:    MaxShares,
+		MaxSecretLen: (p.BitLen()-1)/8 - 1,
+	}
+}
+
+// Split: splits a secret into n hyperplanes with threshold k.
+// The payload holds the k coefficients followed by the constant, each as a fixed-width big-endian integer.
+// Returns the shares and an error if the secret is too long or the split fails.
+func (s *blakleyScheme) Split(secret []byte, n, k int) ([]SchemeShare, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if len(secret) > s.Params().MaxSecretLen {
+		return nil, errors.New("secret is too long for the field")
+	}
+	if n > MaxShares {
+		return nil, errors.New("number of shares cannot exceed 255")
+	}
+
+	p := s.modulus()
+	shares, err := SplitBlakley(secretToInt(secret), n, k, p)
+	if err != nil {
+		return nil, err
+	}
+
+	width := (p.BitLen() + 7) / 8
+	schemeShares := make([]SchemeShare, len(shares))
+	for i, share := range shares {
+		payload := make([]byte, 0, (k+1)*width)
+		for _, v := range share.Coeffs {
+			payload = append(payload, v.FillBytes(make([]byte, width))...)
+		}
+		payload = append(payload, share.Const.FillBytes(make([]byte, width))...)
+
+		schemeShares[i] = SchemeShare{
+			Scheme:    Blakley,
+			Threshold: k,
+			Index:     share.Index,
+			Payload:   payload,
+		}
+	}
+
+	return schemeShares, nil
+}
+
+// shares: converts scheme shares back to hyperplanes.
+// Returns the hyperplanes and an error if they are malformed.
+func (s *blakleyScheme) shares(schemeShares []SchemeShare) ([]Hyperplane, error) {
+	if err := checkSchemeShares(Blakley, schemeShares, MaxShares); err != nil {
+		return nil, err
+	}
+
+	p := s.modulus()
+	k := schemeShares[0].Threshold
+	width := (p.BitLen() + 7) / 8
+
+	shares := make([]Hyperplane, len(schemeShares))
+	for i, schemeShare := range schemeShares {
+		if len(schemeShare.Payload) != (k+1)*width {
+			return nil, errors.New("invalid hyperplane payload length")
+		}
+
+		values := make([]*big.Int, k+1)
+		for j := range values {
+			values[j] = new(big.Int).SetBytes(schemeShare.Payload[j*width : (j+1)*width])
+		}
+		shares[i] = Hyperplane{Prime: p, Index: schemeShare.Index, Coeffs: values[:k], Const: values[k]}
+	}
+
+	if _, err := validateHyperplanes(shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// Combine: reconstructs a secret from the first threshold hyperplanes.
+// Returns the secret and an error if the shares are malformed or do not reconstruct a valid secret.
+func (s *blakleyScheme) Combine(schemeShares []SchemeShare) ([]byte, error) {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := CombineBlakley(shares)
+	if err != nil {
+		return nil, err
+	}
+	return intToSecret(value)
+}
+
+// Verify: checks that the hyperplanes are well-formed and that any extra ones pass through the same point.
+// Returns an error if the verification fails.
+func (s *blakleyScheme) Verify(schemeShares []SchemeShare) error {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return err
+	}
+
+	f, err := newPrimeField(s.modulus())
+	if err != nil {
+		return err
+	}
+
+	k := schemeShares[0].Threshold
+	point, err := intersect(f, shares, k)
+	if err != nil {
+		return err
+	}
+
+	for _, share := range shares[k:] {
+		normal, err := f.elements(share.Coeffs)
+		if err != nil {
+			return err
+		}
+		constant, err := f.element(share.Const)
+		if err != nil {
+			return err
+		}
+		if f.dot(normal, point).Equal(constant) == 0 {
+			return errors.New("inconsistent shares: share " + strconv.Itoa(share.Index) + " does not match the others")
+		}
+	}
+
+	return nil
+}
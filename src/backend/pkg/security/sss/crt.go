@@ -0,0 +1,654 @@
+package sss
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+
+	"filippo.io/bigmod"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// minModulusBits: smallest bit length accepted for generated CRT moduli.
+const minModulusBits int = 16
+
+// CRTShare: struct to hold a share of a Chinese-Remainder-Theorem scheme.
+// The secret modulus is only set for Asmuth-Bloom shares, where it is the public m0 the secret is reduced by.
+type CRTShare struct {
+	Index         int      `json:"index" bson:"index"`                                       // position of the modulus in the sequence, starting at 1
+	Modulus       *big.Int `json:"modulus" bson:"modulus"`                                   // modulus m_i of the holder
+	Residue       *big.Int `json:"residue" bson:"residue"`                                   // secret (or masked secret) modulo m_i
+	SecretModulus *big.Int `json:"secret_modulus,omitempty" bson:"secret_modulus,omitempty"` // public modulus m0 for Asmuth-Bloom, nil for Mignotte
+}
+
+// crtShareDocument: BSON form of a CRTShare, with the big integers as hexadecimal strings.
+type crtShareDocument struct {
+	Index         int    `bson:"index"`
+	Modulus       string `bson:"modulus"`
+	Residue       string `bson:"residue"`
+	SecretModulus string `bson:"secret_modulus,omitempty"`
+}
+
+// MarshalBSON: serializes the share to BSON, allowing it to be stored through the mongo client.
+// Returns the BSON bytes and an error if the encoding fails.
+func (s CRTShare) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(crtShareDocument{
+		Index:         s.Index,
+		Modulus:       bigToHex(s.Modulus),
+		Residue:       bigToHex(s.Residue),
+		SecretModulus: bigToHex(s.SecretModulus),
+	})
+}
+
+// UnmarshalBSON: deserializes the share from BSON.
+// Returns an error if the BSON or one of its integers is malformed.
+func (s *CRTShare) UnmarshalBSON(data []byte) error {
+	var doc crtShareDocument
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	modulus, err := hexToBig(doc.Modulus)
+	if err != nil {
+		return err
+	}
+	residue, err := hexToBig(doc.Residue)
+	if err != nil {
+		return err
+	}
+	secretModulus, err := hexToBig(doc.SecretModulus)
+	if err != nil {
+		return err
+	}
+
+	s.Index, s.Modulus, s.Residue, s.SecretModulus = doc.Index, modulus, residue, secretModulus
+	return nil
+}
+
+// nextPrime: finds the smallest prime greater than or equal to start.
+// Returns the prime.
+func nextPrime(start *big.Int) *big.Int {
+	p := new(big.Int).Set(start)
+	if p.Cmp(big.NewInt(2)) <= 0 {
+		return big.NewInt(2)
+	}
+	if p.Bit(0) == 0 {
+		p.Add(p, big.NewInt(1))
+	}
+	for !p.ProbablyPrime(primeCertainty) {
+		p.Add(p, big.NewInt(2))
+	}
+	return p
+}
+
+// primeSequence: generates n increasing primes of exactly bits bits starting at a random point.
+// The primes are consecutive, so their ratio stays close to 1 which is what both CRT conditions rely on.
+// Returns the primes and an error if the random generation fails.
+func primeSequence(n, bits int) ([]*big.Int, error) {
+	offset, err := rand.Int(entropy.Default(), new(big.Int).Lsh(big.NewInt(1), uint(bits-2)))
+	if err != nil {
+		return nil, err
+	}
+
+	start := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	start.Add(start, offset)
+
+	primes := make([]*big.Int, n)
+	for i := range primes {
+		primes[i] = nextPrime(start)
+		start = new(big.Int).Add(primes[i], big.NewInt(1))
+	}
+
+	if primes[n-1].BitLen() != bits {
+		return nil, errors.New("could not find enough primes of " + strconv.Itoa(bits) + " bits")
+	}
+	return primes, nil
+}
+
+// product: multiplies a list of integers.
+// Returns the product, 1 for an empty list.
+func product(values []*big.Int) *big.Int {
+	prod := big.NewInt(1)
+	for _, v := range values {
+		prod.Mul(prod, v)
+	}
+	return prod
+}
+
+// validateCRTParams: checks the share count and threshold of a CRT split.
+// Returns an error if the parameters are invalid.
+func validateCRTParams(n, k int) error {
+	if k < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return errors.New("number of shares cannot be less than the threshold")
+	}
+	return nil
+}
+
+// validateModuli: checks that the moduli are strictly increasing, greater than 1 and pairwise coprime.
+// Returns an error if the sequence is invalid.
+func validateModuli(moduli []*big.Int) error {
+	gcd := new(big.Int)
+	for i, m := range moduli {
+		if m == nil || m.Cmp(big.NewInt(1)) <= 0 {
+			return errors.New("moduli must be greater than 1")
+		}
+		if i > 0 && m.Cmp(moduli[i-1]) <= 0 {
+			return errors.New("moduli must be strictly increasing")
+		}
+		for _, prev := range moduli[:i] {
+			if gcd.GCD(nil, nil, m, prev).Cmp(big.NewInt(1)) != 0 {
+				return errors.New("moduli must be pairwise coprime")
+			}
+		}
+	}
+	return nil
+}
+
+// mignotteBounds: computes the range secrets must fall in for a (k, n) Mignotte sequence.
+// Alpha is the product of the k smallest moduli, beta the product of the k-1 largest ones.
+// Returns alpha and beta.
+func mignotteBounds(moduli []*big.Int, k int) (*big.Int, *big.Int) {
+	return product(moduli[:k]), product(moduli[len(moduli)-k+1:])
+}
+
+// MignotteSequence: generates a (k, n) Mignotte sequence of bits-bit primes.
+// The product of any k moduli exceeds the product of any k-1 of them.
+// Returns the moduli and an error if the parameters are invalid or generation fails.
+func MignotteSequence(n, k, bits int) ([]*big.Int, error) {
+	if err := validateCRTParams(n, k); err != nil {
+		return nil, err
+	}
+	if bits < minModulusBits {
+		return nil, errors.New("moduli must have at least " + strconv.Itoa(minModulusBits) + " bits")
+	}
+
+	moduli, err := primeSequence(n, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	alpha, beta := mignotteBounds(moduli, k)
+	if alpha.Cmp(beta) <= 0 {
+		return nil, errors.New("generated moduli do not form a Mignotte sequence")
+	}
+	return moduli, nil
+}
+
+// SplitMignotte: splits a secret into residues modulo a (k, n) Mignotte sequence.
+// The secret must lie strictly between the product of the k-1 largest and the k smallest moduli.
+// Returns the shares and an error if the sequence or secret is invalid.
+func SplitMignotte(secret *big.Int, moduli []*big.Int, k int) ([]CRTShare, error) {
+	if err := validateCRTParams(len(moduli), k); err != nil {
+		return nil, err
+	}
+	if err := validateModuli(moduli); err != nil {
+		return nil, err
+	}
+
+	alpha, beta := mignotteBounds(moduli, k)
+	if alpha.Cmp(beta) <= 0 {
+		return nil, errors.New("moduli do not form a Mignotte sequence")
+	}
+	if secret == nil || secret.Cmp(beta) <= 0 || secret.Cmp(alpha) >= 0 {
+		return nil, errors.New("secret must lie between the Mignotte bounds")
+	}
+
+	bound, err := bigmod.NewModulus(alpha.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	value, err := crtElement(secret, bound)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]CRTShare, len(moduli))
+	for i, m := range moduli {
+		residue, err := reduce(value, m)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = CRTShare{
+			Index:   i + 1,
+			Modulus: new(big.Int).Set(m),
+			Residue: residue,
+		}
+	}
+
+	return shares, nil
+}
+
+// AsmuthBloomSequence: generates the public modulus m0 and a (k, n) Asmuth-Bloom sequence for secrets below 2^secretBits.
+// The product of the k smallest moduli exceeds m0 times the product of the k-1 largest ones.
+// Returns m0, the moduli and an error if the parameters are invalid or generation fails.
+func AsmuthBloomSequence(n, k, secretBits int) (*big.Int, []*big.Int, error) {
+	if err := validateCRTParams(n, k); err != nil {
+		return nil, nil, err
+	}
+	if secretBits < 1 {
+		return nil, nil, errors.New("secret size must be at least 1 bit")
+	}
+
+	m0 := nextPrime(new(big.Int).Lsh(big.NewInt(1), uint(secretBits)))
+
+	// b-bit moduli give k(b-1) >= bits(m0) + (k-1)b as soon as b >= bits(m0) + k
+	bits := max(m0.BitLen()+k, minModulusBits)
+	moduli, err := primeSequence(n, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkAsmuthBloom(m0, moduli, k); err != nil {
+		return nil, nil, err
+	}
+	return m0, moduli, nil
+}
+
+// checkAsmuthBloom: checks the Asmuth-Bloom condition on m0 and the moduli.
+// Returns an error if the condition does not hold.
+func checkAsmuthBloom(m0 *big.Int, moduli []*big.Int, k int) error {
+	if m0 == nil || m0.Cmp(big.NewInt(1)) <= 0 {
+		return errors.New("secret modulus must be greater than 1")
+	}
+	if err := validateModuli(append([]*big.Int{m0}, moduli...)); err != nil {
+		return err
+	}
+
+	bound := new(big.Int).Mul(m0, product(moduli[len(moduli)-k+1:]))
+	if product(moduli[:k]).Cmp(bound) <= 0 {
+		return errors.New("moduli do not form an Asmuth-Bloom sequence")
+	}
+	return nil
+}
+
+// SplitAsmuthBloom: splits a secret below m0 into residues modulo a (k, n) Asmuth-Bloom sequence.
+// The secret is masked as y = secret + a*m0 with a random so that y stays below the product M of the k smallest moduli.
+// Returns the shares and an error if the sequence or secret is invalid or the random generation fails.
+func SplitAsmuthBloom(secret, m0 *big.Int, moduli []*big.Int, k int) ([]CRTShare, error) {
+	if err := validateCRTParams(len(moduli), k); err != nil {
+		return nil, err
+	}
+	if err := checkAsmuthBloom(m0, moduli, k); err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Sign() < 0 || secret.Cmp(m0) >= 0 {
+		return nil, errors.New("secret must be smaller than the secret modulus")
+	}
+
+	// a is drawn from [0, M / m0), which keeps y = secret + a*m0 below M for any secret below m0
+	// without the range depending on the secret
+	prod := product(moduli[:k])
+	a, err := rand.Int(entropy.Default(), new(big.Int).Div(prod, m0))
+	if err != nil {
+		return nil, err
+	}
+
+	bound, err := bigmod.NewModulus(prod.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	y, err := crtElement(a, bound)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := crtElement(m0, bound)
+	if err != nil {
+		return nil, err
+	}
+	value, err := crtElement(secret, bound)
+	if err != nil {
+		return nil, err
+	}
+	y.Mul(mask, bound).Add(value, bound)
+
+	shares := make([]CRTShare, len(moduli))
+	for i, m := range moduli {
+		residue, err := reduce(y, m)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = CRTShare{
+			Index:         i + 1,
+			Modulus:       new(big.Int).Set(m),
+			Residue:       residue,
+			SecretModulus: new(big.Int).Set(m0),
+		}
+	}
+
+	return shares, nil
+}
+
+// The moduli of CRT shares are public, but the residues and the values they combine into are secret: those are only
+// handled as fixed-size integers modulo a public bound (filippo.io/bigmod), as in the prime-field arithmetic.
+
+// crtElement: converts an integer below the modulus m to a fixed-size integer modulo m.
+// Returns the value and an error if the integer is out of range.
+func crtElement(v *big.Int, m *bigmod.Modulus) (*bigmod.Nat, error) {
+	if v == nil || v.Sign() < 0 || v.BitLen() > m.BitLen() {
+		return nil, errors.New("value is out of range for its modulus")
+	}
+	x, err := bigmod.NewNat().SetBytes(v.FillBytes(make([]byte, m.Size())), m)
+	if err != nil {
+		return nil, errors.New("value is out of range for its modulus")
+	}
+	return x, nil
+}
+
+// reduce: reduces a fixed-size integer modulo a public modulus greater than 1.
+// Returns the residue and an error if the modulus is invalid.
+func reduce(x *bigmod.Nat, modulus *big.Int) (*big.Int, error) {
+	m, err := bigmod.NewModulus(modulus.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bigmod.NewNat().Mod(x, m).Bytes(m)), nil
+}
+
+// crt: solves the system x = residues[i] mod moduli[i] with the Chinese Remainder Theorem.
+// The coefficients (M/m_i) * ((M/m_i)^-1 mod m_i) only depend on the moduli; the residues are combined with them
+// modulo the product M on fixed-size integers.
+// Returns the unique solution below the product of the moduli and an error if the moduli are not pairwise coprime.
+func crt(residues, moduli []*big.Int) (*bigmod.Nat, error) {
+	prod := product(moduli)
+	bound, err := bigmod.NewModulus(prod.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	x := bigmod.NewNat().ExpandFor(bound)
+
+	for i, m := range moduli {
+		partial := new(big.Int).Div(prod, m)
+		inv := new(big.Int).ModInverse(partial, m)
+		if inv == nil {
+			return nil, errors.New("moduli must be pairwise coprime")
+		}
+
+		coeff, err := crtElement(partial.Mul(partial, inv).Mod(partial, prod), bound)
+		if err != nil {
+			return nil, err
+		}
+		term, err := crtElement(residues[i], bound)
+		if err != nil {
+			return nil, err
+		}
+		x.Add(term.Mul(coeff, bound), bound)
+	}
+
+	return x, nil
+}
+
+// crtValue: turns the solution of a CRT system into the shared value, reducing it modulo m0 for Asmuth-Bloom shares.
+// Returns the value and an error if m0 is invalid.
+func crtValue(y *bigmod.Nat, moduli []*big.Int, m0 *big.Int) (*big.Int, error) {
+	if m0 != nil {
+		return reduce(y, m0)
+	}
+	return reduce(y, product(moduli))
+}
+
+// validateCRTShares: checks that the shares come from the same kind of CRT split and are well-formed.
+// Returns an error if the shares are invalid.
+func validateCRTShares(shares []CRTShare) error {
+	if len(shares) < 2 {
+		return errors.New("at least 2 shares are required")
+	}
+
+	m0 := shares[0].SecretModulus
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.Modulus == nil || share.Modulus.Cmp(big.NewInt(1)) <= 0 {
+			return errors.New("share modulus must be greater than 1")
+		}
+		if share.Residue == nil || share.Residue.Sign() < 0 || share.Residue.Cmp(share.Modulus) >= 0 {
+			return errors.New("share residue must be smaller than its modulus")
+		}
+		if (share.SecretModulus == nil) != (m0 == nil) || (m0 != nil && share.SecretModulus.Cmp(m0) != 0) {
+			return errors.New("shares must all use the same secret modulus")
+		}
+		if seen[share.Index] {
+			return errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+	}
+
+	return nil
+}
+
+// CombineCRT: reconstructs a secret from Mignotte or Asmuth-Bloom shares with the Chinese Remainder Theorem.
+// Asmuth-Bloom results are reduced modulo m0. With fewer than k shares the result is meaningless.
+// Returns the secret and an error if the shares are invalid.
+func CombineCRT(shares []CRTShare) (*big.Int, error) {
+	if err := validateCRTShares(shares); err != nil {
+		return nil, err
+	}
+
+	residues := make([]*big.Int, len(shares))
+	moduli := make([]*big.Int, len(shares))
+	for i, share := range shares {
+		residues[i] = share.Residue
+		moduli[i] = share.Modulus
+	}
+
+	y, err := crt(residues, moduli)
+	if err != nil {
+		return nil, err
+	}
+	return crtValue(y, moduli, shares[0].SecretModulus)
+}
+
+// appendInt: appends an integer to a payload, prefixed by its 2-byte big-endian length.
+// Returns the extended payload.
+func appendInt(payload []byte, v *big.Int) []byte {
+	b := v.Bytes()
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(b)))
+	return append(payload, b...)
+}
+
+// readInt: reads a length-prefixed integer from the start of a payload.
+// Returns the integer, the remaining payload and an error if the payload is truncated.
+func readInt(payload []byte) (*big.Int, []byte, error) {
+	if len(payload) < 2 {
+		return nil, nil, errors.New("truncated CRT payload")
+	}
+	size := int(binary.BigEndian.Uint16(payload))
+	payload = payload[2:]
+	if len(payload) < size {
+		return nil, nil, errors.New("truncated CRT payload")
+	}
+	return new(big.Int).SetBytes(payload[:size]), payload[size:], nil
+}
+
+// crtScheme: Scheme adapter for the Mignotte and Asmuth-Bloom implementations.
+type crtScheme struct {
+	id          SchemeID // registry ID, either Mignotte or AsmuthBloom
+	asmuthBloom bool     // whether the secret is masked with m0 as in Asmuth-Bloom
+}
+
+// Name: returns the registry name of the scheme.
+func (s *crtScheme) Name() string {
+	if s.asmuthBloom {
+		return "asmuth-bloom"
+	}
+	return "mignotte"
+}
+
+// Params: returns the public parameters of the scheme.
+func (s *crtScheme) Params() SchemeParams {
+	return SchemeParams{
+		Field:        "Z/m1...mn",
+		MaxShares:    MaxShares,
+		MaxSecretLen: 64,
+	}
+}
+
+// Split: splits a secret into n CRT shares with threshold k, generating a fresh modulus sequence.
+// Mignotte secrets are offset by 2^((k-1)b) for b-bit moduli, which places them between the Mignotte bounds.
+// Returns the shares and an error if the secret is too long or the split fails.
+func (s *crtScheme) Split(secret []byte, n, k int) ([]SchemeShare, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if len(secret) > s.Params().MaxSecretLen {
+		return nil, errors.New("secret is too long for the scheme")
+	}
+	if n > MaxShares {
+		return nil, errors.New("number of shares cannot exceed 255")
+	}
+	if err := validateCRTParams(n, k); err != nil {
+		return nil, err
+	}
+
+	value := secretToInt(secret)
+
+	var shares []CRTShare
+	if s.asmuthBloom {
+		m0, moduli, err := AsmuthBloomSequence(n, k, value.BitLen())
+		if err != nil {
+			return nil, err
+		}
+		if shares, err = SplitAsmuthBloom(value, m0, moduli, k); err != nil {
+			return nil, err
+		}
+	} else {
+		// b-bit moduli give beta < 2^((k-1)b) and alpha >= 2^(k(b-1)), so the offset secret fits once b > k
+		bits := max(k+1, (value.BitLen()+k-2)/(k-1), minModulusBits)
+		moduli, err := MignotteSequence(n, k, bits)
+		if err != nil {
+			return nil, err
+		}
+		offset := new(big.Int).Lsh(big.NewInt(1), uint((k-1)*bits))
+		if shares, err = SplitMignotte(offset.Add(offset, value), moduli, k); err != nil {
+			return nil, err
+		}
+	}
+
+	schemeShares := make([]SchemeShare, len(shares))
+	for i, share := range shares {
+		var payload []byte
+		if s.asmuthBloom {
+			payload = appendInt(payload, share.SecretModulus)
+		}
+		payload = appendInt(payload, share.Modulus)
+		payload = appendInt(payload, share.Residue)
+
+		schemeShares[i] = SchemeShare{
+			Scheme:    s.id,
+			Threshold: k,
+			Index:     share.Index,
+			Payload:   payload,
+		}
+	}
+
+	return schemeShares, nil
+}
+
+// shares: converts scheme shares back to CRT shares.
+// Returns the CRT shares and an error if they are malformed.
+func (s *crtScheme) shares(schemeShares []SchemeShare) ([]CRTShare, error) {
+	if err := checkSchemeShares(s.id, schemeShares, MaxShares); err != nil {
+		return nil, err
+	}
+
+	shares := make([]CRTShare, len(schemeShares))
+	for i, schemeShare := range schemeShares {
+		var (
+			share = CRTShare{Index: schemeShare.Index}
+			rest  = schemeShare.Payload
+			err   error
+		)
+		if s.asmuthBloom {
+			if share.SecretModulus, rest, err = readInt(rest); err != nil {
+				return nil, err
+			}
+		}
+		if share.Modulus, rest, err = readInt(rest); err != nil {
+			return nil, err
+		}
+		if share.Residue, rest, err = readInt(rest); err != nil {
+			return nil, err
+		}
+		if len(rest) != 0 {
+			return nil, errors.New("trailing data in CRT payload")
+		}
+		shares[i] = share
+	}
+
+	if err := validateCRTShares(shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// decode: turns a reconstructed CRT value back into the secret bytes.
+// Returns the secret and an error if the value does not decode.
+func (s *crtScheme) decode(value *big.Int) ([]byte, error) {
+	if !s.asmuthBloom {
+		if value.BitLen() == 0 {
+			return nil, errors.New("reconstructed value is not a valid secret")
+		}
+		value = new(big.Int).SetBit(value, value.BitLen()-1, 0)
+	}
+	return intToSecret(value)
+}
+
+// Combine: reconstructs a secret from the first threshold CRT shares.
+// Returns the secret and an error if the shares are malformed or do not reconstruct a valid secret.
+func (s *crtScheme) Combine(schemeShares []SchemeShare) ([]byte, error) {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := CombineCRT(shares[:schemeShares[0].Threshold])
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(value)
+}
+
+// Verify: checks that the shares are well-formed and that any extra ones agree with the value the first threshold give.
+// Returns an error if the verification fails.
+func (s *crtScheme) Verify(schemeShares []SchemeShare) error {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return err
+	}
+
+	k := schemeShares[0].Threshold
+	residues := make([]*big.Int, k)
+	moduli := make([]*big.Int, k)
+	for i, share := range shares[:k] {
+		residues[i] = share.Residue
+		moduli[i] = share.Modulus
+	}
+
+	y, err := crt(residues, moduli)
+	if err != nil {
+		return err
+	}
+
+	for _, share := range shares[k:] {
+		residue, err := reduce(y, share.Modulus)
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(residue.Bytes(), share.Residue.Bytes()) != 1 {
+			return errors.New("inconsistent shares: share " + strconv.Itoa(share.Index) + " does not match the others")
+		}
+	}
+
+	value, err := crtValue(y, moduli, shares[0].SecretModulus)
+	if err != nil {
+		return err
+	}
+	_, err = s.decode(value)
+	return err
+}
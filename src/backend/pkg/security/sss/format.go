@@ -0,0 +1,241 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// Binary share format, all integers big-endian:
+//
+//	magic "SSSH" | version u8 | flags u8 | scheme u8 | threshold u16 | index u16 | group [16]
+//	| payload length u32 | payload | signature [64] if signed | CRC32C u32
+//
+// The signature covers every byte before it, the checksum every byte before the checksum.
+const (
+	formatMagic   string = "SSSH"
+	FormatVersion byte   = 1
+
+	flagSigned byte = 1 << 0
+
+	headerLen     int = len(formatMagic) + 1 + 1 + 1 + 2 + 2 + GroupIDLen + 4
+	checksumLen   int = 4
+	maxPayloadLen int = 1 << 20
+)
+
+// GroupIDLen: length in bytes of a split's group ID.
+const GroupIDLen int = 16
+
+// ErrGroupMismatch: returned when shares from different splits are combined.
+var ErrGroupMismatch = errors.New("shares belong to different splits")
+
+// ErrInvalidSignature: returned when a share's dealer signature is missing or does not verify.
+var ErrInvalidSignature = errors.New("invalid dealer signature")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// GroupID: random identifier shared by all the shares of one split.
+type GroupID [GroupIDLen]byte
+
+// NewGroupID: generates a random group ID.
+// Returns the group ID and an error if the random generation fails.
+func NewGroupID() (GroupID, error) {
+	var id GroupID
+	err := entropy.Read(id[:])
+	return id, err
+}
+
+// Envelope: struct to hold a scheme share together with the metadata of the self-describing format.
+type Envelope struct {
+	Share     SchemeShare // share produced by a registered scheme
+	Group     GroupID     // identifier of the split the share belongs to
+	Signature []byte      // Ed25519 dealer signature, nil if the share is unsigned
+}
+
+// Seal: wraps the shares of one split in envelopes with a fresh group ID, signing them if a dealer key is given.
+// Returns the envelopes and an error if the random generation fails or a share cannot be encoded.
+func Seal(shares []SchemeShare, dealer ed25519.PrivateKey) ([]Envelope, error) {
+	group, err := NewGroupID()
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes := make([]Envelope, len(shares))
+	for i, share := range shares {
+		envelopes[i] = Envelope{Share: share, Group: group}
+		if dealer != nil {
+			if err := envelopes[i].Sign(dealer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return envelopes, nil
+}
+
+// signedBytes: encodes the header and payload of the envelope, which is the part covered by the signature.
+// Returns the encoding and an error if a field does not fit the format.
+func (e *Envelope) signedBytes(signed bool) ([]byte, error) {
+	share := e.Share
+	if share.Scheme < 0 || share.Scheme > math.MaxUint8 {
+		return nil, errors.New("scheme ID does not fit the share format")
+	}
+	if share.Threshold < 0 || share.Threshold > math.MaxUint16 {
+		return nil, errors.New("threshold does not fit the share format")
+	}
+	if share.Index < 0 || share.Index > math.MaxUint16 {
+		return nil, errors.New("share index does not fit the share format")
+	}
+	if len(share.Payload) > maxPayloadLen {
+		return nil, errors.New("share payload is too large")
+	}
+
+	var flags byte
+	if signed {
+		flags |= flagSigned
+	}
+
+	buf := make([]byte, 0, headerLen+len(share.Payload)+ed25519.SignatureSize+checksumLen)
+	buf = append(buf, formatMagic...)
+	buf = append(buf, FormatVersion, flags, byte(share.Scheme))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(share.Threshold))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(share.Index))
+	buf = append(buf, e.Group[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(share.Payload)))
+	return append(buf, share.Payload...), nil
+}
+
+// Sign: signs the envelope with the dealer's Ed25519 key.
+// Returns an error if the key is invalid or the share does not fit the format.
+func (e *Envelope) Sign(dealer ed25519.PrivateKey) error {
+	if len(dealer) != ed25519.PrivateKeySize {
+		return errors.New("invalid dealer private key")
+	}
+
+	msg, err := e.signedBytes(true)
+	if err != nil {
+		return err
+	}
+	e.Signature = ed25519.Sign(dealer, msg)
+	return nil
+}
+
+// VerifySignature: checks the envelope's signature against the dealer's Ed25519 public key.
+// Returns ErrInvalidSignature if the envelope is unsigned or the signature does not verify.
+func (e *Envelope) VerifySignature(dealer ed25519.PublicKey) error {
+	if len(dealer) != ed25519.PublicKeySize {
+		return errors.New("invalid dealer public key")
+	}
+	if e.Signature == nil {
+		return ErrInvalidSignature
+	}
+
+	msg, err := e.signedBytes(true)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(dealer, msg, e.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MarshalShare: encodes an envelope in the binary share format.
+// Returns the encoding and an error if a field does not fit the format.
+func MarshalShare(e Envelope) ([]byte, error) {
+	signed := e.Signature != nil
+	if signed && len(e.Signature) != ed25519.SignatureSize {
+		return nil, errors.New("invalid signature length: " + strconv.Itoa(len(e.Signature)))
+	}
+
+	buf, err := e.signedBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, e.Signature...)
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli)), nil
+}
+
+// UnmarshalShare: decodes an envelope from the binary share format.
+// The checksum is verified, the signature is only parsed; use VerifySignature or CombineEnvelopes to check it.
+// Returns the envelope and an error if the data is truncated, corrupted or of an unknown version.
+func UnmarshalShare(data []byte) (*Envelope, error) {
+	if len(data) < headerLen+checksumLen {
+		return nil, errors.New("share is too short")
+	}
+	if !bytes.Equal(data[:len(formatMagic)], []byte(formatMagic)) {
+		return nil, errors.New("not an encoded share: bad magic")
+	}
+
+	body, sum := data[:len(data)-checksumLen], data[len(data)-checksumLen:]
+	if crc32.Checksum(body, castagnoli) != binary.BigEndian.Uint32(sum) {
+		return nil, errors.New("share checksum mismatch")
+	}
+
+	pos := len(formatMagic)
+	if version := body[pos]; version != FormatVersion {
+		return nil, errors.New("unsupported share format version: " + strconv.Itoa(int(version)))
+	}
+	flags := body[pos+1]
+	if flags&^flagSigned != 0 {
+		return nil, errors.New("unknown share format flags")
+	}
+
+	e := &Envelope{
+		Share: SchemeShare{
+			Scheme:    SchemeID(body[pos+2]),
+			Threshold: int(binary.BigEndian.Uint16(body[pos+3:])),
+			Index:     int(binary.BigEndian.Uint16(body[pos+5:])),
+		},
+	}
+	pos += 7
+	copy(e.Group[:], body[pos:pos+GroupIDLen])
+	pos += GroupIDLen
+
+	payloadLen := uint64(binary.BigEndian.Uint32(body[pos:]))
+	pos += 4
+
+	sigLen := 0
+	if flags&flagSigned != 0 {
+		sigLen = ed25519.SignatureSize
+	}
+	if payloadLen > uint64(maxPayloadLen) || uint64(len(body)-pos) != payloadLen+uint64(sigLen) {
+		return nil, errors.New("share length does not match its header")
+	}
+
+	e.Share.Payload = bytes.Clone(body[pos : pos+int(payloadLen)])
+	if sigLen > 0 {
+		e.Signature = bytes.Clone(body[pos+int(payloadLen):])
+	}
+	return e, nil
+}
+
+// CombineEnvelopes: reconstructs a secret from enveloped shares after checking they come from the same split.
+// If a dealer key is given, every share must carry a valid signature from it before any interpolation takes place.
+// Returns the secret, ErrGroupMismatch or ErrInvalidSignature if the checks fail, or an error from the scheme.
+func CombineEnvelopes(envelopes []Envelope, dealer ed25519.PublicKey) ([]byte, error) {
+	if len(envelopes) == 0 {
+		return nil, errors.New("at least 1 share is required")
+	}
+
+	shares := make([]SchemeShare, len(envelopes))
+	for i, e := range envelopes {
+		if e.Group != envelopes[0].Group {
+			return nil, ErrGroupMismatch
+		}
+		if dealer != nil {
+			if err := e.VerifySignature(dealer); err != nil {
+				return nil, err
+			}
+		}
+		shares[i] = e.Share
+	}
+
+	return CombineShares(shares)
+}
@@ -0,0 +1,190 @@
+package sss
+
+// Field arithmetic is constant-time: no branch or memory access depends on the operands, so reconstructing a secret
+// on a shared host does not leak share values through cache or branch timing.
+
+// fieldReduction: the low byte of the reduction polynomial x^8 + x^4 + x^3 + x + 1 (the AES polynomial, 0x11b).
+const fieldReduction byte = 0x1b
+
+// gfAdd: adds two elements of GF(2^8).
+// Returns the sum (which is also the difference).
+func gfAdd(a, b byte) byte {
+	return a ^ b
+}
+
+// gfMul: multiplies two elements of GF(2^8) with a shift-and-add loop that masks instead of branching.
+// Returns the product.
+func gfMul(a, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ fieldReduction&-(a>>7)
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv: computes the multiplicative inverse of an element of GF(2^8) as a^254, with a fixed chain of
+// squarings and multiplications.
+// Returns the inverse, or 0 for the zero element.
+func gfInv(a byte) byte {
+	square := gfMul(a, a)
+	inv := square
+	for range 6 {
+		square = gfMul(square, square)
+		inv = gfMul(inv, square)
+	}
+	return inv
+}
+
+// gfDiv: divides a by a non-zero b in GF(2^8).
+// Returns the quotient.
+func gfDiv(a, b byte) byte {
+	return gfMul(a, gfInv(b))
+}
+
+// evaluate: evaluates the polynomial with the given coefficients at x using Horner's rule.
+// coeffs[0] is the constant term.
+// Returns the value of the polynomial at x.
+func evaluate(coeffs []byte, x byte) byte {
+	var y byte
+
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfAdd(gfMul(y, x), coeffs[i])
+	}
+
+	return y
+}
+
+// interpolate: evaluates at x the unique polynomial passing through the points (xs[i], ys[i]).
+// The xs must be distinct.
+// Returns the value of the interpolated polynomial at x.
+func interpolate(xs, ys []byte, x byte) byte {
+	var y byte
+
+	for i, coeff := range lagrangeCoefficients(xs, x) {
+		y = gfAdd(y, gfMul(ys[i], coeff))
+	}
+
+	return y
+}
+
+// lagrangeCoefficients: computes the Lagrange basis polynomials for the distinct points xs evaluated at x.
+// Interpolating at x is then the sum of ys[i] * coeffs[i].
+// Returns one coefficient per point.
+func lagrangeCoefficients(xs []byte, x byte) []byte {
+	coeffs := make([]byte, len(xs))
+
+	for i := range xs {
+		var num, den byte = 1, 1
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num = gfMul(num, gfAdd(x, xs[j]))
+			den = gfMul(den, gfAdd(xs[i], xs[j]))
+		}
+		coeffs[i] = gfDiv(num, den)
+	}
+
+	return coeffs
+}
+
+// solveLinear: solves the linear system a * x = b over GF(2^8) by Gaussian elimination.
+// a is modified in place. Free variables of an under-determined system are set to 0.
+// The matrix may hold share values (as in Berlekamp-Welch decoding), so the pivot is moved into place with masked
+// swaps and every row is eliminated whether or not its entry is zero; only the rank of the system shows in timing.
+// Returns a solution and false if the system is inconsistent.
+func solveLinear(a [][]byte, b []byte) ([]byte, bool) {
+	rows := len(a)
+	if rows == 0 {
+		return nil, true
+	}
+	cols := len(a[0])
+
+	pivotCols := make([]int, 0, cols)
+	row := 0
+	for col := 0; col < cols && row < rows; col++ {
+		// swap the first row at or below row with a non-zero entry in this column into place
+		var found byte
+		for r := row; r < rows; r++ {
+			nonZero := gfNonZeroMask(a[r][col])
+			swap := nonZero &^ found
+			for c := col; c < cols; c++ {
+				d := (a[row][c] ^ a[r][c]) & swap
+				a[row][c] ^= d
+				a[r][c] ^= d
+			}
+			d := (b[row] ^ b[r]) & swap
+			b[row] ^= d
+			b[r] ^= d
+			found |= nonZero
+		}
+		if found == 0 {
+			continue
+		}
+
+		inv := gfInv(a[row][col])
+		for c := col; c < cols; c++ {
+			a[row][c] = gfMul(a[row][c], inv)
+		}
+		b[row] = gfMul(b[row], inv)
+
+		for r := 0; r < rows; r++ {
+			if r == row {
+				continue
+			}
+			factor := a[r][col]
+			for c := col; c < cols; c++ {
+				a[r][c] = gfAdd(a[r][c], gfMul(factor, a[row][c]))
+			}
+			b[r] = gfAdd(b[r], gfMul(factor, b[row]))
+		}
+
+		pivotCols = append(pivotCols, col)
+		row++
+	}
+
+	// remaining rows are all-zero on the left and must be zero on the right
+	var residue byte
+	for r := row; r < rows; r++ {
+		residue |= b[r]
+	}
+	if residue != 0 {
+		return nil, false
+	}
+
+	x := make([]byte, cols)
+	for r, col := range pivotCols {
+		x[col] = b[r]
+	}
+	return x, true
+}
+
+// gfNonZeroMask: computes a mask from a field element without branching.
+// Returns 0xff if a is non-zero and 0x00 otherwise.
+func gfNonZeroMask(a byte) byte {
+	return byte(((uint16(a) + 0xff) >> 8) * 0xff)
+}
+
+// polyDivide: divides the polynomial num by den, both with the constant term first.
+// The leading coefficient of den must be non-zero.
+// Returns the quotient and the remainder.
+func polyDivide(num, den []byte) ([]byte, []byte) {
+	remainder := append([]byte{}, num...)
+	if len(num) < len(den) {
+		return []byte{}, remainder
+	}
+
+	quotient := make([]byte, len(num)-len(den)+1)
+	leadInv := gfInv(den[len(den)-1])
+	for i := len(quotient) - 1; i >= 0; i-- {
+		coeff := gfMul(remainder[i+len(den)-1], leadInv)
+		quotient[i] = coeff
+		for j := range den {
+			remainder[i+j] = gfAdd(remainder[i+j], gfMul(coeff, den[j]))
+		}
+	}
+
+	return quotient, remainder[:len(den)-1]
+}
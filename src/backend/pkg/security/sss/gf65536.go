@@ -0,0 +1,284 @@
+package sss
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// GF(2^16) lifts the 255-share limit of GF(2^8) to 65535 shares. Each secret byte is embedded as a field element
+// and shared on its own, so share values are twice the size of the secret. The arithmetic is constant-time,
+// as in GF(2^8); Split and Combine are quadratic in the number of shares, see SplitNTT for very large splits.
+
+// MaxShares16: maximum number of shares supported by GF(2^16), one per non-zero field element.
+const MaxShares16 int = 1<<16 - 1
+
+// fieldReduction16: the low bits of the reduction polynomial x^16 + x^12 + x^3 + x + 1.
+const fieldReduction16 uint16 = 0x100b
+
+// Share16: struct to hold a single share of a secret shared over GF(2^16).
+type Share16 struct {
+	X uint16   `json:"x" bson:"x"` // evaluation point, never 0
+	Y []uint16 `json:"y" bson:"y"` // polynomial values at X, one per secret byte
+}
+
+// gf16Mul: multiplies two elements of GF(2^16) with a shift-and-add loop that masks instead of branching.
+// Returns the product.
+func gf16Mul(a, b uint16) uint16 {
+	var p uint16
+	for range 16 {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ fieldReduction16&-(a>>15)
+		b >>= 1
+	}
+	return p
+}
+
+// gf16Inv: computes the multiplicative inverse of an element of GF(2^16) as a^(2^16 - 2), with a fixed chain of
+// squarings and multiplications.
+// Returns the inverse, or 0 for the zero element.
+func gf16Inv(a uint16) uint16 {
+	square := gf16Mul(a, a)
+	inv := square
+	for range 14 {
+		square = gf16Mul(square, square)
+		inv = gf16Mul(inv, square)
+	}
+	return inv
+}
+
+// lagrangeCoefficients16: computes the Lagrange basis polynomials for the distinct points xs evaluated at x.
+// Returns one coefficient per point.
+func lagrangeCoefficients16(xs []uint16, x uint16) []uint16 {
+	coeffs := make([]uint16, len(xs))
+
+	for i := range xs {
+		var num, den uint16 = 1, 1
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num = gf16Mul(num, x^xs[j])
+			den = gf16Mul(den, xs[i]^xs[j])
+		}
+		coeffs[i] = gf16Mul(num, gf16Inv(den))
+	}
+
+	return coeffs
+}
+
+// Split16: splits a secret into n shares over GF(2^16), any k of which can reconstruct it.
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func Split16(secret []byte, n, k int) ([]Share16, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if n > MaxShares16 {
+		return nil, errors.New("number of shares cannot exceed " + strconv.Itoa(MaxShares16))
+	}
+
+	shares := make([]Share16, n)
+	for i := range shares {
+		shares[i] = Share16{X: uint16(i + 1), Y: make([]uint16, len(secret))}
+	}
+
+	coeffs := make([]uint16, k)
+	for b := range secret {
+		randomBytes, err := random(2 * (k - 1))
+		if err != nil {
+			return nil, err
+		}
+
+		coeffs[0] = uint16(secret[b])
+		for j := 1; j < k; j++ {
+			coeffs[j] = binary.BigEndian.Uint16(randomBytes[2*(j-1):])
+		}
+
+		for i := range shares {
+			var y uint16
+			for j := k - 1; j >= 0; j-- {
+				y = gf16Mul(y, shares[i].X) ^ coeffs[j]
+			}
+			shares[i].Y[b] = y
+		}
+	}
+
+	return shares, nil
+}
+
+// validateShares16: checks that the shares can be interpolated together.
+// Returns an error if there are too few shares, their lengths differ, or their X values are zero or duplicated.
+func validateShares16(shares []Share16) error {
+	if len(shares) < 2 {
+		return errors.New("at least 2 shares are required")
+	}
+
+	seen := make(map[uint16]bool, len(shares))
+	for _, share := range shares {
+		if share.X == 0 {
+			return errors.New("share X value cannot be 0")
+		}
+		if seen[share.X] {
+			return errors.New("duplicate share X value: " + strconv.Itoa(int(share.X)))
+		}
+		seen[share.X] = true
+
+		if len(share.Y) == 0 {
+			return errors.New("share Y value cannot be empty")
+		}
+		if len(share.Y) != len(shares[0].Y) {
+			return errors.New("shares must all have the same length")
+		}
+	}
+
+	return nil
+}
+
+// Combine16: reconstructs a secret from its GF(2^16) shares using Lagrange interpolation at 0.
+// The caller must supply at least as many shares as the threshold used at split time.
+// Returns the secret and an error if the shares are malformed or do not interpolate to bytes,
+// which happens with too few shares or shares of different splits.
+func Combine16(shares []Share16) ([]byte, error) {
+	if err := validateShares16(shares); err != nil {
+		return nil, err
+	}
+
+	xs := make([]uint16, len(shares))
+	for i, share := range shares {
+		xs[i] = share.X
+	}
+
+	coeffs := lagrangeCoefficients16(xs, 0)
+	secret := make([]byte, len(shares[0].Y))
+	var overflow uint16
+	for b := range secret {
+		var y uint16
+		for i, share := range shares {
+			y ^= gf16Mul(share.Y[b], coeffs[i])
+		}
+		overflow |= y >> 8
+		secret[b] = byte(y)
+	}
+
+	if overflow != 0 {
+		return nil, errors.New("shares do not interpolate to a byte string: not enough shares or shares of different splits")
+	}
+	return secret, nil
+}
+
+// checkConsistency16: checks that the shares beyond the first k lie on the polynomials through the first k.
+// Returns an error naming the first share that does not.
+func checkConsistency16(shares []Share16, k int) error {
+	if len(shares) <= k {
+		return nil
+	}
+
+	xs := make([]uint16, k)
+	for i := range xs {
+		xs[i] = shares[i].X
+	}
+
+	for _, share := range shares[k:] {
+		coeffs := lagrangeCoefficients16(xs, share.X)
+		for b := range share.Y {
+			var y uint16
+			for i, coeff := range coeffs {
+				y ^= gf16Mul(shares[i].Y[b], coeff)
+			}
+			if y != share.Y[b] {
+				return errors.New("inconsistent shares: share " + strconv.Itoa(int(share.X)) + " does not match the others")
+			}
+		}
+	}
+
+	return nil
+}
+
+// shamirGF65536Scheme: Scheme adapter for the GF(2^16) Shamir implementation.
+type shamirGF65536Scheme struct{}
+
+// Name: returns the registry name of the scheme.
+func (s *shamirGF65536Scheme) Name() string {
+	return "shamir-gf65536"
+}
+
+// Params: returns the public parameters of the scheme.
+func (s *shamirGF65536Scheme) Params() SchemeParams {
+	return SchemeParams{Field: "GF(2^16)", MaxShares: MaxShares16}
+}
+
+// Split: splits a secret into n shares with threshold k, each payload holding the share values as big-endian 16-bit words.
+// Returns the shares and an error if the split fails.
+func (s *shamirGF65536Scheme) Split(secret []byte, n, k int) ([]SchemeShare, error) {
+	shares, err := Split16(secret, n, k)
+	if err != nil {
+		return nil, err
+	}
+
+	schemeShares := make([]SchemeShare, len(shares))
+	for i, share := range shares {
+		payload := make([]byte, 2*len(share.Y))
+		for b, y := range share.Y {
+			binary.BigEndian.PutUint16(payload[2*b:], y)
+		}
+		schemeShares[i] = SchemeShare{
+			Scheme:    ShamirGF65536,
+			Threshold: k,
+			Index:     int(share.X),
+			Payload:   payload,
+		}
+	}
+
+	return schemeShares, nil
+}
+
+// shares: converts scheme shares back to GF(2^16) shares.
+// Returns the shares and an error if they are malformed.
+func (s *shamirGF65536Scheme) shares(schemeShares []SchemeShare) ([]Share16, error) {
+	if err := checkSchemeShares(ShamirGF65536, schemeShares, MaxShares16); err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share16, len(schemeShares))
+	for i, schemeShare := range schemeShares {
+		if len(schemeShare.Payload)%2 != 0 {
+			return nil, errors.New("share payload must hold 16-bit values")
+		}
+		y := make([]uint16, len(schemeShare.Payload)/2)
+		for b := range y {
+			y[b] = binary.BigEndian.Uint16(schemeShare.Payload[2*b:])
+		}
+		shares[i] = Share16{X: uint16(schemeShare.Index), Y: y}
+	}
+
+	if err := validateShares16(shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// Combine: reconstructs a secret from the first threshold shares.
+// Returns the secret and an error if the shares are malformed.
+func (s *shamirGF65536Scheme) Combine(schemeShares []SchemeShare) ([]byte, error) {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return nil, err
+	}
+	return Combine16(shares[:schemeShares[0].Threshold])
+}
+
+// Verify: checks that the shares are well-formed and, if there are more than threshold, consistent.
+// Returns an error if the verification fails.
+func (s *shamirGF65536Scheme) Verify(schemeShares []SchemeShare) error {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return err
+	}
+	return checkConsistency16(shares, schemeShares[0].Threshold)
+}
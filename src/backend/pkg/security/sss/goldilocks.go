@@ -0,0 +1,199 @@
+package sss
+
+import "math/bits"
+
+// Arithmetic modulo the Goldilocks prime p = 2^64 - 2^32 + 1, whose multiplicative group has a subgroup of order 2^32.
+// Polynomials over it can be evaluated at, and interpolated from, the powers of a root of unity with a number
+// theoretic transform (NTT) in O(n log n). Elements are kept canonical (below p) and every operation masks
+// instead of branching, as with the binary fields.
+
+const (
+	goldilocks           uint64 = 0xffffffff00000001  // the prime p
+	goldilocksEpsilon    uint64 = 0xffffffff          // 2^64 mod p
+	goldilocksRoot       uint64 = 1753635133440165772 // primitive 2^32-th root of unity, 7^((p-1)/2^32)
+	goldilocksTwoAdicity int    = 32
+)
+
+// schoolbookCutoff: length below which polynomials are multiplied directly rather than through the NTT.
+const schoolbookCutoff int = 32
+
+// gAdd: adds two elements modulo p.
+// Returns the sum.
+func gAdd(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	reduced, borrow := bits.Sub64(sum, goldilocks, 0)
+	// the unreduced sum is kept only if it neither overflowed nor reached p
+	keep := -(borrow &^ carry)
+	return sum&keep | reduced&^keep
+}
+
+// gSub: subtracts b from a modulo p.
+// Returns the difference.
+func gSub(a, b uint64) uint64 {
+	diff, borrow := bits.Sub64(a, b, 0)
+	// on underflow diff is a - b + 2^64, and a - b + p is diff - (2^64 - p)
+	return diff - goldilocksEpsilon&-borrow
+}
+
+// gMul: multiplies two elements modulo p, folding the high half of the product with 2^64 = 2^32 - 1 and 2^96 = -1.
+// Returns the product.
+func gMul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	hiHi, hiLo := hi>>32, hi&goldilocksEpsilon
+
+	t0, borrow := bits.Sub64(lo, hiHi, 0)
+	t0 -= goldilocksEpsilon & -borrow
+	t1, carry := bits.Add64(t0, hiLo*goldilocksEpsilon, 0)
+	t1 += goldilocksEpsilon & -carry
+
+	reduced, borrow := bits.Sub64(t1, goldilocks, 0)
+	keep := -borrow
+	return t1&keep | reduced&^keep
+}
+
+// gPow: raises a to a public exponent by square-and-multiply.
+// Returns a^e.
+func gPow(a, e uint64) uint64 {
+	result := uint64(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = gMul(result, a)
+		}
+		a = gMul(a, a)
+	}
+	return result
+}
+
+// gInv: inverts an element as a^(p-2).
+// Returns the inverse, or 0 for the zero element.
+func gInv(a uint64) uint64 {
+	return gPow(a, goldilocks-2)
+}
+
+// gInvBatch: inverts non-zero elements with a single field inversion (Montgomery's trick).
+// Returns the inverses.
+func gInvBatch(values []uint64) []uint64 {
+	prefix := make([]uint64, len(values))
+	acc := uint64(1)
+	for i, v := range values {
+		prefix[i] = acc
+		acc = gMul(acc, v)
+	}
+
+	inv := gInv(acc)
+	out := make([]uint64, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		out[i] = gMul(inv, prefix[i])
+		inv = gMul(inv, values[i])
+	}
+	return out
+}
+
+// nttRoot: computes a primitive root of unity of order n, a power of two up to 2^32.
+// Returns the root.
+func nttRoot(n int) uint64 {
+	root := goldilocksRoot
+	for order := uint64(1) << goldilocksTwoAdicity; order > uint64(n); order >>= 1 {
+		root = gMul(root, root)
+	}
+	return root
+}
+
+// ntt: transforms a in place, its length being a power of two n. The forward transform replaces the coefficients
+// of a polynomial of degree below n by its values at root^0, ..., root^(n-1), root being nttRoot(n);
+// the inverse transform interpolates such values back to coefficients.
+func ntt(a []uint64, inverse bool) {
+	n := len(a)
+
+	// bit-reversal permutation, which depends on the length only
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	root := nttRoot(n)
+	if inverse {
+		root = gInv(root)
+	}
+	twiddles := make([]uint64, n/2)
+	w := uint64(1)
+	for i := range twiddles {
+		twiddles[i] = w
+		w = gMul(w, root)
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size/2, n/size
+		for start := 0; start < n; start += size {
+			for j := range half {
+				u := a[start+j]
+				v := gMul(a[start+j+half], twiddles[j*step])
+				a[start+j] = gAdd(u, v)
+				a[start+j+half] = gSub(u, v)
+			}
+		}
+	}
+
+	if inverse {
+		nInv := gInv(uint64(n))
+		for i := range a {
+			a[i] = gMul(a[i], nInv)
+		}
+	}
+}
+
+// nttSize: finds the smallest power of two that is at least n.
+// Returns the power of two.
+func nttSize(n int) int {
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	return size
+}
+
+// polyMulMod: multiplies two polynomials given by their coefficients, lowest degree first.
+// Returns the coefficients of the product.
+func polyMulMod(a, b []uint64) []uint64 {
+	length := len(a) + len(b) - 1
+
+	if min(len(a), len(b)) < schoolbookCutoff {
+		out := make([]uint64, length)
+		for i, ai := range a {
+			for j, bj := range b {
+				out[i+j] = gAdd(out[i+j], gMul(ai, bj))
+			}
+		}
+		return out
+	}
+
+	size := nttSize(length)
+	fa := make([]uint64, size)
+	fb := make([]uint64, size)
+	copy(fa, a)
+	copy(fb, b)
+	ntt(fa, false)
+	ntt(fb, false)
+	for i := range fa {
+		fa[i] = gMul(fa[i], fb[i])
+	}
+	ntt(fa, true)
+	return fa[:length]
+}
+
+// vanishing: computes the monic polynomial whose roots are xs, multiplying halves recursively (a subproduct tree),
+// which takes O(n log^2 n) operations with NTT multiplication.
+// Returns its coefficients, lowest degree first.
+func vanishing(xs []uint64) []uint64 {
+	if len(xs) == 1 {
+		return []uint64{gSub(0, xs[0]), 1}
+	}
+	mid := len(xs) / 2
+	return polyMulMod(vanishing(xs[:mid]), vanishing(xs[mid:]))
+}
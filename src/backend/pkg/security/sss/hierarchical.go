@@ -0,0 +1,264 @@
+package sss
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+
+	"filippo.io/bigmod"
+)
+
+// Level: struct to describe one level of a hierarchical access structure, from the most senior level down.
+type Level struct {
+	Holders   int // number of holders at this level
+	Threshold int // cumulative threshold: holders needed from this level and all the levels above it
+}
+
+// HierarchicalShare: struct to hold a share of a Tassa hierarchical threshold split.
+// Holders at level i receive the derivative of order Thresholds[i-1] of the sharing polynomial, level 0 receives the polynomial itself.
+type HierarchicalShare struct {
+	Prime      *big.Int // modulus of the field
+	Thresholds []int    // cumulative thresholds of all the levels of the split
+	Level      int      // level of the holder, 0 being the most senior
+	X          int      // evaluation point, never 0
+	Y          *big.Int // derivative of the sharing polynomial at X
+}
+
+// derivativeOrder: computes the order of the derivative handed to holders of a level.
+// Returns 0 for the top level and the cumulative threshold of the previous level otherwise.
+func derivativeOrder(thresholds []int, level int) int {
+	if level == 0 {
+		return 0
+	}
+	return thresholds[level-1]
+}
+
+// birkhoffRow: computes the coefficients of a_0..a_{k-1} in the derivative of order d of sum a_c x^c at x.
+// Entry c is c!/(c-d)! * x^(c-d) for c >= d, and 0 below.
+// Returns the row of the Birkhoff interpolation matrix.
+func birkhoffRow(x *big.Int, d, k int, p *big.Int) []*big.Int {
+	row := make([]*big.Int, k)
+	for c := range row {
+		row[c] = new(big.Int)
+	}
+	if d >= k {
+		return row
+	}
+
+	// falling factorial c!/(c-d)! starting at c = d
+	factor := big.NewInt(1)
+	for i := 2; i <= d; i++ {
+		factor.Mul(factor, big.NewInt(int64(i)))
+	}
+
+	power := big.NewInt(1)
+	for c := d; c < k; c++ {
+		row[c].Mul(factor, power)
+		row[c].Mod(row[c], p)
+
+		power.Mul(power, x)
+		power.Mod(power, p)
+		factor.Mul(factor, big.NewInt(int64(c+1)))
+		factor.Div(factor, big.NewInt(int64(c+1-d)))
+	}
+
+	return row
+}
+
+// validateThresholds: checks that cumulative thresholds are positive, strictly increasing and end at 2 or more.
+// Returns an error if the thresholds are invalid.
+func validateThresholds(thresholds []int) error {
+	if len(thresholds) == 0 {
+		return errors.New("at least 1 level is required")
+	}
+	for i, threshold := range thresholds {
+		if threshold < 1 || (i > 0 && threshold <= thresholds[i-1]) {
+			return errors.New("level thresholds must be positive and strictly increasing")
+		}
+	}
+	if thresholds[len(thresholds)-1] < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	return nil
+}
+
+// validateLevels: checks that the levels describe a reachable hierarchical access structure.
+// Returns the cumulative thresholds, the number of holders and an error if the levels are invalid.
+func validateLevels(levels []Level) ([]int, int, error) {
+	thresholds := make([]int, len(levels))
+	holders := 0
+	for i, level := range levels {
+		if level.Holders < 1 {
+			return nil, 0, errors.New("level " + strconv.Itoa(i) + " must have at least 1 holder")
+		}
+		holders += level.Holders
+		if level.Threshold > holders {
+			return nil, 0, errors.New("level " + strconv.Itoa(i) + " threshold cannot exceed the holders at or above it")
+		}
+		thresholds[i] = level.Threshold
+	}
+
+	if err := validateThresholds(thresholds); err != nil {
+		return nil, 0, err
+	}
+	return thresholds, holders, nil
+}
+
+// SplitHierarchical: splits a secret field element with Tassa's hierarchical threshold scheme.
+// A group can reconstruct when, for every level, it holds at least that level's cumulative threshold of shares at or above it.
+// Points are allocated in level order, which keeps the Birkhoff systems of authorised groups solvable for large primes.
+// Returns the shares in level order and an error if the levels, secret or prime are invalid.
+func SplitHierarchical(secret *big.Int, levels []Level, prime *big.Int) ([]HierarchicalShare, error) {
+	if err := validatePrime(prime); err != nil {
+		return nil, err
+	}
+	if !inField(secret, prime) {
+		return nil, errors.New("secret must be an element of the field")
+	}
+
+	thresholds, holders, err := validateLevels(levels)
+	if err != nil {
+		return nil, err
+	}
+	if big.NewInt(int64(holders)).Cmp(prime) >= 0 {
+		return nil, errors.New("number of shares must be smaller than the prime")
+	}
+
+	p := new(big.Int).Set(prime)
+	k := thresholds[len(thresholds)-1]
+	f, err := newPrimeField(p)
+	if err != nil {
+		return nil, err
+	}
+
+	coeffs := make([]*bigmod.Nat, k)
+	if coeffs[0], err = f.element(secret); err != nil {
+		return nil, err
+	}
+	for i := 1; i < k; i++ {
+		coeff, err := randomFieldElement(p)
+		if err != nil {
+			return nil, err
+		}
+		if coeffs[i], err = f.element(coeff); err != nil {
+			return nil, err
+		}
+	}
+
+	shares := make([]HierarchicalShare, 0, holders)
+	for level, l := range levels {
+		d := derivativeOrder(thresholds, level)
+		for range l.Holders {
+			x := len(shares) + 1
+			row, err := f.elements(birkhoffRow(big.NewInt(int64(x)), d, k, p))
+			if err != nil {
+				return nil, err
+			}
+			shares = append(shares, HierarchicalShare{
+				Prime:      p,
+				Thresholds: thresholds,
+				Level:      level,
+				X:          x,
+				Y:          f.toInt(f.dot(row, coeffs)),
+			})
+		}
+	}
+
+	return shares, nil
+}
+
+// checkHierarchicalAccess: checks that the shares meet every cumulative threshold of the hierarchy.
+// Returns an *AccessError naming the first level whose threshold is not met.
+func checkHierarchicalAccess(shares []HierarchicalShare, thresholds []int) error {
+	counts := make([]int, len(thresholds))
+	for _, share := range shares {
+		counts[share.Level]++
+	}
+
+	cumulative := 0
+	for level, threshold := range thresholds {
+		cumulative += counts[level]
+		if cumulative < threshold {
+			return &AccessError{
+				Reason: "level " + strconv.Itoa(level) + " needs " + strconv.Itoa(threshold) +
+					" holders at or above it, have " + strconv.Itoa(cumulative),
+			}
+		}
+	}
+
+	return nil
+}
+
+// CombineHierarchical: reconstructs a secret from hierarchical shares by Birkhoff interpolation.
+// Exactly k shares are used, taken from the most senior levels first.
+// Returns the secret, an *AccessError if the shares do not satisfy the hierarchy, or another error if they are malformed.
+func CombineHierarchical(shares []HierarchicalShare) (*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, &AccessError{Reason: "no shares supplied"}
+	}
+
+	prime := shares[0].Prime
+	if err := validatePrime(prime); err != nil {
+		return nil, err
+	}
+	thresholds := shares[0].Thresholds
+	if err := validateThresholds(thresholds); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.Prime == nil || share.Prime.Cmp(prime) != 0 {
+			return nil, errors.New("shares must all use the same prime")
+		}
+		if !slices.Equal(share.Thresholds, thresholds) {
+			return nil, errors.New("shares must all come from the same hierarchy")
+		}
+		if share.Level < 0 || share.Level >= len(thresholds) {
+			return nil, errors.New("share level is out of range")
+		}
+		if share.X <= 0 || big.NewInt(int64(share.X)).Cmp(prime) >= 0 {
+			return nil, errors.New("share x must be a non-zero field element")
+		}
+		if seen[share.X] {
+			return nil, errors.New("duplicate share x: " + strconv.Itoa(share.X))
+		}
+		seen[share.X] = true
+		if !inField(share.Y, prime) {
+			return nil, errors.New("share y is outside the field")
+		}
+	}
+
+	if err := checkHierarchicalAccess(shares, thresholds); err != nil {
+		return nil, err
+	}
+
+	// the k most senior shares meet every cumulative threshold whenever the whole set does
+	k := thresholds[len(thresholds)-1]
+	sorted := slices.Clone(shares)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Level < sorted[j].Level })
+
+	f, err := newPrimeField(prime)
+	if err != nil {
+		return nil, err
+	}
+
+	a := make([][]*bigmod.Nat, k)
+	b := make([]*bigmod.Nat, k)
+	for i, share := range sorted[:k] {
+		if a[i], err = f.elements(birkhoffRow(big.NewInt(int64(share.X)), derivativeOrder(thresholds, share.Level), k, prime)); err != nil {
+			return nil, err
+		}
+		if b[i], err = f.element(share.Y); err != nil {
+			return nil, err
+		}
+	}
+
+	coeffs, err := f.solve(a, b)
+	if err != nil {
+		return nil, errors.New("shares form a singular Birkhoff system: " + err.Error())
+	}
+	return f.toInt(coeffs[0]), nil
+}
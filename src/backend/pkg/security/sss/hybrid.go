@@ -0,0 +1,256 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// HybridKeyLen: length in bytes of the AES-256 key shared by SplitHybrid.
+const HybridKeyLen int = 32
+
+// ErrHybridDecrypt: returned when no set of fragments decrypts under the reconstructed key,
+// either because the key shares are wrong or because fewer than k fragments are intact.
+var ErrHybridDecrypt = errors.New("hybrid shares do not decrypt: key shares or too many fragments are corrupt")
+
+// HybridShare: struct to hold one share of data split with SplitHybrid.
+// Only the key share is a Shamir share; the fragment is 1/k of the ciphertext and reveals nothing without the key.
+type HybridShare struct {
+	Key       Share    `json:"key" bson:"key"`             // Shamir share of the AES-256 key, Key.X is the fragment index
+	Threshold int      `json:"threshold" bson:"threshold"` // number of shares needed to reconstruct
+	Length    int      `json:"length" bson:"length"`       // ciphertext length, GCM tag included
+	Nonce     []byte   `json:"nonce" bson:"nonce"`         // GCM nonce
+	Digests   [][]byte `json:"digests" bson:"digests"`     // SHA-256 of every fragment, indexed by X-1
+	Fragment  []byte   `json:"fragment" bson:"fragment"`   // IDA fragment of the ciphertext
+}
+
+// HybridResult: struct to hold the outcome of a hybrid reconstruction.
+type HybridResult struct {
+	Secret  []byte // decrypted data
+	Corrupt []byte // X values of the shares whose fragment or key share was found corrupt, in increasing order
+}
+
+// SplitHybrid: splits large data into n shares, any k of which can reconstruct it, in the spirit of Krawczyk's
+// secret sharing made short. The data is encrypted with AES-256-GCM, the ciphertext is dispersed with Rabin's IDA
+// and only the key is Shamir-shared, so each share is about len(data)/k bytes instead of len(data).
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func SplitHybrid(data []byte, n, k int) ([]HybridShare, error) {
+	if len(data) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+
+	key, err := random(HybridKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	keyShares, err := Split(key, n, k)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newHybridAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if err := entropy.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := aead.Seal(nil, nonce, data, nil)
+
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = keyShares[i].X
+	}
+	fragments := disperse(ciphertext, xs, k)
+
+	digests := make([][]byte, n)
+	for i, fragment := range fragments {
+		digest := sha256.Sum256(fragment)
+		digests[i] = digest[:]
+	}
+
+	shares := make([]HybridShare, n)
+	for i := range shares {
+		shares[i] = HybridShare{
+			Key:       keyShares[i],
+			Threshold: k,
+			Length:    len(ciphertext),
+			Nonce:     nonce,
+			Digests:   digests,
+			Fragment:  fragments[i],
+		}
+	}
+
+	return shares, nil
+}
+
+// newHybridAEAD: builds the AES-256-GCM cipher for a hybrid key.
+// Returns the AEAD and an error if the key has the wrong length.
+func newHybridAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// validateHybridShares: checks that the shares carry the same split parameters and distinct indices.
+// Returns the threshold and an error if the shares are malformed or too few.
+func validateHybridShares(shares []HybridShare) (int, error) {
+	if len(shares) == 0 {
+		return 0, errors.New("at least 1 share is required")
+	}
+
+	k := shares[0].Threshold
+	if k < 2 {
+		return 0, errors.New("threshold must be at least 2")
+	}
+	if len(shares) < k {
+		return 0, errors.New("not enough shares: have " + strconv.Itoa(len(shares)) + ", need " + strconv.Itoa(k))
+	}
+
+	for _, share := range shares {
+		if share.Threshold != k {
+			return 0, errors.New("shares have different thresholds")
+		}
+		if share.Length != shares[0].Length || share.Length <= 0 {
+			return 0, errors.New("shares have different or invalid ciphertext lengths")
+		}
+		if !bytes.Equal(share.Nonce, shares[0].Nonce) {
+			return 0, errors.New("shares have different nonces")
+		}
+		if len(share.Key.Y) != HybridKeyLen {
+			return 0, errors.New("key share must be " + strconv.Itoa(HybridKeyLen) + " bytes")
+		}
+	}
+
+	keyShares := make([]Share, len(shares))
+	for i, share := range shares {
+		keyShares[i] = share.Key
+	}
+	if err := validateShares(keyShares); err != nil {
+		return 0, err
+	}
+
+	return k, nil
+}
+
+// matchesDigests: checks the digest of a share's fragment against a list of fragment digests.
+// Returns true if the list has an entry for the share's index and it equals the fragment digest.
+func matchesDigests(x byte, fragmentDigest [sha256.Size]byte, digests [][]byte) bool {
+	i := int(x) - 1
+	return i < len(digests) && bytes.Equal(fragmentDigest[:], digests[i])
+}
+
+// digestListKey: encodes a digest list so that identical lists can be counted in a map.
+// Returns the encoded list.
+func digestListKey(digests [][]byte) string {
+	var key []byte
+	for _, digest := range digests {
+		key = append(key, byte(len(digest)))
+		key = append(key, digest...)
+	}
+	return string(key)
+}
+
+// digestCandidates: groups the digest lists carried by the shares, most widely held first.
+// Returns the distinct digest lists.
+func digestCandidates(shares []HybridShare) [][][]byte {
+	var candidates [][][]byte
+	support := make(map[string]int)
+	for _, share := range shares {
+		key := digestListKey(share.Digests)
+		if support[key] == 0 {
+			candidates = append(candidates, share.Digests)
+		}
+		support[key]++
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return support[digestListKey(candidates[i])] > support[digestListKey(candidates[j])]
+	})
+
+	return candidates
+}
+
+// CombineHybrid: reconstructs data split with SplitHybrid and reports corrupt shares.
+// The key is recovered from the key shares, correcting bad ones when there are spare shares. Fragments are then
+// checked against the digest lists the shares carry; a list is trusted once k fragments matching it pass the GCM tag,
+// and every share whose fragment does not match it is reported as corrupt.
+// Returns the data with the corrupt share indices, ErrHybridDecrypt if nothing decrypts, or another error if the
+// shares are malformed or the key shares cannot be corrected.
+func CombineHybrid(shares []HybridShare) (*HybridResult, error) {
+	k, err := validateHybridShares(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	keyShares := make([]Share, len(shares))
+	for i, share := range shares {
+		keyShares[i] = share.Key
+	}
+	keyResult, err := CombineRobust(keyShares, k)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newHybridAEAD(keyResult.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares[0].Nonce) != aead.NonceSize() {
+		return nil, errors.New("nonce must be " + strconv.Itoa(aead.NonceSize()) + " bytes")
+	}
+
+	rejectedKeys := make(map[byte]bool, len(keyResult.Rejected))
+	for _, x := range keyResult.Rejected {
+		rejectedKeys[x] = true
+	}
+
+	fragmentDigests := make([][sha256.Size]byte, len(shares))
+	for i, share := range shares {
+		fragmentDigests[i] = sha256.Sum256(share.Fragment)
+	}
+
+	for _, digests := range digestCandidates(shares) {
+		var xs []byte
+		var fragments [][]byte
+		for i, share := range shares {
+			if len(xs) < k && !rejectedKeys[share.Key.X] && matchesDigests(share.Key.X, fragmentDigests[i], digests) {
+				xs = append(xs, share.Key.X)
+				fragments = append(fragments, share.Fragment)
+			}
+		}
+		if len(xs) < k {
+			continue
+		}
+
+		ciphertext, err := undisperse(xs, fragments, shares[0].Length)
+		if err != nil {
+			continue
+		}
+		secret, err := aead.Open(nil, shares[0].Nonce, ciphertext, nil)
+		if err != nil {
+			continue
+		}
+
+		result := &HybridResult{Secret: secret, Corrupt: []byte{}}
+		for i, share := range shares {
+			if rejectedKeys[share.Key.X] || !matchesDigests(share.Key.X, fragmentDigests[i], digests) {
+				result.Corrupt = append(result.Corrupt, share.Key.X)
+			}
+		}
+		sort.Slice(result.Corrupt, func(i, j int) bool { return result.Corrupt[i] < result.Corrupt[j] })
+
+		return result, nil
+	}
+
+	return nil, ErrHybridDecrypt
+}
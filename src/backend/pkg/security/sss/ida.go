@@ -0,0 +1,92 @@
+package sss
+
+import "errors"
+
+// Rabin's Information Dispersal Algorithm over GF(2^8): the data is cut into chunks of k bytes, each chunk is read as
+// the coefficients of a polynomial of degree k-1 and fragment i receives its value at x_i. Any k fragments determine
+// every chunk, and each fragment is only 1/k of the data.
+
+// disperse: splits data into one fragment per X value, any k of which can rebuild it.
+// The data is zero-padded to a multiple of k, so fragments are ceil(len(data)/k) bytes long.
+// Returns the fragments in the order of xs.
+func disperse(data []byte, xs []byte, k int) [][]byte {
+	chunks := (len(data) + k - 1) / k
+	padded := make([]byte, chunks*k)
+	copy(padded, data)
+
+	fragments := make([][]byte, len(xs))
+	for i, x := range xs {
+		fragment := make([]byte, chunks)
+		for c := range fragment {
+			fragment[c] = evaluate(padded[c*k:(c+1)*k], x)
+		}
+		fragments[i] = fragment
+	}
+
+	return fragments
+}
+
+// vandermondeInverse: inverts the k x k Vandermonde matrix with rows (1, x_i, ..., x_i^(k-1)).
+// Returns the inverse and an error if the X values repeat.
+func vandermondeInverse(xs []byte) ([][]byte, error) {
+	k := len(xs)
+
+	inverse := make([][]byte, k)
+	for j := range inverse {
+		inverse[j] = make([]byte, k)
+	}
+
+	for col := range k {
+		a := make([][]byte, k)
+		b := make([]byte, k)
+		for i, x := range xs {
+			a[i] = make([]byte, k)
+			power := byte(1)
+			for j := range k {
+				a[i][j] = power
+				power = gfMul(power, x)
+			}
+		}
+		b[col] = 1
+
+		solution, ok := solveLinear(a, b)
+		if !ok {
+			return nil, errors.New("fragment X values must be distinct")
+		}
+		for j := range k {
+			inverse[j][col] = solution[j]
+		}
+	}
+
+	return inverse, nil
+}
+
+// undisperse: rebuilds length bytes of data from k fragments taken at the given X values.
+// Returns the data and an error if the X values repeat or the fragments are too short.
+func undisperse(xs []byte, fragments [][]byte, length int) ([]byte, error) {
+	k := len(xs)
+	chunks := (length + k - 1) / k
+	for _, fragment := range fragments {
+		if len(fragment) != chunks {
+			return nil, errors.New("fragment length does not match the data length")
+		}
+	}
+
+	inverse, err := vandermondeInverse(xs)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, chunks*k)
+	for c := range chunks {
+		for j := range k {
+			var v byte
+			for i := range k {
+				v = gfAdd(v, gfMul(inverse[j][i], fragments[i][c]))
+			}
+			data[c*k+j] = v
+		}
+	}
+
+	return data[:length], nil
+}
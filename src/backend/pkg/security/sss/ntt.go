@@ -0,0 +1,236 @@
+package sss
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// Shamir's scheme for very large numbers of shares, over the Goldilocks prime field. Share points are the powers
+// of a root of unity of order the next power of two above n, so a split evaluates each polynomial at all points
+// with one NTT, and a combine finds the Lagrange coefficients from the derivative of the vanishing polynomial of
+// the share points, also evaluated with one NTT. Both take O(n log^2 n) field operations instead of O(n^2).
+
+// MaxNTTShares: maximum number of shares of an NTT split.
+const MaxNTTShares int = 1 << 20
+
+// nttLimbLen: number of secret bytes shared in each field element, the largest that always fits below p.
+const nttLimbLen int = 7
+
+// nttDirectCutoff: number of shares up to which a combine computes the Lagrange coefficients directly,
+// which is cheaper than transforms over the whole domain.
+const nttDirectCutoff int = 64
+
+// NTTShare: struct to hold a single share of a secret shared with SplitNTT.
+type NTTShare struct {
+	Domain int    `json:"domain" bson:"domain"` // number of share points of the split, a power of two
+	Index  int    `json:"index" bson:"index"`   // share point root^(Index-1), from 1 to Domain
+	Length int    `json:"length" bson:"length"` // length of the secret in bytes
+	Y      []byte `json:"y" bson:"y"`           // polynomial values at the share point, 8 big-endian bytes per 7-byte limb
+}
+
+// nttLimbs: computes the number of field elements holding a secret of the given length.
+// Returns the number of limbs.
+func nttLimbs(length int) int {
+	return (length + nttLimbLen - 1) / nttLimbLen
+}
+
+// randomGoldilocks: generates uniformly random elements modulo p, resampling the rare values at or above p.
+// Returns the elements and an error if the random generation fails.
+func randomGoldilocks(count int) ([]uint64, error) {
+	randomBytes, err := random(8 * count)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]uint64, count)
+	for i := range out {
+		v := binary.BigEndian.Uint64(randomBytes[8*i:])
+		for v >= goldilocks {
+			resample, err := random(8)
+			if err != nil {
+				return nil, err
+			}
+			v = binary.BigEndian.Uint64(resample)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// SplitNTT: splits a secret into n shares, any k of which can reconstruct it, in O(n log n) per 7-byte limb.
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func SplitNTT(secret []byte, n, k int) ([]NTTShare, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if n > MaxNTTShares {
+		return nil, errors.New("number of shares cannot exceed " + strconv.Itoa(MaxNTTShares))
+	}
+
+	domain := nttSize(n)
+	limbs := nttLimbs(len(secret))
+	shares := make([]NTTShare, n)
+	for i := range shares {
+		shares[i] = NTTShare{Domain: domain, Index: i + 1, Length: len(secret), Y: make([]byte, 8*limbs)}
+	}
+
+	poly := make([]uint64, domain)
+	limb := make([]byte, 8)
+	for l := range limbs {
+		coeffs, err := randomGoldilocks(k - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		clear(poly)
+		clear(limb)
+		copy(limb[1:], secret[l*nttLimbLen:min((l+1)*nttLimbLen, len(secret))])
+		poly[0] = binary.BigEndian.Uint64(limb)
+		copy(poly[1:], coeffs)
+
+		ntt(poly, false)
+		for i := range shares {
+			binary.BigEndian.PutUint64(shares[i].Y[8*l:], poly[i])
+		}
+	}
+
+	return shares, nil
+}
+
+// validateNTTShares: checks that the shares come from the same split and can be interpolated together.
+// Returns the share values and an error if the shares are malformed.
+func validateNTTShares(shares []NTTShare) ([][]uint64, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+
+	domain, length := shares[0].Domain, shares[0].Length
+	if domain < 2 || domain > MaxNTTShares || domain&(domain-1) != 0 {
+		return nil, errors.New("share domain must be a power of two up to " + strconv.Itoa(MaxNTTShares))
+	}
+	if length <= 0 {
+		return nil, errors.New("share secret length must be positive")
+	}
+
+	limbs := nttLimbs(length)
+	seen := make(map[int]bool, len(shares))
+	ys := make([][]uint64, len(shares))
+	for i, share := range shares {
+		if share.Domain != domain || share.Length != length {
+			return nil, errors.New("shares belong to different splits")
+		}
+		if share.Index < 1 || share.Index > domain {
+			return nil, errors.New("share index out of range: " + strconv.Itoa(share.Index))
+		}
+		if seen[share.Index] {
+			return nil, errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+
+		if len(share.Y) != 8*limbs {
+			return nil, errors.New("share Y value does not match the secret length")
+		}
+		ys[i] = make([]uint64, limbs)
+		for l := range ys[i] {
+			ys[i][l] = binary.BigEndian.Uint64(share.Y[8*l:])
+			if ys[i][l] >= goldilocks {
+				return nil, errors.New("share value is outside the field")
+			}
+		}
+	}
+
+	return ys, nil
+}
+
+// nttLagrange: computes the Lagrange basis polynomials at 0 for distinct points xs = root^indices[i], root being
+// of order domain. With Z the vanishing polynomial of the points, the basis polynomial of x_i at 0 is
+// (-1)^(m-1) * prod(x_j) / (x_i * Z'(x_i)), and Z' is evaluated at every point of the domain with one NTT.
+// Returns one coefficient per point.
+func nttLagrange(xs []uint64, indices []int, domain int) []uint64 {
+	m := len(xs)
+
+	if m <= nttDirectCutoff {
+		coeffs := make([]uint64, m)
+		for i := range xs {
+			num, den := uint64(1), uint64(1)
+			for j := range xs {
+				if i != j {
+					num = gMul(num, xs[j])
+					den = gMul(den, gSub(xs[j], xs[i]))
+				}
+			}
+			coeffs[i] = gMul(num, gInv(den))
+		}
+		return coeffs
+	}
+
+	z := vanishing(xs)
+	derivative := make([]uint64, domain)
+	for i := 1; i < len(z); i++ {
+		derivative[i-1] = gMul(uint64(i), z[i])
+	}
+	ntt(derivative, false)
+
+	product := uint64(1)
+	dens := make([]uint64, m)
+	for i, x := range xs {
+		product = gMul(product, x)
+		dens[i] = gMul(x, derivative[indices[i]])
+	}
+	if (m-1)%2 == 1 {
+		product = gSub(0, product)
+	}
+
+	coeffs := gInvBatch(dens)
+	for i := range coeffs {
+		coeffs[i] = gMul(coeffs[i], product)
+	}
+	return coeffs
+}
+
+// CombineNTT: reconstructs a secret from shares produced by SplitNTT, in O(m log^2 m) for m shares.
+// The caller must supply at least as many shares as the threshold used at split time.
+// Returns the secret and an error if the shares are malformed or do not interpolate to a secret,
+// which happens with too few shares or shares of different splits.
+func CombineNTT(shares []NTTShare) ([]byte, error) {
+	ys, err := validateNTTShares(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, length := shares[0].Domain, shares[0].Length
+	root := nttRoot(domain)
+	xs := make([]uint64, len(shares))
+	indices := make([]int, len(shares))
+	for i, share := range shares {
+		indices[i] = share.Index - 1
+		xs[i] = gPow(root, uint64(indices[i]))
+	}
+	coeffs := nttLagrange(xs, indices, domain)
+
+	secret := make([]byte, 0, len(ys[0])*nttLimbLen)
+	var overflow uint64
+	limb := make([]byte, 8)
+	for l := range ys[0] {
+		var v uint64
+		for i, coeff := range coeffs {
+			v = gAdd(v, gMul(ys[i][l], coeff))
+		}
+		overflow |= v >> (8 * nttLimbLen)
+
+		binary.BigEndian.PutUint64(limb, v)
+		secret = append(secret, limb[1:]...)
+	}
+
+	if overflow != 0 {
+		return nil, errors.New("shares do not interpolate to a secret: not enough shares or shares of different splits")
+	}
+	return secret[:length], nil
+}
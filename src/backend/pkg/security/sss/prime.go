@@ -0,0 +1,433 @@
+package sss
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+
+	"filippo.io/bigmod"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// Names of the primes shipped with the package.
+const (
+	Mersenne127 string = "mersenne-127"
+	Mersenne521 string = "mersenne-521"
+	P256Order   string = "p256-order"
+)
+
+// customPrime: field name recorded for caller-supplied primes.
+const customPrime string = "custom"
+
+// primeCertainty: number of Miller-Rabin rounds used to check caller-supplied primes.
+const primeCertainty int = 32
+
+var namedPrimes = map[string]*big.Int{
+	Mersenne127: mersenne(127),
+	Mersenne521: mersenne(521),
+	P256Order:   mustParseHex("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551"),
+}
+
+// mersenne: computes the Mersenne number 2^exp - 1.
+// Returns the Mersenne number.
+func mersenne(exp uint) *big.Int {
+	m := new(big.Int).Lsh(big.NewInt(1), exp)
+	return m.Sub(m, big.NewInt(1))
+}
+
+// mustParseHex: parses a hexadecimal constant.
+// Panics if the constant is malformed.
+func mustParseHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hexadecimal constant: " + s)
+	}
+	return v
+}
+
+// NamedPrime: looks up one of the primes shipped with the package.
+// Returns a copy of the prime and an error if the name is unknown.
+func NamedPrime(name string) (*big.Int, error) {
+	p, ok := namedPrimes[name]
+	if !ok {
+		return nil, errors.New("unknown prime: " + name)
+	}
+	return new(big.Int).Set(p), nil
+}
+
+// primeName: finds the name of a shipped prime.
+// Returns the name, or "custom" if the prime is not one of the shipped primes.
+func primeName(p *big.Int) string {
+	for name, named := range namedPrimes {
+		if named.Cmp(p) == 0 {
+			return name
+		}
+	}
+	return customPrime
+}
+
+// validatePrime: checks that p can be used as the modulus of a secret-sharing field.
+// Shipped primes are trusted, caller-supplied ones are tested for primality.
+// Returns an error if p is not a usable prime.
+func validatePrime(p *big.Int) error {
+	if p == nil {
+		return errors.New("prime cannot be nil")
+	}
+	if primeName(p) != customPrime {
+		return nil
+	}
+	if p.Cmp(big.NewInt(2)) <= 0 || !p.ProbablyPrime(primeCertainty) {
+		return errors.New("modulus is not an odd prime")
+	}
+	return nil
+}
+
+// PrimeShare: struct to hold a single share of a secret shared over the prime field GF(p).
+type PrimeShare struct {
+	Prime *big.Int // modulus of the field
+	X     int      // evaluation point, never 0
+	Y     *big.Int // polynomial value at X
+}
+
+// primeShareDocument: self-describing serialized form of a PrimeShare.
+// Big integers are stored as hexadecimal strings so that they survive both JSON and BSON.
+type primeShareDocument struct {
+	Field string `json:"field" bson:"field"` // name of the prime, or "custom"
+	Prime string `json:"prime" bson:"prime"` // hexadecimal modulus
+	X     int    `json:"x" bson:"x"`
+	Y     string `json:"y" bson:"y"` // hexadecimal share value
+}
+
+// document: converts the share to its serialized form.
+// Returns the document and an error if the share is incomplete.
+func (s PrimeShare) document() (*primeShareDocument, error) {
+	if s.Prime == nil || s.Y == nil {
+		return nil, errors.New("prime share is incomplete")
+	}
+
+	return &primeShareDocument{
+		Field: primeName(s.Prime),
+		Prime: s.Prime.Text(16),
+		X:     s.X,
+		Y:     s.Y.Text(16),
+	}, nil
+}
+
+// fromDocument: restores the share from its serialized form.
+// Returns an error if the document is malformed or inconsistent.
+func (s *PrimeShare) fromDocument(doc *primeShareDocument) error {
+	prime, ok := new(big.Int).SetString(doc.Prime, 16)
+	if !ok {
+		return errors.New("invalid prime in share document")
+	}
+	y, ok := new(big.Int).SetString(doc.Y, 16)
+	if !ok {
+		return errors.New("invalid share value in share document")
+	}
+
+	if doc.Field != customPrime {
+		named, known := namedPrimes[doc.Field]
+		if !known || named.Cmp(prime) != 0 {
+			return errors.New("share document field does not match its prime")
+		}
+	}
+	if y.Sign() < 0 || y.Cmp(prime) >= 0 {
+		return errors.New("share value is outside the field")
+	}
+
+	s.Prime, s.X, s.Y = prime, doc.X, y
+	return nil
+}
+
+// MarshalJSON: serializes the share to JSON.
+// Returns the JSON bytes and an error if the share is incomplete.
+func (s PrimeShare) MarshalJSON() ([]byte, error) {
+	doc, err := s.document()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON: deserializes the share from JSON.
+// Returns an error if the JSON is malformed.
+func (s *PrimeShare) UnmarshalJSON(data []byte) error {
+	var doc primeShareDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return s.fromDocument(&doc)
+}
+
+// MarshalBSON: serializes the share to BSON, allowing it to be stored through the mongo client.
+// Returns the BSON bytes and an error if the share is incomplete.
+func (s PrimeShare) MarshalBSON() ([]byte, error) {
+	doc, err := s.document()
+	if err != nil {
+		return nil, err
+	}
+	return bson.Marshal(doc)
+}
+
+// UnmarshalBSON: deserializes the share from BSON.
+// Returns an error if the BSON is malformed.
+func (s *PrimeShare) UnmarshalBSON(data []byte) error {
+	var doc primeShareDocument
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return s.fromDocument(&doc)
+}
+
+// randomFieldElement: generates a uniformly random element of GF(p).
+// Returns the element and an error if the generation fails.
+func randomFieldElement(p *big.Int) (*big.Int, error) {
+	return rand.Int(entropy.Default(), p)
+}
+
+// SplitPrime: splits a secret field element into n shares over GF(prime), any k of which can reconstruct it.
+// The prime can be one of the shipped primes (see NamedPrime) or any caller-supplied prime.
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func SplitPrime(secret *big.Int, n, k int, prime *big.Int) ([]PrimeShare, error) {
+	if err := validatePrime(prime); err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Sign() < 0 || secret.Cmp(prime) >= 0 {
+		return nil, errors.New("secret must be an element of the field")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if big.NewInt(int64(n)).Cmp(prime) >= 0 {
+		return nil, errors.New("number of shares must be smaller than the prime")
+	}
+
+	f, err := newPrimeField(prime)
+	if err != nil {
+		return nil, err
+	}
+
+	coeffs := make([]*bigmod.Nat, k)
+	if coeffs[0], err = f.element(secret); err != nil {
+		return nil, err
+	}
+	for i := 1; i < k; i++ {
+		coeff, err := randomFieldElement(prime)
+		if err != nil {
+			return nil, err
+		}
+		if coeffs[i], err = f.element(coeff); err != nil {
+			return nil, err
+		}
+	}
+
+	p := new(big.Int).Set(prime)
+	shares := make([]PrimeShare, n)
+	for i := range shares {
+		x := i + 1
+		shares[i] = PrimeShare{
+			Prime: p,
+			X:     x,
+			Y:     f.toInt(f.evaluate(coeffs, f.small(x))),
+		}
+	}
+
+	return shares, nil
+}
+
+// CombinePrime: reconstructs a secret field element from its shares using Lagrange interpolation at 0.
+// All shares must have been produced over the same prime.
+// Returns the secret and an error if the shares are malformed.
+func CombinePrime(shares []PrimeShare) (*big.Int, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+
+	prime := shares[0].Prime
+	if err := validatePrime(prime); err != nil {
+		return nil, err
+	}
+	f, err := newPrimeField(prime)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]*bigmod.Nat, len(shares))
+	ys := make([]*bigmod.Nat, len(shares))
+	seen := make(map[int]bool, len(shares))
+	for i, share := range shares {
+		if share.Prime == nil || share.Prime.Cmp(prime) != 0 {
+			return nil, errors.New("shares must all use the same prime")
+		}
+		if share.X <= 0 || big.NewInt(int64(share.X)).Cmp(prime) >= 0 {
+			return nil, errors.New("share X value must be a non-zero element of the field")
+		}
+		if seen[share.X] {
+			return nil, errors.New("duplicate share X value")
+		}
+		seen[share.X] = true
+		if share.Y == nil || share.Y.Sign() < 0 || share.Y.Cmp(prime) >= 0 {
+			return nil, errors.New("share value is outside the field")
+		}
+
+		xs[i] = f.small(share.X)
+		if ys[i], err = f.element(share.Y); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.toInt(f.interpolate(xs, ys, f.small(0))), nil
+}
+
+// secretToInt: maps a byte secret to an integer by prefixing it with a 0x01 marker.
+// The marker preserves leading zero bytes and lets the decoder detect a failed reconstruction.
+// Returns the integer.
+func secretToInt(secret []byte) *big.Int {
+	return new(big.Int).SetBytes(append([]byte{0x01}, secret...))
+}
+
+// intToSecret: maps an integer produced by secretToInt back to the byte secret.
+// Returns the secret and an error if the integer does not carry the marker.
+func intToSecret(value *big.Int) ([]byte, error) {
+	encoded := value.Bytes()
+	if len(encoded) < 2 || encoded[0] != 0x01 {
+		return nil, errors.New("shares do not reconstruct a valid secret")
+	}
+	return encoded[1:], nil
+}
+
+// shamirPrimeScheme: Scheme adapter for the prime-field Shamir implementation.
+type shamirPrimeScheme struct {
+	prime string // name of the shipped prime the scheme works over
+}
+
+// modulus: returns the prime the scheme works over.
+func (s *shamirPrimeScheme) modulus() *big.Int {
+	return namedPrimes[s.prime]
+}
+
+// Name: returns the registry name of the scheme.
+func (s *shamirPrimeScheme) Name() string {
+	return "shamir-prime"
+}
+
+// Params: returns the public parameters of the scheme.
+func (s *shamirPrimeScheme) Params() SchemeParams {
+	p := s.modulus()
+	return SchemeParams{
+		Field:        "GF(p) " + s.prime,
+		MaxShares:    MaxShares,
+		MaxSecretLen: (p.BitLen()-1)/8 - 1,
+	}
+}
+
+// Split: splits a secret into n shares with threshold k.
+// Returns the shares and an error if the secret is too long or the split fails.
+func (s *shamirPrimeScheme) Split(secret []byte, n, k int) ([]SchemeShare, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if len(secret) > s.Params().MaxSecretLen {
+		return nil, errors.New("secret is too long for the field")
+	}
+	if n > MaxShares {
+		return nil, errors.New("number of shares cannot exceed 255")
+	}
+
+	p := s.modulus()
+	shares, err := SplitPrime(secretToInt(secret), n, k, p)
+	if err != nil {
+		return nil, err
+	}
+
+	schemeShares := make([]SchemeShare, len(shares))
+	for i, share := range shares {
+		schemeShares[i] = SchemeShare{
+			Scheme:    ShamirPrime,
+			Threshold: k,
+			Index:     share.X,
+			Payload:   share.Y.FillBytes(make([]byte, (p.BitLen()+7)/8)),
+		}
+	}
+
+	return schemeShares, nil
+}
+
+// shares: converts scheme shares back to prime-field shares.
+// Returns the shares and an error if they are malformed.
+func (s *shamirPrimeScheme) shares(schemeShares []SchemeShare) ([]PrimeShare, error) {
+	if err := checkSchemeShares(ShamirPrime, schemeShares, MaxShares); err != nil {
+		return nil, err
+	}
+
+	p := s.modulus()
+	shares := make([]PrimeShare, len(schemeShares))
+	for i, schemeShare := range schemeShares {
+		y := new(big.Int).SetBytes(schemeShare.Payload)
+		if y.Cmp(p) >= 0 {
+			return nil, errors.New("share value is outside the field")
+		}
+		shares[i] = PrimeShare{Prime: p, X: schemeShare.Index, Y: y}
+	}
+
+	return shares, nil
+}
+
+// Combine: reconstructs a secret from the first threshold shares.
+// Returns the secret and an error if the shares are malformed or do not reconstruct a valid secret.
+func (s *shamirPrimeScheme) Combine(schemeShares []SchemeShare) ([]byte, error) {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := CombinePrime(shares[:schemeShares[0].Threshold])
+	if err != nil {
+		return nil, err
+	}
+
+	return intToSecret(value)
+}
+
+// Verify: checks that the shares are well-formed and, if there are more than threshold, consistent.
+// Returns an error if the verification fails.
+func (s *shamirPrimeScheme) Verify(schemeShares []SchemeShare) error {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return err
+	}
+
+	f, err := newPrimeField(s.modulus())
+	if err != nil {
+		return err
+	}
+
+	k := schemeShares[0].Threshold
+	xs := make([]*bigmod.Nat, k)
+	ys := make([]*bigmod.Nat, k)
+	for i := range xs {
+		xs[i] = f.small(shares[i].X)
+		if ys[i], err = f.element(shares[i].Y); err != nil {
+			return err
+		}
+	}
+
+	for _, share := range shares[k:] {
+		y, err := f.element(share.Y)
+		if err != nil {
+			return err
+		}
+		if f.interpolate(xs, ys, f.small(share.X)).Equal(y) == 0 {
+			return errors.New("inconsistent shares: share " + strconv.Itoa(share.X) + " does not match the others")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package sss
+
+import (
+	"errors"
+	"math/big"
+
+	"filippo.io/bigmod"
+)
+
+// Prime-field arithmetic works on fixed-size integers modulo the prime (filippo.io/bigmod), so that, as with the
+// binary fields, no branch or memory access depends on the values being combined; only the size of the prime shows
+// in timing. Values cross the *big.Int API of the package at its boundary only, where they are copied, not computed on.
+
+// primeField: struct to hold the precomputed modulus of GF(p).
+type primeField struct {
+	m   *bigmod.Modulus
+	inv []byte // p - 2, the exponent that inverts a non-zero element by Fermat's little theorem
+}
+
+// newPrimeField: prepares the arithmetic modulo an odd prime p.
+// Returns the field and an error if p is not greater than 2.
+func newPrimeField(p *big.Int) (*primeField, error) {
+	if p == nil || p.Cmp(big.NewInt(2)) <= 0 {
+		return nil, errors.New("modulus is not an odd prime")
+	}
+
+	m, err := bigmod.NewModulus(p.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &primeField{m: m, inv: new(big.Int).Sub(p, big.NewInt(2)).Bytes()}, nil
+}
+
+// element: converts an integer to an element of the field.
+// Returns the element and an error if the integer is not in [0, p).
+func (f *primeField) element(v *big.Int) (*bigmod.Nat, error) {
+	if v == nil || v.Sign() < 0 || v.BitLen() > f.m.BitLen() {
+		return nil, errors.New("value is outside the field")
+	}
+
+	x, err := bigmod.NewNat().SetBytes(v.FillBytes(make([]byte, f.m.Size())), f.m)
+	if err != nil {
+		return nil, errors.New("value is outside the field")
+	}
+	return x, nil
+}
+
+// elements: converts a list of integers to elements of the field.
+// Returns the elements and an error if an integer is not in [0, p).
+func (f *primeField) elements(values []*big.Int) ([]*bigmod.Nat, error) {
+	xs := make([]*bigmod.Nat, len(values))
+	for i, v := range values {
+		x, err := f.element(v)
+		if err != nil {
+			return nil, err
+		}
+		xs[i] = x
+	}
+	return xs, nil
+}
+
+// small: converts a public non-negative integer below p, such as a share index, to an element of the field.
+// Returns the element.
+func (f *primeField) small(v int) *bigmod.Nat {
+	return bigmod.NewNat().SetUint(uint(v)).ExpandFor(f.m)
+}
+
+// toInt: converts an element of the field back to an integer.
+// Returns the integer.
+func (f *primeField) toInt(x *bigmod.Nat) *big.Int {
+	return new(big.Int).SetBytes(x.Bytes(f.m))
+}
+
+// clone: copies an element of the field.
+// Returns the copy.
+func (f *primeField) clone(x *bigmod.Nat) *bigmod.Nat {
+	return f.small(0).Add(x, f.m)
+}
+
+// invert: computes the multiplicative inverse of an element as x^(p-2), with a fixed sequence of operations.
+// Returns the inverse, or 0 for the zero element.
+func (f *primeField) invert(x *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Exp(x, f.inv, f.m)
+}
+
+// swap: exchanges two elements if on is 1 and leaves them unchanged if it is 0, without branching.
+func (f *primeField) swap(x, y *bigmod.Nat, on uint) {
+	d := f.clone(y).Sub(x, f.m)
+	d.Mul(f.small(int(on)), f.m)
+	x.Add(d, f.m)
+	y.Sub(d, f.m)
+}
+
+// evaluate: evaluates the polynomial with the given coefficients at x using Horner's rule.
+// coeffs[0] is the constant term.
+// Returns the value of the polynomial at x.
+func (f *primeField) evaluate(coeffs []*bigmod.Nat, x *bigmod.Nat) *bigmod.Nat {
+	y := f.small(0)
+
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.Mul(x, f.m)
+		y.Add(coeffs[i], f.m)
+	}
+
+	return y
+}
+
+// interpolate: evaluates at x the unique polynomial passing through the points (xs[i], ys[i]).
+// The xs must be distinct.
+// Returns the value of the interpolated polynomial at x.
+func (f *primeField) interpolate(xs, ys []*bigmod.Nat, x *bigmod.Nat) *bigmod.Nat {
+	y := f.small(0)
+
+	for i := range xs {
+		num, den := f.small(1), f.small(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num.Mul(f.clone(x).Sub(xs[j], f.m), f.m)
+			den.Mul(f.clone(xs[i]).Sub(xs[j], f.m), f.m)
+		}
+
+		term := f.invert(den).Mul(num, f.m)
+		y.Add(term.Mul(ys[i], f.m), f.m)
+	}
+
+	return y
+}
+
+// dot: computes the dot product of two vectors of the same length.
+// Returns the dot product.
+func (f *primeField) dot(a, b []*bigmod.Nat) *bigmod.Nat {
+	sum := f.small(0)
+
+	for i := range a {
+		sum.Add(f.clone(a[i]).Mul(b[i], f.m), f.m)
+	}
+
+	return sum
+}
+
+// solve: solves the square linear system a * x = b by Gaussian elimination.
+// a and b are modified in place.
+// As in solveLinear, the pivot is moved into place with masked swaps and every row is eliminated whether or not its
+// entry is zero, so only whether the system is singular shows in timing.
+// Returns the unique solution and an error if the system is singular.
+func (f *primeField) solve(a [][]*bigmod.Nat, b []*bigmod.Nat) ([]*bigmod.Nat, error) {
+	size := len(a)
+
+	for col := 0; col < size; col++ {
+		// swap the first row at or below col with a non-zero entry in this column into place
+		var found uint
+		for r := col; r < size; r++ {
+			nonZero := 1 ^ a[r][col].IsZero()
+			swap := nonZero &^ found
+			for c := col; c < size; c++ {
+				f.swap(a[col][c], a[r][c], swap)
+			}
+			f.swap(b[col], b[r], swap)
+			found |= nonZero
+		}
+		if found == 0 {
+			return nil, errors.New("linear system is singular")
+		}
+
+		inv := f.invert(a[col][col])
+		for c := col; c < size; c++ {
+			a[col][c].Mul(inv, f.m)
+		}
+		b[col].Mul(inv, f.m)
+
+		for r := 0; r < size; r++ {
+			if r == col {
+				continue
+			}
+			factor := f.clone(a[r][col])
+			for c := col; c < size; c++ {
+				a[r][c].Sub(f.clone(factor).Mul(a[col][c], f.m), f.m)
+			}
+			b[r].Sub(f.clone(factor).Mul(b[col], f.m), f.m)
+		}
+	}
+
+	return b, nil
+}
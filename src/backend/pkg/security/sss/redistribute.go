@@ -0,0 +1,254 @@
+package sss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+)
+
+// sealInfo: HKDF context binding the derived keys to sub-share sealing.
+const sealInfo string = "CRYPTO-sss sealed sub-share v1"
+
+// SealOverhead: bytes a sealed sub-share's ciphertext adds to the sub-share's Y value, the epoch and the GCM tag.
+const SealOverhead int = 4 + 16
+
+// ErrSealedSubShare: returned when a sealed sub-share does not decrypt under the holder's key.
+var ErrSealedSubShare = errors.New("sealed sub-share does not decrypt: wrong key or tampered envelope")
+
+// SubShare: struct to hold the piece of an old share that an old holder sends to a new holder during redistribution.
+type SubShare struct {
+	From  byte  `json:"from" bson:"from"`   // X value of the old holder that produced the sub-share
+	Share Share `json:"share" bson:"share"` // sub-share addressed to the new holder Share.X
+}
+
+// SealedSubShare: struct to hold a sub-share encrypted to its new holder, so that a relay between the old and
+// the new holders learns only who sends to whom.
+type SealedSubShare struct {
+	From       byte   `json:"from" bson:"from"`             // X value of the old holder that produced the sub-share
+	To         byte   `json:"to" bson:"to"`                 // X value of the new holder able to open it
+	Ephemeral  []byte `json:"ephemeral" bson:"ephemeral"`   // single-use X25519 public key of the sender
+	Ciphertext []byte `json:"ciphertext" bson:"ciphertext"` // AES-256-GCM of the epoch and Y value, tag included
+}
+
+// sealAEAD: derives the AES-256-GCM instance of an envelope from the X25519 shared secret and both public keys.
+// Returns the AEAD and an error if the key agreement fails.
+func sealAEAD(private *ecdh.PrivateKey, public *ecdh.PublicKey, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+	defer securemem.Wipe(shared)
+
+	key, err := hkdf.Key(sha256.New, shared, append(append([]byte{}, ephemeral...), recipient...), sealInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer securemem.Wipe(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealSubShare: encrypts a sub-share to the X25519 public key of its new holder (ECIES with HKDF-SHA256 and
+// AES-256-GCM). Every envelope uses a fresh ephemeral key, so the all-zero nonce is never reused, and the X values
+// of both holders are authenticated so that a relay cannot redirect it.
+// Returns the sealed sub-share and an error if the key generation or encryption fails.
+func SealSubShare(subShare SubShare, recipient *ecdh.PublicKey) (SealedSubShare, error) {
+	if recipient == nil || recipient.Curve() != ecdh.X25519() {
+		return SealedSubShare{}, errors.New("recipient must be an X25519 public key")
+	}
+
+	seed := make([]byte, 32)
+	defer securemem.Wipe(seed)
+	if err := entropy.ReadFull(entropy.Default(), seed); err != nil {
+		return SealedSubShare{}, err
+	}
+	private, err := ecdh.X25519().NewPrivateKey(seed)
+	if err != nil {
+		return SealedSubShare{}, err
+	}
+	ephemeral := private.PublicKey().Bytes()
+
+	aead, err := sealAEAD(private, recipient, ephemeral, recipient.Bytes())
+	if err != nil {
+		return SealedSubShare{}, err
+	}
+
+	plaintext := binary.BigEndian.AppendUint32(nil, subShare.Share.Epoch)
+	plaintext = append(plaintext, subShare.Share.Y...)
+	defer securemem.Wipe(plaintext)
+
+	to := subShare.Share.X
+	return SealedSubShare{
+		From:       subShare.From,
+		To:         to,
+		Ephemeral:  ephemeral,
+		Ciphertext: aead.Seal(nil, make([]byte, aead.NonceSize()), plaintext, []byte{subShare.From, to}),
+	}, nil
+}
+
+// OpenSubShare: decrypts a sub-share sealed to the new holder owning the given X25519 private key.
+// Returns the sub-share, or ErrSealedSubShare if the envelope was not sealed to this key or was altered.
+func OpenSubShare(sealed SealedSubShare, key *ecdh.PrivateKey) (SubShare, error) {
+	if key == nil || key.Curve() != ecdh.X25519() {
+		return SubShare{}, errors.New("key must be an X25519 private key")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed.Ephemeral)
+	if err != nil {
+		return SubShare{}, ErrSealedSubShare
+	}
+
+	aead, err := sealAEAD(key, ephemeral, sealed.Ephemeral, key.PublicKey().Bytes())
+	if err != nil {
+		return SubShare{}, ErrSealedSubShare
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealed.Ciphertext, []byte{sealed.From, sealed.To})
+	if err != nil || len(plaintext) <= 4 {
+		return SubShare{}, ErrSealedSubShare
+	}
+	defer securemem.Wipe(plaintext)
+
+	share := Share{X: sealed.To, Y: append([]byte{}, plaintext[4:]...), Epoch: binary.BigEndian.Uint32(plaintext)}
+	return SubShare{From: sealed.From, Share: share}, nil
+}
+
+// newHolderXs: builds the X values 1..n of a new holder set.
+// Returns the X values and an error if the parameters are invalid.
+func newHolderXs(n, k int) ([]byte, error) {
+	if k < 2 {
+		return nil, errors.New("new threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("new number of shares cannot be less than the new threshold")
+	}
+	if n > MaxShares {
+		return nil, errors.New("new number of shares cannot exceed 255")
+	}
+
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = byte(i + 1)
+	}
+	return xs, nil
+}
+
+// Reshare: runs an old holder's step of a redistribution from (n, k) to (newN, newK).
+// The holder shares their own share with a fresh polynomial of degree newK-1 and sends one
+// sub-share to each of the new holders 1..newN (Desmedt-Jajodia), sealed with SealSubShare when it passes
+// through a relay. The secret is never rebuilt.
+// Returns one sub-share per new holder and an error if the parameters are invalid.
+func Reshare(share Share, newN, newK int) ([]SubShare, error) {
+	if share.X == 0 {
+		return nil, errors.New("share X value cannot be 0")
+	}
+	if len(share.Y) == 0 {
+		return nil, errors.New("share Y value cannot be empty")
+	}
+
+	xs, err := newHolderXs(newN, newK)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces, err := splitAt(share.Y, xs, newK)
+	if err != nil {
+		return nil, err
+	}
+
+	subShares := make([]SubShare, len(pieces))
+	for i, piece := range pieces {
+		piece.Epoch = share.Epoch
+		subShares[i] = SubShare{From: share.X, Share: piece}
+	}
+
+	return subShares, nil
+}
+
+// CombineSubShares: runs a new holder's step of a redistribution.
+// The sub-shares received from a set of at least k old holders are weighted with the Lagrange
+// coefficients of that set at 0. Every new holder must use sub-shares from the same set of old holders.
+// The new share starts a new epoch, so it cannot be combined with the old shares.
+// Returns the new holder's share and an error if the sub-shares are malformed.
+func CombineSubShares(subShares []SubShare) (Share, error) {
+	if len(subShares) < 2 {
+		return Share{}, errors.New("at least 2 sub-shares are required")
+	}
+
+	first := subShares[0].Share
+	if first.Epoch == math.MaxUint32 {
+		return Share{}, errors.New("share epoch counter is exhausted")
+	}
+
+	// the senders play the role of interpolation points
+	senders := make([]Share, len(subShares))
+	for i, subShare := range subShares {
+		if subShare.Share.X != first.X {
+			return Share{}, errors.New("sub-shares are addressed to different holders")
+		}
+		senders[i] = Share{X: subShare.From, Y: subShare.Share.Y, Epoch: subShare.Share.Epoch}
+	}
+	if err := validateShares(senders); err != nil {
+		return Share{}, err
+	}
+
+	xs := make([]byte, len(senders))
+	for i, sender := range senders {
+		xs[i] = sender.X
+	}
+	coeffs := lagrangeCoefficients(xs, 0)
+
+	share := Share{X: first.X, Y: make([]byte, len(first.Y)), Epoch: first.Epoch + 1}
+	for i, sender := range senders {
+		for b := range share.Y {
+			share.Y[b] = gfAdd(share.Y[b], gfMul(coeffs[i], sender.Y[b]))
+		}
+	}
+
+	return share, nil
+}
+
+// Redistribute: runs a complete redistribution of the given old shares to newN holders with threshold newK.
+// At least k old shares must be supplied for the new shares to encode the same secret.
+// Returns the new shares and an error if the shares or parameters are invalid.
+func Redistribute(shares []Share, newN, newK int) ([]Share, error) {
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+	if _, err := newHolderXs(newN, newK); err != nil {
+		return nil, err
+	}
+
+	// received[j] collects the sub-shares every old holder sent to new holder j
+	received := make([][]SubShare, newN)
+	for _, share := range shares {
+		subShares, err := Reshare(share, newN, newK)
+		if err != nil {
+			return nil, err
+		}
+		for j, subShare := range subShares {
+			received[j] = append(received[j], subShare)
+		}
+	}
+
+	newShares := make([]Share, newN)
+	for j := range newShares {
+		share, err := CombineSubShares(received[j])
+		if err != nil {
+			return nil, err
+		}
+		newShares[j] = share
+	}
+
+	return newShares, nil
+}
@@ -0,0 +1,116 @@
+package sss
+
+import (
+	"errors"
+	"math"
+)
+
+// RefreshContribution: generates one holder's contribution to a proactive refresh round.
+// The holder picks a random polynomial of degree k-1 with a zero constant term for every secret byte
+// and evaluates it at the X value of every holder, so that adding the contributions re-randomizes the
+// shares without changing the secret (Herzberg et al.).
+// Returns one sub-share per holder, tagged with the epoch being refreshed, and an error if the parameters are invalid.
+func RefreshContribution(xs []byte, epoch uint32, k, length int) ([]Share, error) {
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	// a higher degree than the holders can interpolate would leave the refreshed shares unable to reconstruct
+	if len(xs) < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if length <= 0 {
+		return nil, errors.New("share length must be positive")
+	}
+
+	seen := make(map[byte]bool, len(xs))
+	for _, x := range xs {
+		if x == 0 {
+			return nil, errors.New("share X value cannot be 0")
+		}
+		if seen[x] {
+			return nil, errors.New("duplicate share X value")
+		}
+		seen[x] = true
+	}
+
+	// sharing an all-zero secret yields polynomials with a zero constant term
+	contribution, err := splitAt(make([]byte, length), xs, k)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contribution {
+		contribution[i].Epoch = epoch
+	}
+
+	return contribution, nil
+}
+
+// ApplyRefresh: adds the sub-shares addressed to a holder to their share, moving it to the next epoch.
+// Every contribution must target the holder's X value and the holder's current epoch.
+// Returns the refreshed share and an error if a contribution does not belong to this holder or round.
+func ApplyRefresh(share Share, contributions []Share) (Share, error) {
+	if len(contributions) == 0 {
+		return Share{}, errors.New("at least 1 contribution is required")
+	}
+	if share.Epoch == math.MaxUint32 {
+		return Share{}, errors.New("share epoch counter is exhausted")
+	}
+
+	refreshed := Share{X: share.X, Y: make([]byte, len(share.Y)), Epoch: share.Epoch + 1}
+	copy(refreshed.Y, share.Y)
+
+	for _, contribution := range contributions {
+		if contribution.X != share.X {
+			return Share{}, errors.New("contribution is addressed to another holder")
+		}
+		if contribution.Epoch != share.Epoch {
+			return Share{}, errors.New("contribution belongs to a different epoch")
+		}
+		if len(contribution.Y) != len(share.Y) {
+			return Share{}, errors.New("contribution length does not match the share")
+		}
+
+		for b := range refreshed.Y {
+			refreshed.Y[b] = gfAdd(refreshed.Y[b], contribution.Y[b])
+		}
+	}
+
+	return refreshed, nil
+}
+
+// Refresh: runs a complete refresh round in which every holder contributes.
+// The secret and the threshold stay the same, while the old shares can no longer be combined with the new ones.
+// Returns the refreshed shares and an error if the shares are malformed.
+func Refresh(shares []Share, k int) ([]Share, error) {
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		xs[i] = share.X
+	}
+
+	// received[i] collects the sub-shares every holder sent to holder i
+	received := make([][]Share, len(shares))
+	for range shares {
+		contribution, err := RefreshContribution(xs, shares[0].Epoch, k, len(shares[0].Y))
+		if err != nil {
+			return nil, err
+		}
+		for i, subShare := range contribution {
+			received[i] = append(received[i], subShare)
+		}
+	}
+
+	refreshed := make([]Share, len(shares))
+	for i, share := range shares {
+		newShare, err := ApplyRefresh(share, received[i])
+		if err != nil {
+			return nil, err
+		}
+		refreshed[i] = newShare
+	}
+
+	return refreshed, nil
+}
@@ -0,0 +1,218 @@
+package sss
+
+import (
+	"errors"
+	"strconv"
+)
+
+// RepairPiece: struct to hold a masked value exchanged while repairing or enrolling a share.
+type RepairPiece struct {
+	From   byte   `json:"from" bson:"from"`     // X value of the sender
+	To     byte   `json:"to" bson:"to"`         // X value of the receiver, a helper or the target
+	Target byte   `json:"target" bson:"target"` // X value of the share being rebuilt
+	Epoch  uint32 `json:"epoch" bson:"epoch"`   // epoch of the helpers' shares
+	Value  []byte `json:"value" bson:"value"`
+}
+
+// validateRepairSet: checks the helper set and the target of a repair.
+// Returns an error if the helpers are fewer than 2, repeated, or include the target.
+func validateRepairSet(helperXs []byte, targetX byte) error {
+	if targetX == 0 {
+		return errors.New("target X value cannot be 0")
+	}
+	if len(helperXs) < 2 {
+		return errors.New("at least 2 helpers are required")
+	}
+
+	seen := make(map[byte]bool, len(helperXs))
+	for _, x := range helperXs {
+		if x == 0 {
+			return errors.New("helper X value cannot be 0")
+		}
+		if x == targetX {
+			return errors.New("target cannot be one of the helpers")
+		}
+		if seen[x] {
+			return errors.New("duplicate helper X value")
+		}
+		seen[x] = true
+	}
+
+	return nil
+}
+
+// collectPieces: checks that exactly one piece from every helper arrived at the receiver.
+// Returns the pieces ordered like the helpers and an error if a piece is missing, repeated or misaddressed.
+func collectPieces(receiver byte, helperXs []byte, targetX byte, pieces []RepairPiece) ([]RepairPiece, error) {
+	if len(pieces) != len(helperXs) {
+		return nil, errors.New("expected " + strconv.Itoa(len(helperXs)) + " pieces, got " + strconv.Itoa(len(pieces)))
+	}
+
+	byHelper := make(map[byte]RepairPiece, len(pieces))
+	for _, piece := range pieces {
+		if piece.To != receiver || piece.Target != targetX {
+			return nil, errors.New("piece from " + strconv.Itoa(int(piece.From)) + " is addressed to another repair")
+		}
+		if _, ok := byHelper[piece.From]; ok {
+			return nil, errors.New("duplicate piece from " + strconv.Itoa(int(piece.From)))
+		}
+		if len(piece.Value) == 0 || len(piece.Value) != len(pieces[0].Value) {
+			return nil, errors.New("pieces must all have the same non-zero length")
+		}
+		if piece.Epoch != pieces[0].Epoch {
+			return nil, errors.New("pieces belong to different epochs")
+		}
+		byHelper[piece.From] = piece
+	}
+
+	ordered := make([]RepairPiece, len(helperXs))
+	for i, x := range helperXs {
+		piece, ok := byHelper[x]
+		if !ok {
+			return nil, errors.New("missing piece from helper " + strconv.Itoa(int(x)))
+		}
+		ordered[i] = piece
+	}
+
+	return ordered, nil
+}
+
+// RepairContribute: runs a helper's first step of a repair.
+// The helper weights their share with its Lagrange coefficient at the target and splits the result
+// into random additive pieces, one for each helper, so that no single piece reveals anything.
+// Returns one piece per helper and an error if the helper set is invalid.
+func RepairContribute(share Share, helperXs []byte, targetX byte) ([]RepairPiece, error) {
+	if err := validateRepairSet(helperXs, targetX); err != nil {
+		return nil, err
+	}
+	if len(share.Y) == 0 {
+		return nil, errors.New("share Y value cannot be empty")
+	}
+
+	self := -1
+	for i, x := range helperXs {
+		if x == share.X {
+			self = i
+		}
+	}
+	if self < 0 {
+		return nil, errors.New("share does not belong to one of the helpers")
+	}
+
+	weight := lagrangeCoefficients(helperXs, targetX)[self]
+
+	pieces := make([]RepairPiece, len(helperXs))
+	remainder := make([]byte, len(share.Y))
+	for b := range remainder {
+		remainder[b] = gfMul(weight, share.Y[b])
+	}
+
+	// all pieces but the last are random masks, the last one makes them sum to the weighted share
+	for i, x := range helperXs {
+		pieces[i] = RepairPiece{From: share.X, To: x, Target: targetX, Epoch: share.Epoch}
+		if i == len(helperXs)-1 {
+			pieces[i].Value = remainder
+			break
+		}
+
+		mask, err := random(len(remainder))
+		if err != nil {
+			return nil, err
+		}
+		for b := range remainder {
+			remainder[b] = gfAdd(remainder[b], mask[b])
+		}
+		pieces[i].Value = mask
+	}
+
+	return pieces, nil
+}
+
+// RepairAggregate: runs a helper's second step of a repair.
+// The helper adds up the pieces every helper sent them and forwards the sum to the target.
+// Returns the piece for the target and an error if a piece is missing or misaddressed.
+func RepairAggregate(helperX byte, helperXs []byte, targetX byte, pieces []RepairPiece) (RepairPiece, error) {
+	if err := validateRepairSet(helperXs, targetX); err != nil {
+		return RepairPiece{}, err
+	}
+
+	ordered, err := collectPieces(helperX, helperXs, targetX, pieces)
+	if err != nil {
+		return RepairPiece{}, err
+	}
+
+	sum := RepairPiece{
+		From:   helperX,
+		To:     targetX,
+		Target: targetX,
+		Epoch:  ordered[0].Epoch,
+		Value:  make([]byte, len(ordered[0].Value)),
+	}
+	for _, piece := range ordered {
+		for b := range sum.Value {
+			sum.Value[b] = gfAdd(sum.Value[b], piece.Value[b])
+		}
+	}
+
+	return sum, nil
+}
+
+// RepairRecover: runs the target's step of a repair, adding up the sums forwarded by the helpers.
+// Returns the rebuilt share for the target X and an error if a sum is missing or misaddressed.
+func RepairRecover(targetX byte, helperXs []byte, sums []RepairPiece) (Share, error) {
+	if err := validateRepairSet(helperXs, targetX); err != nil {
+		return Share{}, err
+	}
+
+	ordered, err := collectPieces(targetX, helperXs, targetX, sums)
+	if err != nil {
+		return Share{}, err
+	}
+
+	share := Share{X: targetX, Y: make([]byte, len(ordered[0].Value)), Epoch: ordered[0].Epoch}
+	for _, sum := range ordered {
+		for b := range share.Y {
+			share.Y[b] = gfAdd(share.Y[b], sum.Value[b])
+		}
+	}
+
+	return share, nil
+}
+
+// Repair: runs a complete repair in which the given helpers rebuild the share at targetX.
+// The target may be a lost share's X value or a fresh one, which enrolls a new custodian.
+// At least k helpers must take part for the rebuilt share to lie on the sharing polynomial.
+// Returns the rebuilt share and an error if the helpers or the target are invalid.
+func Repair(helpers []Share, targetX byte) (Share, error) {
+	if err := validateShares(helpers); err != nil {
+		return Share{}, err
+	}
+
+	helperXs := make([]byte, len(helpers))
+	for i, helper := range helpers {
+		helperXs[i] = helper.X
+	}
+
+	// received[j] collects the pieces every helper sent to helper j
+	received := make([][]RepairPiece, len(helpers))
+	for _, helper := range helpers {
+		pieces, err := RepairContribute(helper, helperXs, targetX)
+		if err != nil {
+			return Share{}, err
+		}
+		for j, piece := range pieces {
+			received[j] = append(received[j], piece)
+		}
+	}
+
+	sums := make([]RepairPiece, len(helpers))
+	for j, helperX := range helperXs {
+		sum, err := RepairAggregate(helperX, helperXs, targetX, received[j])
+		if err != nil {
+			return Share{}, err
+		}
+		sums[j] = sum
+	}
+
+	return RepairRecover(targetX, helperXs, sums)
+}
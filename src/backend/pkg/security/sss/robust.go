@@ -0,0 +1,167 @@
+package sss
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+)
+
+// ErrInconsistentShares: returned when the shares disagree but there is not enough redundancy to tell which ones are bad.
+var ErrInconsistentShares = errors.New("inconsistent shares detected")
+
+// ErrTooManyBadShares: returned when more shares are bad than Berlekamp-Welch decoding can correct.
+var ErrTooManyBadShares = errors.New("too many inconsistent shares to correct")
+
+// RobustResult: struct to hold the outcome of a robust reconstruction.
+type RobustResult struct {
+	Secret   []byte // reconstructed secret
+	Rejected []byte // X values of the shares found inconsistent, in increasing order
+}
+
+// berlekampWelch: decodes one byte position of m shares as a Reed-Solomon codeword of dimension k.
+// It looks for an error locator E of degree e and Q of degree below e+k with Q(x_i) = y_i * E(x_i),
+// so that the sharing polynomial is P = Q / E. The system and its solution are derived from the share values,
+// so they are wiped before returning; the caller wipes P.
+// Returns the polynomial P and false if no polynomial is within e errors of the received values.
+func berlekampWelch(xs, ys []byte, k, e int) ([]byte, bool) {
+	m := len(xs)
+	unknowns := 2*e + k // E_0..E_{e-1} followed by Q_0..Q_{e+k-1}
+
+	a := make([][]byte, m)
+	b := make([]byte, m)
+	defer func() {
+		for _, row := range a {
+			securemem.Wipe(row)
+		}
+		securemem.Wipe(b)
+	}()
+	for i := range xs {
+		a[i] = make([]byte, unknowns)
+
+		power := byte(1)
+		for j := 0; j < e+k; j++ {
+			if j < e {
+				a[i][j] = gfMul(ys[i], power)
+			}
+			a[i][e+j] = power
+			power = gfMul(power, xs[i])
+		}
+
+		// the monic term y_i * x_i^e moves to the right-hand side
+		b[i] = gfMul(ys[i], expPow(xs[i], e))
+	}
+
+	solution, ok := solveLinear(a, b)
+	if !ok {
+		return nil, false
+	}
+	defer securemem.Wipe(solution)
+
+	locator := append(append([]byte{}, solution[:e]...), 1)
+	quotient, remainder := polyDivide(solution[e:], locator)
+	defer securemem.Wipe(locator)
+	defer securemem.Wipe(quotient)
+	defer securemem.Wipe(remainder)
+
+	for _, r := range remainder {
+		if r != 0 {
+			return nil, false
+		}
+	}
+
+	poly := make([]byte, k)
+	copy(poly, quotient)
+	return poly, true
+}
+
+// expPow: raises x to a non-negative integer power in GF(2^8).
+// Returns x^exp.
+func expPow(x byte, exp int) byte {
+	result := byte(1)
+	for i := 0; i < exp; i++ {
+		result = gfMul(result, x)
+	}
+	return result
+}
+
+// CombineRobust: reconstructs a secret from m >= k shares without assuming that every holder is honest.
+// Each byte position is decoded as a Reed-Solomon codeword with Berlekamp-Welch, which corrects up to
+// floor((m-k)/2) bad shares and reports them. With fewer spare shares the inconsistency is only detected.
+// The values and polynomials derived from the shares along the way are wiped, as is a partial secret on failure.
+// Returns the secret with the rejected shares, ErrInconsistentShares if bad shares were detected but cannot be
+// identified, or ErrTooManyBadShares if they exceed the correction capacity.
+func CombineRobust(shares []Share, k int) (*RobustResult, error) {
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+	if len(shares) < k {
+		return nil, errors.New("not enough shares: have " + strconv.Itoa(len(shares)) + ", need " + strconv.Itoa(k))
+	}
+
+	if checkConsistency(shares, k) == nil {
+		secret, err := Combine(shares[:k])
+		if err != nil {
+			return nil, err
+		}
+		return &RobustResult{Secret: secret, Rejected: []byte{}}, nil
+	}
+
+	e := (len(shares) - k) / 2
+	if e == 0 {
+		return nil, ErrInconsistentShares
+	}
+
+	xs := make([]byte, len(shares))
+	ys := make([]byte, len(shares))
+	defer securemem.Wipe(ys)
+	for i, share := range shares {
+		xs[i] = share.X
+	}
+
+	secret := make([]byte, len(shares[0].Y))
+	rejected := make(map[byte]bool)
+	for b := range secret {
+		for i, share := range shares {
+			ys[i] = share.Y[b]
+		}
+
+		poly, ok := berlekampWelch(xs, ys, k, e)
+		if !ok {
+			securemem.Wipe(secret)
+			return nil, ErrTooManyBadShares
+		}
+
+		bad := 0
+		for i := range xs {
+			if evaluate(poly, xs[i]) != ys[i] {
+				rejected[xs[i]] = true
+				bad++
+			}
+		}
+		secret[b] = poly[0]
+		securemem.Wipe(poly)
+
+		if bad > e {
+			securemem.Wipe(secret)
+			return nil, ErrTooManyBadShares
+		}
+	}
+
+	if len(rejected) > e {
+		securemem.Wipe(secret)
+		return nil, ErrTooManyBadShares
+	}
+
+	result := &RobustResult{Secret: secret, Rejected: make([]byte, 0, len(rejected))}
+	for x := range rejected {
+		result.Rejected = append(result.Rejected, x)
+	}
+	sort.Slice(result.Rejected, func(i, j int) bool { return result.Rejected[i] < result.Rejected[j] })
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package sss
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Scheme: interface specifying the methods for a secret-sharing algorithm.
+type Scheme interface {
+	Name() string
+	Params() SchemeParams
+	Split(secret []byte, n, k int) ([]SchemeShare, error)
+	Combine(shares []SchemeShare) ([]byte, error)
+	Verify(shares []SchemeShare) error
+}
+
+// SchemeParams: struct to hold the public parameters of a scheme.
+type SchemeParams struct {
+	Field        string `json:"field"`          // algebraic structure the scheme works in
+	MaxShares    int    `json:"max_shares"`     // maximum number of shares per split
+	MaxSecretLen int    `json:"max_secret_len"` // maximum secret length in bytes, 0 if unbounded
+}
+
+// SchemeShare: struct to hold a share produced by any registered scheme.
+// The scheme ID lets CombineShares pick the right algorithm without the caller knowing it.
+type SchemeShare struct {
+	Scheme    SchemeID `json:"scheme" bson:"scheme"`
+	Threshold int      `json:"threshold" bson:"threshold"`
+	Index     int      `json:"index" bson:"index"`
+	Payload   []byte   `json:"payload" bson:"payload"` // scheme-specific share data
+}
+
+type SchemeID int
+
+const (
+	ShamirGF256 SchemeID = iota
+	ShamirPrime
+	Blakley
+	Mignotte
+	AsmuthBloom
+	ShamirGF65536
+)
+
+var Schemes = map[SchemeID]Scheme{
+	ShamirGF256:   &shamirGF256Scheme{},
+	ShamirPrime:   &shamirPrimeScheme{prime: Mersenne521},
+	Blakley:       &blakleyScheme{prime: Mersenne521},
+	Mignotte:      &crtScheme{id: Mignotte},
+	AsmuthBloom:   &crtScheme{id: AsmuthBloom, asmuthBloom: true},
+	ShamirGF65536: &shamirGF65536Scheme{},
+}
+
+// LookupScheme: looks up a registered scheme by its ID.
+// Returns the scheme and an error if no scheme is registered under the ID.
+func LookupScheme(id SchemeID) (Scheme, error) {
+	scheme, ok := Schemes[id]
+	if !ok {
+		return nil, errors.New("unknown secret sharing scheme: " + strconv.Itoa(int(id)))
+	}
+	return scheme, nil
+}
+
+// SplitWith: splits a secret using the registered scheme with the given ID.
+// Returns the shares and an error if the scheme is unknown or the split fails.
+func SplitWith(id SchemeID, secret []byte, n, k int) ([]SchemeShare, error) {
+	scheme, err := LookupScheme(id)
+	if err != nil {
+		return nil, err
+	}
+	return scheme.Split(secret, n, k)
+}
+
+// schemeOf: finds the scheme the shares were produced with.
+// Returns the scheme and an error if the shares are empty, mixed, or from an unknown scheme.
+func schemeOf(shares []SchemeShare) (Scheme, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("at least 1 share is required")
+	}
+
+	for _, share := range shares {
+		if share.Scheme != shares[0].Scheme {
+			return nil, errors.New("shares were produced by different schemes")
+		}
+	}
+
+	return LookupScheme(shares[0].Scheme)
+}
+
+// CombineShares: reconstructs a secret using the scheme recorded in the shares.
+// Returns the secret and an error if the scheme is unknown or the reconstruction fails.
+func CombineShares(shares []SchemeShare) ([]byte, error) {
+	scheme, err := schemeOf(shares)
+	if err != nil {
+		return nil, err
+	}
+	return scheme.Combine(shares)
+}
+
+// VerifyShares: checks the shares using the scheme recorded in them.
+// Returns an error if the scheme is unknown or the shares are inconsistent.
+func VerifyShares(shares []SchemeShare) error {
+	scheme, err := schemeOf(shares)
+	if err != nil {
+		return err
+	}
+	return scheme.Verify(shares)
+}
+
+// checkSchemeShares: checks the metadata common to all schemes.
+// Returns an error if a share belongs to another scheme, the threshold is invalid or differs,
+// an index is out of range or repeated, or there are fewer shares than the threshold.
+func checkSchemeShares(id SchemeID, shares []SchemeShare, maxShares int) error {
+	if len(shares) == 0 {
+		return errors.New("at least 1 share is required")
+	}
+
+	threshold := shares[0].Threshold
+	if threshold < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.Scheme != id {
+			return errors.New("share was produced by a different scheme")
+		}
+		if share.Threshold != threshold {
+			return errors.New("shares have different thresholds")
+		}
+		if share.Index < 1 || share.Index > maxShares {
+			return errors.New("share index out of range: " + strconv.Itoa(share.Index))
+		}
+		if seen[share.Index] {
+			return errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+	}
+
+	if len(shares) < threshold {
+		return errors.New("not enough shares: have " + strconv.Itoa(len(shares)) + ", need " + strconv.Itoa(threshold))
+	}
+
+	return nil
+}
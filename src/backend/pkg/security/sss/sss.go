@@ -0,0 +1,307 @@
+package sss
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+)
+
+// MaxShares: maximum number of shares supported by GF(2^8), one per non-zero field element.
+const MaxShares int = 255
+
+// Share: struct to hold a single share of a secret.
+type Share struct {
+	X     byte   `json:"x" bson:"x"`         // evaluation point, never 0
+	Y     []byte `json:"y" bson:"y"`         // polynomial values at X, one per secret byte
+	Epoch uint32 `json:"epoch" bson:"epoch"` // number of refreshes the share went through
+}
+
+// random: generates random bytes to be used as polynomial coefficients.
+// Returns the random bytes and an error if the generation fails.
+func random(len int) ([]byte, error) {
+	randomBytes := make([]byte, len)
+
+	err := entropy.Read(randomBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return randomBytes, nil
+}
+
+// Split: splits a secret into n shares, any k of which can reconstruct it.
+// Each byte of the secret is shared independently using a random polynomial of degree k-1 over GF(2^8).
+// Returns the shares and an error if the parameters are invalid or the random generation fails.
+func Split(secret []byte, n, k int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if n > MaxShares {
+		return nil, errors.New("number of shares cannot exceed 255")
+	}
+
+	xs := make([]byte, n)
+	for i := range xs {
+		xs[i] = byte(i + 1)
+	}
+
+	return splitAt(secret, xs, k)
+}
+
+// splitAt: shares a secret byte-wise with random polynomials of degree k-1 evaluated at the given X values.
+// Returns one share per X value and an error if there are fewer X values than k or the random generation fails.
+func splitAt(secret []byte, xs []byte, k int) ([]Share, error) {
+	if len(xs) < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+
+	shares := make([]Share, len(xs))
+	for i, x := range xs {
+		shares[i] = Share{X: x, Y: make([]byte, len(secret))}
+	}
+
+	// the coefficients determine the secret, so they are wiped rather than left to the garbage collector
+	coeffs := make([]byte, k)
+	defer securemem.Wipe(coeffs)
+	for b := range secret {
+		randomCoeffs, err := random(k - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		coeffs[0] = secret[b]
+		copy(coeffs[1:], randomCoeffs)
+		securemem.Wipe(randomCoeffs)
+
+		for i := range shares {
+			shares[i].Y[b] = evaluate(coeffs, shares[i].X)
+		}
+	}
+
+	return shares, nil
+}
+
+// validateShares: checks that the shares can be interpolated together.
+// Returns an error if there are too few shares, their lengths or epochs differ, or their X values are zero or duplicated.
+func validateShares(shares []Share) error {
+	if len(shares) < 2 {
+		return errors.New("at least 2 shares are required")
+	}
+
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if share.X == 0 {
+			return errors.New("share X value cannot be 0")
+		}
+		if seen[share.X] {
+			return errors.New("duplicate share X value")
+		}
+		seen[share.X] = true
+
+		if len(share.Y) == 0 {
+			return errors.New("share Y value cannot be empty")
+		}
+		if len(share.Y) != len(shares[0].Y) {
+			return errors.New("shares must all have the same length")
+		}
+		if share.Epoch != shares[0].Epoch {
+			return errors.New("shares belong to different epochs")
+		}
+	}
+
+	return nil
+}
+
+// Combine: reconstructs a secret from its shares using Lagrange interpolation at 0.
+// The caller must supply at least as many shares as the threshold used at split time,
+// otherwise the result is an unrelated value.
+// Returns the secret and an error if the shares are malformed.
+func Combine(shares []Share) ([]byte, error) {
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, len(shares[0].Y))
+	combineInto(secret, shares)
+	return secret, nil
+}
+
+// CombineBuffer: reconstructs a secret like Combine, writing it into locked memory instead of the heap.
+// The caller must close the returned buffer.
+// Returns the buffer and an error if the shares are malformed or the buffer cannot be allocated.
+func CombineBuffer(shares []Share) (*securemem.SecretBuffer, error) {
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+
+	secret, err := securemem.New(len(shares[0].Y))
+	if err != nil {
+		return nil, err
+	}
+	combineInto(secret.Bytes(), shares)
+	return secret, nil
+}
+
+// combineInto: interpolates validated shares at 0 into secret, which has the length of the share values.
+func combineInto(secret []byte, shares []Share) {
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		xs[i] = share.X
+	}
+
+	coeffs := lagrangeCoefficients(xs, 0)
+	for b := range secret {
+		for i, share := range shares {
+			secret[b] = gfAdd(secret[b], gfMul(share.Y[b], coeffs[i]))
+		}
+	}
+}
+
+// Wipe: zeroes the share value, once it has been stored or sent and no copy is needed in memory.
+func (s Share) Wipe() {
+	securemem.Wipe(s.Y)
+}
+
+// Interpolate: evaluates at x the byte-wise polynomials through the points (xs[i], ys[i]).
+// Unlike Combine, any X value including 0 may be used, which lets share formats over the same field
+// (such as SLIP-0039) reuse the arithmetic with their own evaluation points.
+// Returns the interpolated bytes and an error if there are no points, the X values repeat or the Y values differ in length.
+func Interpolate(xs []byte, ys [][]byte, x byte) ([]byte, error) {
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return nil, errors.New("interpolation needs one y value per x value")
+	}
+
+	seen := make(map[byte]bool, len(xs))
+	for i, xi := range xs {
+		if seen[xi] {
+			return nil, errors.New("duplicate x value: " + strconv.Itoa(int(xi)))
+		}
+		seen[xi] = true
+		if len(ys[i]) != len(ys[0]) {
+			return nil, errors.New("y values must all have the same length")
+		}
+	}
+
+	coeffs := lagrangeCoefficients(xs, x)
+	out := make([]byte, len(ys[0]))
+	for b := range out {
+		for i, coeff := range coeffs {
+			out[b] = gfAdd(out[b], gfMul(ys[i][b], coeff))
+		}
+	}
+
+	return out, nil
+}
+
+// checkConsistency: checks that every share lies on the polynomial defined by the first k shares.
+// Returns an error if a share is inconsistent with the others.
+func checkConsistency(shares []Share, k int) error {
+	if len(shares) <= k {
+		return nil
+	}
+
+	xs := make([]byte, k)
+	ys := make([]byte, k)
+	defer securemem.Wipe(ys)
+	for i := range xs {
+		xs[i] = shares[i].X
+	}
+
+	for b := range shares[0].Y {
+		for i := range ys {
+			ys[i] = shares[i].Y[b]
+		}
+		for _, share := range shares[k:] {
+			if interpolate(xs, ys, share.X) != share.Y[b] {
+				return errors.New("inconsistent shares: share " + strconv.Itoa(int(share.X)) + " does not match the others")
+			}
+		}
+	}
+
+	return nil
+}
+
+// shamirGF256Scheme: Scheme adapter for the GF(2^8) Shamir implementation.
+type shamirGF256Scheme struct{}
+
+// Name: returns the registry name of the scheme.
+func (s *shamirGF256Scheme) Name() string {
+	return "shamir-gf256"
+}
+
+// Params: returns the public parameters of the scheme.
+func (s *shamirGF256Scheme) Params() SchemeParams {
+	return SchemeParams{Field: "GF(2^8)", MaxShares: MaxShares}
+}
+
+// Split: splits a secret into n shares with threshold k.
+// Returns the shares and an error if the split fails.
+func (s *shamirGF256Scheme) Split(secret []byte, n, k int) ([]SchemeShare, error) {
+	shares, err := Split(secret, n, k)
+	if err != nil {
+		return nil, err
+	}
+
+	schemeShares := make([]SchemeShare, len(shares))
+	for i, share := range shares {
+		schemeShares[i] = SchemeShare{
+			Scheme:    ShamirGF256,
+			Threshold: k,
+			Index:     int(share.X),
+			Payload:   share.Y,
+		}
+	}
+
+	return schemeShares, nil
+}
+
+// shares: converts scheme shares back to GF(2^8) shares.
+// Returns the shares and an error if they are malformed.
+func (s *shamirGF256Scheme) shares(schemeShares []SchemeShare) ([]Share, error) {
+	if err := checkSchemeShares(ShamirGF256, schemeShares, MaxShares); err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, len(schemeShares))
+	for i, schemeShare := range schemeShares {
+		shares[i] = Share{X: byte(schemeShare.Index), Y: schemeShare.Payload}
+	}
+
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// Combine: reconstructs a secret from the shares, correcting bad shares when more than threshold are supplied.
+// Returns the secret and an error if the shares are malformed or too many of them are bad.
+func (s *shamirGF256Scheme) Combine(schemeShares []SchemeShare) ([]byte, error) {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := CombineRobust(shares, schemeShares[0].Threshold)
+	if err != nil {
+		return nil, err
+	}
+	return result.Secret, nil
+}
+
+// Verify: checks that the shares are well-formed and, if there are more than threshold, consistent.
+// Returns an error if the verification fails.
+func (s *shamirGF256Scheme) Verify(schemeShares []SchemeShare) error {
+	shares, err := s.shares(schemeShares)
+	if err != nil {
+		return err
+	}
+	return checkConsistency(shares, schemeShares[0].Threshold)
+}
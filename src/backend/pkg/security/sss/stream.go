@@ -0,0 +1,370 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"strconv"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// Streaming share format, all integers big-endian. Every share stream starts with a header
+//
+//	magic "SSTR" | version u8 | threshold u8 | x u8 | chunk size u32 | stream ID [16] | MAC key share [32]
+//
+// followed by one record per chunk of the input
+//
+//	sequence u64 | flags u8 | length u32 | share of (chunk | tag)
+//
+// The tag is HMAC-SHA256 under the Shamir-shared MAC key over the stream ID, threshold, sequence number, flags and
+// chunk, so tampered, reordered or spliced chunks fail once reconstructed. The last record carries the final flag;
+// a stream that ends without it was truncated.
+const (
+	streamMagic   string = "SSTR"
+	StreamVersion byte   = 1
+
+	// StreamChunkSize: number of input bytes per chunk written by a Splitter.
+	StreamChunkSize int = 64 << 10
+	// MaxStreamChunkSize: largest chunk size a Combiner accepts, which bounds its memory use.
+	MaxStreamChunkSize int = 1 << 20
+
+	streamIDLen     int = 16
+	streamKeyLen    int = 32
+	streamHeaderLen int = len(streamMagic) + 1 + 1 + 1 + 4 + streamIDLen + streamKeyLen
+	streamRecordLen int = 8 + 1 + 4
+	streamTagLen    int = sha256.Size
+
+	chunkFinal byte = 1 << 0
+)
+
+// ErrStreamTruncated: returned when a share stream ends before its final chunk.
+var ErrStreamTruncated = errors.New("share stream is truncated")
+
+// ErrChunkOutOfOrder: returned when a share stream delivers a chunk other than the next one expected.
+var ErrChunkOutOfOrder = errors.New("chunk is out of order")
+
+// ErrStreamMismatch: returned when share streams belong to different splits or disagree about a chunk.
+var ErrStreamMismatch = errors.New("share streams do not belong together")
+
+// ErrChunkAuthentication: returned when a reconstructed chunk fails its authentication tag.
+var ErrChunkAuthentication = errors.New("chunk failed authentication")
+
+// StreamError: error returned by a Combiner, locating the failure within the share streams.
+type StreamError struct {
+	Reader int    // position of the offending reader, -1 if the failure cannot be pinned on one
+	Chunk  uint64 // sequence number of the chunk being combined
+	Err    error  // one of the stream sentinel errors, or the underlying read error
+}
+
+// Error: returns the error message.
+func (e *StreamError) Error() string {
+	where := "chunk " + strconv.FormatUint(e.Chunk, 10)
+	if e.Reader >= 0 {
+		where = "reader " + strconv.Itoa(e.Reader) + ", " + where
+	}
+	return where + ": " + e.Err.Error()
+}
+
+// Unwrap: returns the underlying error.
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// chunkMAC: starts the authentication tag of a chunk.
+// Returns the MAC with the stream ID, threshold, sequence number and flags already written.
+func chunkMAC(key []byte, streamID []byte, k int, seq uint64, flags byte) hash.Hash {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(streamID)
+	mac.Write([]byte{byte(k)})
+	mac.Write(binary.BigEndian.AppendUint64(nil, seq))
+	mac.Write([]byte{flags})
+	return mac
+}
+
+// Splitter: splits a stream chunk by chunk into one share stream per writer, any k of which can reconstruct it.
+// Memory use is bounded by a chunk per writer regardless of the input size.
+type Splitter struct {
+	r        io.Reader
+	writers  []io.Writer
+	k        int
+	xs       []byte
+	streamID []byte
+	macKey   []byte
+	keys     []Share
+}
+
+// NewSplitter: prepares the split of r into share streams written to writers, any k of which can reconstruct it.
+// Returns the splitter and an error if the parameters are invalid or the random generation fails.
+func NewSplitter(r io.Reader, writers []io.Writer, k int) (*Splitter, error) {
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	macKey, err := random(streamKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := Split(macKey, len(writers), k)
+	if err != nil {
+		return nil, err
+	}
+	streamID, err := random(streamIDLen)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]byte, len(keys))
+	for i, key := range keys {
+		xs[i] = key.X
+	}
+
+	return &Splitter{r: r, writers: writers, k: k, xs: xs, streamID: streamID, macKey: macKey, keys: keys}, nil
+}
+
+// Split: reads r to the end, writing the header and one record per chunk to every share stream.
+// Returns the number of input bytes split and an error if reading, writing or the random generation fails.
+func (s *Splitter) Split() (int64, error) {
+	for i, w := range s.writers {
+		header := make([]byte, 0, streamHeaderLen)
+		header = append(header, streamMagic...)
+		header = append(header, StreamVersion, byte(s.k), s.xs[i])
+		header = binary.BigEndian.AppendUint32(header, uint32(StreamChunkSize))
+		header = append(header, s.streamID...)
+		header = append(header, s.keys[i].Y...)
+		if _, err := w.Write(header); err != nil {
+			return 0, err
+		}
+	}
+
+	payloadLen := StreamChunkSize + streamTagLen
+	payload := make([]byte, payloadLen)
+	coeffs := make([]byte, s.k)
+	randomCoeffs := make([]byte, (s.k-1)*payloadLen)
+	records := make([][]byte, len(s.writers))
+	for i := range records {
+		records[i] = make([]byte, streamRecordLen+payloadLen)
+	}
+
+	var total int64
+	for seq := uint64(0); ; seq++ {
+		n, err := io.ReadFull(s.r, payload[:StreamChunkSize])
+		total += int64(n)
+		var flags byte
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			flags = chunkFinal
+		default:
+			return total, err
+		}
+
+		mac := chunkMAC(s.macKey, s.streamID, s.k, seq, flags)
+		mac.Write(payload[:n])
+		chunk := mac.Sum(payload[:n])
+
+		if err := entropy.Read(randomCoeffs[:(s.k-1)*len(chunk)]); err != nil {
+			return total, err
+		}
+		for i, x := range s.xs {
+			record := records[i][:streamRecordLen+len(chunk)]
+			binary.BigEndian.PutUint64(record, seq)
+			record[8] = flags
+			binary.BigEndian.PutUint32(record[9:], uint32(len(chunk)))
+			for b := range chunk {
+				coeffs[0] = chunk[b]
+				copy(coeffs[1:], randomCoeffs[b*(s.k-1):(b+1)*(s.k-1)])
+				record[streamRecordLen+b] = evaluate(coeffs, x)
+			}
+			if _, err := s.writers[i].Write(record); err != nil {
+				return total, err
+			}
+		}
+
+		if flags&chunkFinal != 0 {
+			return total, nil
+		}
+	}
+}
+
+// Combiner: reconstructs a stream from share streams, verifying every chunk before releasing it.
+type Combiner struct {
+	readers  []io.Reader
+	k        int
+	streamID []byte
+	macKey   []byte
+	coeffs   []byte // Lagrange coefficients of the readers at 0
+	records  [][]byte
+	seq      uint64
+	chunk    []byte // verified bytes of the current chunk not yet returned
+	done     bool
+	err      error
+}
+
+// NewCombiner: prepares the reconstruction of a stream from its share streams.
+// Headers are read lazily, so malformed or mismatched streams are reported by the first Read as a *StreamError.
+// Only the first k readers are consumed, k being the threshold recorded in the streams.
+// Returns the reconstructed stream.
+func NewCombiner(readers []io.Reader) io.Reader {
+	return &Combiner{readers: readers}
+}
+
+// readHeaders: reads and checks the headers of the first k share streams and recovers the MAC key.
+// Returns a *StreamError if a header is missing, malformed or from another split.
+func (c *Combiner) readHeaders() error {
+	if len(c.readers) == 0 {
+		return &StreamError{Reader: -1, Err: errors.New("at least 1 share stream is required")}
+	}
+
+	var chunkSize uint32
+	var keys []Share
+	for i := 0; i == 0 || i < c.k; i++ {
+		if i >= len(c.readers) {
+			return &StreamError{Reader: -1, Err: errors.New("not enough share streams: have " + strconv.Itoa(len(c.readers)) + ", need " + strconv.Itoa(c.k))}
+		}
+
+		header := make([]byte, streamHeaderLen)
+		if _, err := io.ReadFull(c.readers[i], header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = ErrStreamTruncated
+			}
+			return &StreamError{Reader: i, Err: err}
+		}
+		if !bytes.Equal(header[:len(streamMagic)], []byte(streamMagic)) {
+			return &StreamError{Reader: i, Err: errors.New("not a share stream: bad magic")}
+		}
+		pos := len(streamMagic)
+		if header[pos] != StreamVersion {
+			return &StreamError{Reader: i, Err: errors.New("unsupported share stream version: " + strconv.Itoa(int(header[pos])))}
+		}
+		k, x := int(header[pos+1]), header[pos+2]
+		size := binary.BigEndian.Uint32(header[pos+3:])
+		pos += 7
+		streamID := header[pos : pos+streamIDLen]
+		key := Share{X: x, Y: header[pos+streamIDLen:]}
+
+		if i == 0 {
+			if k < 2 {
+				return &StreamError{Reader: i, Err: errors.New("threshold must be at least 2")}
+			}
+			if size == 0 || size > uint32(MaxStreamChunkSize) {
+				return &StreamError{Reader: i, Err: errors.New("invalid chunk size: " + strconv.FormatUint(uint64(size), 10))}
+			}
+			c.k, c.streamID, chunkSize = k, streamID, size
+		} else if k != c.k || size != chunkSize || !bytes.Equal(streamID, c.streamID) {
+			return &StreamError{Reader: i, Err: ErrStreamMismatch}
+		}
+		keys = append(keys, key)
+	}
+
+	if err := validateShares(keys); err != nil {
+		return &StreamError{Reader: -1, Err: err}
+	}
+	macKey, err := Combine(keys)
+	if err != nil {
+		return &StreamError{Reader: -1, Err: err}
+	}
+
+	xs := make([]byte, c.k)
+	for i, key := range keys {
+		xs[i] = key.X
+	}
+	c.macKey = macKey
+	c.coeffs = lagrangeCoefficients(xs, 0)
+	c.records = make([][]byte, c.k)
+	for i := range c.records {
+		c.records[i] = make([]byte, streamRecordLen+int(chunkSize)+streamTagLen)
+	}
+	return nil
+}
+
+// nextChunk: reads the next record from every share stream, reconstructs the chunk and verifies its tag.
+// Returns a *StreamError if a stream is truncated, out of order or inconsistent, or the chunk fails authentication.
+func (c *Combiner) nextChunk() error {
+	var flags byte
+	var length int
+	payloads := make([][]byte, c.k)
+	for i := range c.k {
+		record := c.records[i]
+		if _, err := io.ReadFull(c.readers[i], record[:streamRecordLen]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = ErrStreamTruncated
+			}
+			return &StreamError{Reader: i, Chunk: c.seq, Err: err}
+		}
+
+		if seq := binary.BigEndian.Uint64(record); seq != c.seq {
+			return &StreamError{Reader: i, Chunk: c.seq, Err: errors.Join(ErrChunkOutOfOrder, errors.New("got chunk "+strconv.FormatUint(seq, 10)))}
+		}
+		recordFlags := record[8]
+		recordLen := int(binary.BigEndian.Uint32(record[9:]))
+		if recordFlags&^chunkFinal != 0 || recordLen < streamTagLen || recordLen > len(record)-streamRecordLen {
+			return &StreamError{Reader: i, Chunk: c.seq, Err: errors.New("malformed chunk record")}
+		}
+		if i == 0 {
+			flags, length = recordFlags, recordLen
+		} else if recordFlags != flags || recordLen != length {
+			return &StreamError{Reader: i, Chunk: c.seq, Err: ErrStreamMismatch}
+		}
+
+		payloads[i] = record[streamRecordLen : streamRecordLen+recordLen]
+		if _, err := io.ReadFull(c.readers[i], payloads[i]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = ErrStreamTruncated
+			}
+			return &StreamError{Reader: i, Chunk: c.seq, Err: err}
+		}
+	}
+
+	// the first payload buffer is no longer needed once its byte has been read, so the chunk is rebuilt in place
+	chunk := payloads[0]
+	for b := range chunk {
+		var v byte
+		for i, coeff := range c.coeffs {
+			v = gfAdd(v, gfMul(payloads[i][b], coeff))
+		}
+		chunk[b] = v
+	}
+
+	data, tag := chunk[:length-streamTagLen], chunk[length-streamTagLen:]
+	mac := chunkMAC(c.macKey, c.streamID, c.k, c.seq, flags)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return &StreamError{Reader: -1, Chunk: c.seq, Err: ErrChunkAuthentication}
+	}
+
+	c.chunk = data
+	c.done = flags&chunkFinal != 0
+	c.seq++
+	return nil
+}
+
+// Read: reads reconstructed bytes, combining and verifying further chunks as needed.
+// Returns the number of bytes read and io.EOF after the final chunk, or a *StreamError if the share streams are bad.
+func (c *Combiner) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.macKey == nil {
+		if c.err = c.readHeaders(); c.err != nil {
+			return 0, c.err
+		}
+	}
+
+	for len(c.chunk) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		if c.err = c.nextChunk(); c.err != nil {
+			return 0, c.err
+		}
+	}
+
+	n := copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+	return n, nil
+}
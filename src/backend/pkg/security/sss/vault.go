@@ -0,0 +1,42 @@
+package sss
+
+import "errors"
+
+// HashiCorp Vault's shamir package shares over the same field as Split (GF(2^8) with the AES polynomial,
+// its tables merely use 0xe5 as the generator), so its shares only differ in layout:
+//
+//	y_1 | y_2 | ... | y_m | x
+//
+// The X value is a single trailing byte, drawn by Vault from a random permutation of 1..255.
+// Unseal and recovery keys are these bytes, printed in base64 (or hex) by the Vault CLI.
+
+// ImportVaultShare: converts a share produced by Vault's shamir package into a Share.
+// Returns the share and an error if the share is too short or its X value is 0.
+func ImportVaultShare(data []byte) (Share, error) {
+	if len(data) < 2 {
+		return Share{}, errors.New("vault share must hold at least one value byte and the X tag")
+	}
+
+	x := data[len(data)-1]
+	if x == 0 {
+		return Share{}, errors.New("share X value cannot be 0")
+	}
+
+	return Share{X: x, Y: append([]byte{}, data[:len(data)-1]...)}, nil
+}
+
+// ExportVaultShare: converts a Share into the layout expected by Vault's shamir.Combine.
+// Vault has no notion of epochs, so refreshed shares export the same way as fresh ones.
+// Returns the share bytes and an error if the share has no value or its X value is 0.
+func ExportVaultShare(share Share) ([]byte, error) {
+	if share.X == 0 {
+		return nil, errors.New("share X value cannot be 0")
+	}
+	if len(share.Y) == 0 {
+		return nil, errors.New("share Y value cannot be empty")
+	}
+
+	out := make([]byte, 0, len(share.Y)+1)
+	out = append(out, share.Y...)
+	return append(out, share.X), nil
+}
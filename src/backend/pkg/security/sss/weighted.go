@@ -0,0 +1,91 @@
+package sss
+
+import (
+	"errors"
+	"strconv"
+)
+
+// AccessError: error returned when the supplied shares do not satisfy the access structure they were split for.
+type AccessError struct {
+	Reason string // which requirement of the access structure is not met
+}
+
+// Error: returns the error message.
+func (e *AccessError) Error() string {
+	return "shares do not satisfy the access structure: " + e.Reason
+}
+
+// WeightedShare: struct to hold the shares of one holder in a weighted split.
+// A holder of weight w receives w Shamir shares, so it counts as w holders towards the threshold.
+type WeightedShare struct {
+	Holder    int     `json:"holder" bson:"holder"`       // position of the holder in the weight list
+	Threshold int     `json:"threshold" bson:"threshold"` // total weight needed to reconstruct
+	Shares    []Share `json:"shares" bson:"shares"`       // one share per unit of weight
+}
+
+// SplitWeighted: splits a secret between holders of different weights, so that any group of total weight k can reconstruct it.
+// Returns one weighted share per holder and an error if the weights or threshold are invalid.
+func SplitWeighted(secret []byte, weights []int, k int) ([]WeightedShare, error) {
+	if len(weights) == 0 {
+		return nil, errors.New("at least 1 holder is required")
+	}
+
+	total := 0
+	for i, w := range weights {
+		if w < 1 {
+			return nil, errors.New("holder " + strconv.Itoa(i) + " must have a positive weight")
+		}
+		total += w
+	}
+	if total > MaxShares {
+		return nil, errors.New("total weight cannot exceed 255")
+	}
+
+	shares, err := Split(secret, total, k)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make([]WeightedShare, len(weights))
+	for i, w := range weights {
+		holders[i] = WeightedShare{
+			Holder:    i,
+			Threshold: k,
+			Shares:    shares[:w:w], // capped so that appending to one holder cannot overwrite the next
+		}
+		shares = shares[w:]
+	}
+
+	return holders, nil
+}
+
+// CombineWeighted: reconstructs a secret from the shares of a group of weighted holders.
+// Returns the secret, an *AccessError if the group does not reach the threshold, or another error if the shares are malformed.
+func CombineWeighted(holders []WeightedShare) ([]byte, error) {
+	if len(holders) == 0 {
+		return nil, &AccessError{Reason: "no holders supplied"}
+	}
+
+	k := holders[0].Threshold
+	seen := make(map[int]bool, len(holders))
+	var shares []Share
+	for _, holder := range holders {
+		if holder.Threshold != k {
+			return nil, errors.New("holders must all come from the same split")
+		}
+		if seen[holder.Holder] {
+			return nil, errors.New("duplicate holder: " + strconv.Itoa(holder.Holder))
+		}
+		seen[holder.Holder] = true
+		shares = append(shares, holder.Shares...)
+	}
+
+	if len(shares) < k {
+		return nil, &AccessError{Reason: "holders have total weight " + strconv.Itoa(len(shares)) + ", need " + strconv.Itoa(k)}
+	}
+	if err := validateShares(shares); err != nil {
+		return nil, err
+	}
+
+	return Combine(shares[:k])
+}
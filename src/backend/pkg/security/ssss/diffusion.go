@@ -0,0 +1,95 @@
+package ssss
+
+// MinDiffusionDegree: smallest security level at which ssss applies its diffusion layer.
+const MinDiffusionDegree int = 64
+
+// xteaDelta: the XTEA key schedule constant.
+const xteaDelta uint32 = 0x9e3779b9
+
+// diffusionRounds: number of block encryptions per byte of the secret.
+const diffusionRounds int = 40
+
+// encipherBlock: encrypts one 64-bit block with 32 cycles of XTEA under the all-zero key.
+func encipherBlock(v *[2]uint32) {
+	var sum uint32
+	for range 32 {
+		v[0] += ((v[1]<<4 ^ v[1]>>5) + v[1]) ^ sum
+		sum += xteaDelta
+		v[1] += ((v[0]<<4 ^ v[0]>>5) + v[0]) ^ sum
+	}
+}
+
+// decipherBlock: inverts encipherBlock.
+func decipherBlock(v *[2]uint32) {
+	sum := uint32(0xc6ef3720) // xteaDelta * 32 mod 2^32
+	for range 32 {
+		v[1] -= ((v[0]<<4 ^ v[0]>>5) + v[0]) ^ sum
+		sum -= xteaDelta
+		v[0] -= ((v[1]<<4 ^ v[1]>>5) + v[1]) ^ sum
+	}
+}
+
+// processSlice: runs a block function over the 8 bytes starting at idx, wrapping around the end of data.
+func processSlice(data []byte, idx int, block func(*[2]uint32)) {
+	n := len(data)
+
+	var v [2]uint32
+	for i := range v {
+		for j := range 4 {
+			v[i] = v[i]<<8 | uint32(data[(idx+4*i+j)%n])
+		}
+	}
+
+	block(&v)
+
+	for i := range v {
+		for j := range 4 {
+			data[(idx+4*i+j)%n] = byte(v[i] >> (24 - 8*j))
+		}
+	}
+}
+
+// diffuse: applies (or, when decode is set, removes) the ssss diffusion layer to a big-endian field element.
+// The element is laid out as GMP exports it, in 16-bit big-endian words with the least significant word first,
+// and overlapping XTEA blocks are run across it so that every bit of the secret affects every bit of the result.
+// Returns the transformed element, again big-endian.
+func diffuse(x []byte, decode bool) []byte {
+	n := len(x)
+	words := (n + 1) / 2
+
+	// word w holds the bytes x[n-2-2w] (high, absent for the top word of an odd length) and x[n-1-2w] (low)
+	v := make([]byte, 2*words)
+	for w := range words {
+		if hi := n - 2 - 2*w; hi >= 0 {
+			v[2*w] = x[hi]
+		}
+		v[2*w+1] = x[n-1-2*w]
+	}
+	if n%2 == 1 {
+		v[n-1] = v[n]
+	}
+
+	if decode {
+		for i := diffusionRounds*n - 2; i >= 0; i -= 2 {
+			processSlice(v[:n], i, decipherBlock)
+		}
+	} else {
+		for i := 0; i < diffusionRounds*n; i += 2 {
+			processSlice(v[:n], i, encipherBlock)
+		}
+	}
+
+	if n%2 == 1 {
+		v[n] = v[n-1]
+		v[n-1] = 0
+	}
+
+	out := make([]byte, n)
+	for w := range words {
+		if hi := n - 2 - 2*w; hi >= 0 {
+			out[hi] = v[2*w]
+		}
+		out[n-1-2*w] = v[2*w+1]
+	}
+	return out
+}
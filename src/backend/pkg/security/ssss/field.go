@@ -0,0 +1,162 @@
+package ssss
+
+import (
+	"errors"
+	"strconv"
+)
+
+// MaxDegree: largest security level (field degree in bits) supported by ssss.
+const MaxDegree int = 1024
+
+// irreducibleCoeffs: middle exponents (a, b, c) of the pentanomials x^d + x^a + x^b + x^c + 1
+// that ssss uses as reduction polynomials, one triple per degree d = 8, 16, ..., 1024.
+var irreducibleCoeffs = [...]uint8{
+	4, 3, 1, 5, 3, 1, 4, 3, 1, 7, 3, 2, 5, 4, 3, 5, 3, 2, 7, 4, 2, 4, 3, 1, 10, 9, 3, 9, 4, 2, 7, 6, 2, 10, 9,
+	6, 4, 3, 1, 5, 4, 3, 4, 3, 1, 7, 2, 1, 5, 3, 2, 7, 4, 2, 6, 3, 2, 5, 3, 2, 15, 3, 2, 11, 3, 2, 9, 8, 7, 7,
+	2, 1, 5, 3, 2, 9, 3, 1, 7, 3, 1, 9, 8, 3, 9, 4, 2, 8, 5, 3, 15, 14, 10, 10, 5, 2, 9, 6, 2, 9, 3, 2, 9, 5,
+	2, 11, 10, 1, 7, 3, 2, 11, 2, 1, 9, 7, 4, 4, 3, 1, 8, 3, 1, 7, 4, 1, 7, 2, 1, 13, 11, 6, 5, 3, 2, 7, 3, 2,
+	8, 7, 5, 12, 3, 2, 13, 10, 6, 5, 3, 2, 5, 3, 2, 9, 5, 2, 9, 7, 2, 13, 4, 3, 4, 3, 1, 11, 6, 4, 18, 9, 6,
+	19, 18, 13, 11, 3, 2, 15, 9, 6, 4, 3, 1, 16, 5, 2, 15, 14, 6, 8, 5, 2, 15, 11, 2, 11, 6, 2, 7, 5, 3, 8,
+	3, 1, 19, 16, 9, 11, 9, 6, 15, 7, 6, 13, 4, 3, 14, 13, 3, 13, 6, 3, 9, 5, 2, 19, 13, 6, 19, 10, 3, 11,
+	6, 5, 9, 2, 1, 14, 3, 2, 13, 3, 1, 7, 5, 4, 11, 9, 8, 11, 6, 5, 23, 16, 9, 19, 14, 6, 23, 10, 2, 8, 3,
+	2, 5, 4, 3, 9, 6, 4, 4, 3, 2, 13, 8, 6, 13, 11, 1, 13, 10, 3, 11, 6, 5, 19, 17, 4, 15, 14, 7, 13, 9, 6,
+	9, 7, 3, 9, 7, 1, 14, 3, 2, 11, 8, 2, 11, 6, 4, 13, 5, 2, 11, 5, 1, 11, 4, 1, 19, 10, 3, 21, 10, 6, 13,
+	3, 1, 15, 7, 5, 19, 18, 10, 7, 5, 3, 12, 7, 2, 7, 5, 1, 14, 9, 6, 10, 3, 2, 15, 13, 12, 12, 11, 9, 16,
+	9, 7, 12, 9, 3, 9, 5, 2, 17, 10, 6, 24, 9, 3, 17, 15, 13, 5, 4, 3, 19, 17, 8, 15, 6, 3, 19, 6, 1,
+}
+
+// element: a field element as little-endian 64-bit words, bits at and above the degree always clear.
+// Elements are fixed-width and the arithmetic below masks instead of branching, so timing does not depend on values.
+type element []uint64
+
+// field: the binary field GF(2^degree) used by ssss for a given security level.
+type field struct {
+	degree  int
+	words   int     // number of 64-bit words of an element
+	topMask uint64  // valid bits of the most significant word
+	poly    element // reduction polynomial without its x^degree term
+}
+
+// newField: builds the field for a security level.
+// Returns the field and an error if the degree is not a multiple of 8 between 8 and MaxDegree.
+func newField(degree int) (*field, error) {
+	if degree < 8 || degree > MaxDegree || degree%8 != 0 {
+		return nil, errors.New("security level must be a multiple of 8 between 8 and " + strconv.Itoa(MaxDegree) + ", got " + strconv.Itoa(degree))
+	}
+
+	f := &field{degree: degree, words: (degree + 63) / 64, topMask: ^uint64(0)}
+	if r := degree % 64; r != 0 {
+		f.topMask = 1<<r - 1
+	}
+
+	i := 3 * (degree/8 - 1)
+	f.poly = f.zero()
+	for _, bit := range []int{int(irreducibleCoeffs[i]), int(irreducibleCoeffs[i+1]), int(irreducibleCoeffs[i+2]), 0} {
+		f.poly[bit/64] |= 1 << (bit % 64)
+	}
+
+	return f, nil
+}
+
+// zero: allocates the zero element.
+// Returns the element.
+func (f *field) zero() element {
+	return make(element, f.words)
+}
+
+// fromBytes: reads a big-endian value of at most degree/8 bytes as a field element.
+// Returns the element.
+func (f *field) fromBytes(b []byte) element {
+	e := f.zero()
+	for i, v := range b {
+		bit := 8 * (len(b) - 1 - i)
+		e[bit/64] |= uint64(v) << (bit % 64)
+	}
+	return e
+}
+
+// bytes: writes a field element as degree/8 big-endian bytes.
+// Returns the bytes.
+func (f *field) bytes(e element) []byte {
+	n := f.degree / 8
+	out := make([]byte, n)
+	for i := range out {
+		bit := 8 * (n - 1 - i)
+		out[i] = byte(e[bit/64] >> (bit % 64))
+	}
+	return out
+}
+
+// fromIndex: converts a share index into a field element.
+// Returns the element.
+func (f *field) fromIndex(index int) element {
+	e := f.zero()
+	e[0] = uint64(index)
+	return e
+}
+
+// equal: compares two field elements, reading every word whatever the first difference.
+// Returns true if they are equal.
+func (f *field) equal(a, b element) bool {
+	var diff uint64
+	for i := range a {
+		diff |= a[i] ^ b[i]
+	}
+	return diff == 0
+}
+
+// isZero: reports whether a public field element is zero.
+// Returns true if every word is zero.
+func (f *field) isZero(a element) bool {
+	for _, w := range a {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// add: adds two field elements.
+// Returns the sum (which is also the difference).
+func (f *field) add(a, b element) element {
+	r := f.zero()
+	for i := range r {
+		r[i] = a[i] ^ b[i]
+	}
+	return r
+}
+
+// mul: multiplies two field elements with a shift-and-add loop over every bit of b, most significant first.
+// Each step doubles the accumulator, reduces it and conditionally adds a, all through masks.
+// Returns the product.
+func (f *field) mul(a, b element) element {
+	r := f.zero()
+	top := uint(f.degree-1) % 64
+	for i := f.degree - 1; i >= 0; i-- {
+		overflow := -(r[f.words-1] >> top & 1)
+		for w := f.words - 1; w > 0; w-- {
+			r[w] = r[w]<<1 | r[w-1]>>63
+		}
+		r[0] <<= 1
+		r[f.words-1] &= f.topMask
+
+		add := -(b[i/64] >> (i % 64) & 1)
+		for w := range r {
+			r[w] ^= f.poly[w]&overflow ^ a[w]&add
+		}
+	}
+	return r
+}
+
+// inv: inverts a field element as a^(2^degree - 2), with a fixed sequence of squarings and multiplications.
+// Returns the inverse, or zero for the zero element.
+func (f *field) inv(a element) element {
+	square := a
+	result := f.zero()
+	result[0] = 1
+	for range f.degree - 1 {
+		square = f.mul(square, square)
+		result = f.mul(result, square)
+	}
+	return result
+}
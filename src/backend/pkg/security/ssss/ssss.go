@@ -0,0 +1,282 @@
+// Package ssss implements the secret sharing scheme of B. Poettering's ssss-split and ssss-combine tools,
+// so that shares printed by those tools can be combined here and the reverse.
+//
+// ssss shares a secret over GF(2^d), where the security level d is a multiple of 8 (by default eight times
+// the secret length), with a monic polynomial of degree t whose constant term is the secret.
+// Secrets of 64 bits or more first go through an XTEA-based diffusion layer.
+// Shares are printed as "[token-]index-hex", the index zero-padded to the width of the largest index.
+package ssss
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// Params: options of ssss-split and ssss-combine that change the shares or how they are read.
+type Params struct {
+	Token         string // optional prefix of every share (the -w flag)
+	SecurityLevel int    // field degree in bits, 0 to derive it from the secret length (the -s flag)
+	NoDiffusion   bool   // skip the diffusion layer (the -D flag)
+}
+
+// Share: a single ssss share.
+type Share struct {
+	Token  string
+	Index  int
+	Degree int      // security level of the field the value lives in
+	Value  *big.Int // the polynomial evaluated at Index
+}
+
+// Format: prints a share the way ssss-split does, with the index padded to width digits.
+// Returns the share string.
+func (s Share) Format(width int) string {
+	index := strconv.Itoa(s.Index)
+	if len(index) < width {
+		index = strings.Repeat("0", width-len(index)) + index
+	}
+
+	value := s.Value.Text(16)
+	if len(value) < s.Degree/4 {
+		value = strings.Repeat("0", s.Degree/4-len(value)) + value
+	}
+
+	out := index + "-" + value
+	if s.Token != "" {
+		out = s.Token + "-" + out
+	}
+	return out
+}
+
+// String: prints a share without index padding.
+// Returns the share string.
+func (s Share) String() string {
+	return s.Format(0)
+}
+
+// ParseShare: parses a share printed by ssss-split.
+// The security level is taken from the number of hex digits, as ssss-combine does.
+// Returns the share and an error if the string is malformed.
+func ParseShare(s string) (Share, error) {
+	s = strings.TrimSpace(s)
+
+	sep := strings.LastIndexByte(s, '-')
+	if sep < 0 {
+		return Share{}, errors.New("share must have the form [token-]index-value")
+	}
+	head, hexValue := s[:sep], s[sep+1:]
+
+	var token string
+	if i := strings.LastIndexByte(head, '-'); i >= 0 {
+		token, head = head[:i], head[i+1:]
+	}
+
+	index, err := strconv.Atoi(head)
+	if err != nil || index < 1 || head[0] == '+' {
+		return Share{}, errors.New("invalid share index: " + head)
+	}
+
+	degree := 4 * len(hexValue)
+	if _, err := newField(degree); err != nil {
+		return Share{}, errors.New("invalid share value length: " + strconv.Itoa(len(hexValue)) + " hex digits")
+	}
+	if big.NewInt(int64(index)).BitLen() > degree {
+		return Share{}, errors.New("share index does not fit in the field: " + head)
+	}
+	value, ok := new(big.Int).SetString(hexValue, 16)
+	if !ok || value.Sign() < 0 || hexValue[0] == '+' || hexValue[0] == '-' {
+		return Share{}, errors.New("invalid share value: " + hexValue)
+	}
+
+	return Share{Token: token, Index: index, Degree: degree, Value: value}, nil
+}
+
+// Split: splits a secret into n shares, any k of which can reconstruct it, as ssss-split does.
+// Returns the shares formatted as ssss-split prints them and an error if the parameters are invalid.
+func Split(secret []byte, n, k int, params Params) ([]string, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return nil, errors.New("number of shares cannot be less than the threshold")
+	}
+	if strings.ContainsAny(params.Token, "- \t\r\n") {
+		return nil, errors.New("token cannot contain dashes or whitespace")
+	}
+
+	degree := params.SecurityLevel
+	if degree == 0 {
+		degree = 8 * len(secret)
+	}
+	f, err := newField(degree)
+	if err != nil {
+		return nil, err
+	}
+	if 8*len(secret) > degree {
+		return nil, errors.New("secret is longer than the security level allows: " + strconv.Itoa(degree/8) + " bytes")
+	}
+	if big.NewInt(int64(n)).BitLen() > degree {
+		return nil, errors.New("number of shares does not fit in the field")
+	}
+
+	constant := make([]byte, degree/8)
+	copy(constant[len(constant)-len(secret):], secret)
+	if !params.NoDiffusion && degree >= MinDiffusionDegree {
+		constant = diffuse(constant, false)
+	}
+
+	coeffs := make([]element, k)
+	coeffs[0] = f.fromBytes(constant)
+	random := make([]byte, degree/8)
+	for i := 1; i < k; i++ {
+		if err := entropy.Read(random); err != nil {
+			return nil, err
+		}
+		coeffs[i] = f.fromBytes(random)
+	}
+
+	width := len(strconv.Itoa(n))
+	shares := make([]string, n)
+	for i := range shares {
+		y := f.evaluate(coeffs, f.fromIndex(i+1))
+		share := Share{Token: params.Token, Index: i + 1, Degree: degree, Value: new(big.Int).SetBytes(f.bytes(y))}
+		shares[i] = share.Format(width)
+	}
+
+	return shares, nil
+}
+
+// evaluate: evaluates the monic polynomial x^k + coeffs[k-1] x^(k-1) + ... + coeffs[0] at x with Horner's rule.
+// Returns the value of the polynomial.
+func (f *field) evaluate(coeffs []element, x element) element {
+	y := x
+	for i := len(coeffs) - 1; i > 0; i-- {
+		y = f.mul(f.add(y, coeffs[i]), x)
+	}
+	return f.add(y, coeffs[0])
+}
+
+// Combine: reconstructs a secret from ssss shares, as ssss-combine does.
+// The first k shares determine the polynomial and any further shares are checked against it.
+// Returns the secret, padded to the security level, and an error if the shares are malformed, too few or inconsistent.
+func Combine(shares []string, k int, params Params) ([]byte, error) {
+	if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if len(shares) < k {
+		return nil, errors.New("at least " + strconv.Itoa(k) + " shares are required")
+	}
+
+	parsed := make([]Share, len(shares))
+	seen := make(map[int]bool, len(shares))
+	for i, s := range shares {
+		share, err := ParseShare(s)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && share.Degree != parsed[0].Degree {
+			return nil, errors.New("shares must all have the same security level")
+		}
+		if share.Token != params.Token && params.Token != "" {
+			return nil, errors.New("share token does not match: " + share.Token)
+		}
+		if seen[share.Index] {
+			return nil, errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+		parsed[i] = share
+	}
+
+	degree := parsed[0].Degree
+	if params.SecurityLevel != 0 && params.SecurityLevel != degree {
+		return nil, errors.New("shares do not match the security level: " + strconv.Itoa(degree))
+	}
+	f, err := newField(degree)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]element, len(parsed))
+	for i, share := range parsed {
+		values[i] = f.fromBytes(share.Value.FillBytes(make([]byte, degree/8)))
+	}
+
+	coeffs, err := f.solve(parsed[:k], values[:k])
+	if err != nil {
+		return nil, err
+	}
+	for i, share := range parsed[k:] {
+		if !f.equal(f.evaluate(coeffs, f.fromIndex(share.Index)), values[k+i]) {
+			return nil, errors.New("inconsistent shares: share " + strconv.Itoa(share.Index) + " does not match the others")
+		}
+	}
+
+	secret := f.bytes(coeffs[0])
+	if !params.NoDiffusion && degree >= MinDiffusionDegree {
+		secret = diffuse(secret, true)
+	}
+
+	return secret, nil
+}
+
+// solve: recovers the non-leading coefficients of the monic polynomial through k shares with Gauss-Jordan elimination.
+// Each share (x, y) gives the equation c_0 + c_1 x + ... + c_{k-1} x^(k-1) = y + x^k. The matrix only holds powers
+// of the public indices, so pivoting may branch on it; the secret right-hand side goes through masked arithmetic.
+// Returns the coefficients and an error if the system is singular.
+func (f *field) solve(shares []Share, values []element) ([]element, error) {
+	k := len(shares)
+
+	rows := make([][]element, k)
+	for i, share := range shares {
+		x := f.fromIndex(share.Index)
+		row := make([]element, k+1)
+		power := f.fromIndex(1)
+		for j := range k {
+			row[j] = power
+			power = f.mul(power, x)
+		}
+		row[k] = f.add(values[i], power)
+		rows[i] = row
+	}
+
+	for col := range k {
+		pivot := -1
+		for r := col; r < k; r++ {
+			if !f.isZero(rows[r][col]) {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, errors.New("shares are inconsistent, perhaps a share was used twice")
+		}
+		rows[col], rows[pivot] = rows[pivot], rows[col]
+
+		inv := f.inv(rows[col][col])
+		for j := col; j <= k; j++ {
+			rows[col][j] = f.mul(rows[col][j], inv)
+		}
+
+		for r := range k {
+			if r == col || f.isZero(rows[r][col]) {
+				continue
+			}
+			factor := rows[r][col]
+			for j := col; j <= k; j++ {
+				rows[r][j] = f.add(rows[r][j], f.mul(factor, rows[col][j]))
+			}
+		}
+	}
+
+	coeffs := make([]element, k)
+	for i := range coeffs {
+		coeffs[i] = rows[i][k]
+	}
+	return coeffs, nil
+}
@@ -0,0 +1,184 @@
+package vss
+
+import (
+	"errors"
+	"strconv"
+
+	"filippo.io/edwards25519"
+)
+
+// FeldmanMode: name of the Feldman verifiable secret sharing mode.
+const FeldmanMode string = "feldman"
+
+// Share: struct to hold a share of a verifiable dealing.
+type Share struct {
+	Index    int    `json:"index" bson:"index"`                           // evaluation point, never 0
+	Value    []byte `json:"value" bson:"value"`                           // canonical scalar encoding of f(Index)
+	Blinding []byte `json:"blinding,omitempty" bson:"blinding,omitempty"` // canonical scalar encoding of b(Index), Pedersen only
+}
+
+// Commitments: struct to hold the commitments a dealer publishes for the dealing polynomial.
+// They are stored with the secret's metadata and served to custodians to verify their shares.
+type Commitments struct {
+	Mode   string   `json:"mode" bson:"mode"`     // verifiable sharing mode
+	Group  string   `json:"group" bson:"group"`   // group the points belong to
+	Points [][]byte `json:"points" bson:"points"` // encoded commitment to each coefficient, constant term first
+}
+
+// SplitFeldman: splits a secret scalar into n shares with threshold k and publishes Feldman commitments.
+// The commitments are a_j*G for each coefficient a_j; note that the first one reveals secret*G.
+// Returns the shares, the commitments and an error if the parameters are invalid or the random generation fails.
+func SplitFeldman(secret []byte, n, k int) ([]Share, *Commitments, error) {
+	if err := validateParams(n, k); err != nil {
+		return nil, nil, err
+	}
+
+	s, err := decodeScalar(secret)
+	if err != nil {
+		return nil, nil, errors.New("secret must be a canonical scalar: " + err.Error())
+	}
+
+	coeffs, err := randomPolynomial(s, k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitments := &Commitments{
+		Mode:   FeldmanMode,
+		Group:  GroupName,
+		Points: make([][]byte, k),
+	}
+	for j, coeff := range coeffs {
+		commitments.Points[j] = edwards25519.NewIdentityPoint().ScalarBaseMult(coeff).Bytes()
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{
+			Index: i + 1,
+			Value: evaluatePolynomial(coeffs, i+1).Bytes(),
+		}
+	}
+
+	return shares, commitments, nil
+}
+
+// points: decodes the commitment points.
+// Returns the points and an error if the commitments are malformed.
+func (c *Commitments) points() ([]*edwards25519.Point, error) {
+	if c == nil || len(c.Points) == 0 {
+		return nil, errors.New("commitments cannot be empty")
+	}
+	if c.Group != GroupName {
+		return nil, errors.New("unsupported commitment group: " + c.Group)
+	}
+
+	points := make([]*edwards25519.Point, len(c.Points))
+	for j, encoded := range c.Points {
+		p, err := decodePoint(encoded)
+		if err != nil {
+			return nil, errors.New("invalid commitment " + strconv.Itoa(j) + ": " + err.Error())
+		}
+		points[j] = p
+	}
+
+	return points, nil
+}
+
+// Validate: checks that the commitments are in a supported mode and group, and that every point decodes to the
+// prime-order subgroup.
+// Returns an error describing the first problem found.
+func (c *Commitments) Validate() error {
+	if _, err := c.points(); err != nil {
+		return err
+	}
+	if c.Mode != FeldmanMode && c.Mode != PedersenMode {
+		return errors.New("unsupported commitment mode: " + c.Mode)
+	}
+	return nil
+}
+
+// evaluateCommitments: computes sum_j index^j * C_j, the commitment to the polynomial value at index.
+// Returns the resulting point.
+func evaluateCommitments(points []*edwards25519.Point, index int) *edwards25519.Point {
+	powers := make([]*edwards25519.Scalar, len(points))
+	x := scalarFromInt(index)
+	powers[0] = scalarFromInt(1)
+	for j := 1; j < len(powers); j++ {
+		powers[j] = edwards25519.NewScalar().Multiply(powers[j-1], x)
+	}
+
+	return edwards25519.NewIdentityPoint().VarTimeMultiScalarMult(powers, points)
+}
+
+// verifyFeldman: checks a share against Feldman commitments.
+// Returns an error if the share does not match the commitments.
+func verifyFeldman(share Share, points []*edwards25519.Point) error {
+	value, err := decodeScalar(share.Value)
+	if err != nil {
+		return err
+	}
+
+	lhs := edwards25519.NewIdentityPoint().ScalarBaseMult(value)
+	if lhs.Equal(evaluateCommitments(points, share.Index)) != 1 {
+		return errors.New("share " + strconv.Itoa(share.Index) + " does not match the commitments")
+	}
+	return nil
+}
+
+// VerifyShare: checks that a share is consistent with the commitments published by the dealer.
+// Returns an error if the share or the commitments are malformed, or the share does not match.
+func VerifyShare(share Share, commitments *Commitments) error {
+	if share.Index < 1 || share.Index > MaxShares {
+		return errors.New("share index out of range: " + strconv.Itoa(share.Index))
+	}
+
+	points, err := commitments.points()
+	if err != nil {
+		return err
+	}
+
+	switch commitments.Mode {
+	case FeldmanMode:
+		return verifyFeldman(share, points)
+	case PedersenMode:
+		return verifyPedersen(share, points)
+	default:
+		return errors.New("unsupported commitment mode: " + commitments.Mode)
+	}
+}
+
+// Combine: reconstructs a secret scalar from its shares using Lagrange interpolation at 0.
+// Shares should be verified with VerifyShare beforehand.
+// Returns the canonical scalar encoding and an error if the shares are malformed.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+
+	indices := make([]int, len(shares))
+	values := make([]*edwards25519.Scalar, len(shares))
+	seen := make(map[int]bool, len(shares))
+	for i, share := range shares {
+		if share.Index < 1 || share.Index > MaxShares {
+			return nil, errors.New("share index out of range: " + strconv.Itoa(share.Index))
+		}
+		if seen[share.Index] {
+			return nil, errors.New("duplicate share index: " + strconv.Itoa(share.Index))
+		}
+		seen[share.Index] = true
+
+		value, err := decodeScalar(share.Value)
+		if err != nil {
+			return nil, err
+		}
+		indices[i], values[i] = share.Index, value
+	}
+
+	secret := edwards25519.NewScalar()
+	for i, coeff := range lagrangeAtZero(indices) {
+		secret.MultiplyAdd(coeff, values[i], secret)
+	}
+
+	return secret.Bytes(), nil
+}
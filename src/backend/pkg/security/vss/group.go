@@ -0,0 +1,153 @@
+package vss
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"filippo.io/edwards25519"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+)
+
+// GroupName: name of the prime-order group the commitments live in.
+const GroupName string = "edwards25519"
+
+// ScalarLen: length in bytes of an encoded scalar or point.
+const ScalarLen int = 32
+
+// MaxShares: maximum number of shares supported by a single dealing.
+const MaxShares int = 1 << 16
+
+// minusOne: the scalar l-1, used to check that points lie in the prime-order subgroup.
+var minusOne = edwards25519.NewScalar().Negate(scalarFromInt(1))
+
+// randomScalar: generates a uniformly random scalar.
+// Returns the scalar and an error if the random generation fails.
+func randomScalar() (*edwards25519.Scalar, error) {
+	randomBytes := make([]byte, 64)
+
+	err := entropy.Read(randomBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return edwards25519.NewScalar().SetUniformBytes(randomBytes)
+}
+
+// scalarFromInt: converts a non-negative integer to a scalar.
+// Returns the scalar.
+func scalarFromInt(x int) *edwards25519.Scalar {
+	var buf [ScalarLen]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(x))
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(buf[:])
+	if err != nil {
+		panic(err) // unreachable: any 64-bit value is below the group order
+	}
+	return s
+}
+
+// decodeScalar: decodes a canonical little-endian scalar encoding.
+// Returns the scalar and an error if the encoding is not canonical.
+func decodeScalar(b []byte) (*edwards25519.Scalar, error) {
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(b)
+	if err != nil {
+		return nil, errors.New("invalid scalar encoding")
+	}
+	return s, nil
+}
+
+// decodePoint: decodes a point and checks that it lies in the prime-order subgroup.
+// Returns the point and an error if the encoding is invalid or the point has a torsion component.
+func decodePoint(b []byte) (*edwards25519.Point, error) {
+	p, err := edwards25519.NewIdentityPoint().SetBytes(b)
+	if err != nil {
+		return nil, errors.New("invalid point encoding")
+	}
+
+	// l*P = (l-1)*P + P is the identity only for points of the prime-order subgroup
+	check := edwards25519.NewIdentityPoint().ScalarMult(minusOne, p)
+	check.Add(check, p)
+	if check.Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return nil, errors.New("point is not in the prime-order subgroup")
+	}
+
+	return p, nil
+}
+
+// lagrangeAtZero: computes the Lagrange coefficients at 0 for the given distinct indices.
+// Returns one coefficient per index.
+func lagrangeAtZero(indices []int) []*edwards25519.Scalar {
+	coeffs := make([]*edwards25519.Scalar, len(indices))
+
+	for i, xi := range indices {
+		num := scalarFromInt(1)
+		den := scalarFromInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			sj := scalarFromInt(xj)
+			num.Multiply(num, sj)
+			den.Multiply(den, edwards25519.NewScalar().Subtract(sj, scalarFromInt(xi)))
+		}
+		coeffs[i] = num.Multiply(num, den.Invert(den))
+	}
+
+	return coeffs
+}
+
+// evaluatePolynomial: evaluates the polynomial with the given coefficients at x using Horner's rule.
+// Returns the value of the polynomial at x.
+func evaluatePolynomial(coeffs []*edwards25519.Scalar, x int) *edwards25519.Scalar {
+	sx := scalarFromInt(x)
+	y := edwards25519.NewScalar()
+
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.MultiplyAdd(y, sx, coeffs[i])
+	}
+
+	return y
+}
+
+// randomPolynomial: generates a random polynomial of degree k-1 with the given constant term.
+// Returns the coefficients and an error if the random generation fails.
+func randomPolynomial(constant *edwards25519.Scalar, k int) ([]*edwards25519.Scalar, error) {
+	coeffs := make([]*edwards25519.Scalar, k)
+	coeffs[0] = edwards25519.NewScalar().Set(constant)
+
+	for i := 1; i < k; i++ {
+		coeff, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = coeff
+	}
+
+	return coeffs, nil
+}
+
+// validateParams: checks the number of shares and the threshold of a dealing.
+// Returns an error if the parameters are invalid.
+func validateParams(n, k int) error {
+	if k < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	if n < k {
+		return errors.New("number of shares cannot be less than the threshold")
+	}
+	if n > MaxShares {
+		return errors.New("number of shares exceeds the maximum")
+	}
+	return nil
+}
+
+// NewSecret: generates a random secret scalar suitable for verifiable sharing.
+// Returns the canonical scalar encoding and an error if the random generation fails.
+func NewSecret() ([]byte, error) {
+	s, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return s.Bytes(), nil
+}
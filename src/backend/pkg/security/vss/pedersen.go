@@ -0,0 +1,173 @@
+package vss
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"strconv"
+
+	"filippo.io/edwards25519"
+)
+
+// PedersenMode: name of the Pedersen verifiable secret sharing mode.
+const PedersenMode string = "pedersen"
+
+// pedersenDomain: domain separation string used to derive the second generator.
+const pedersenDomain string = "CRYPTO-SSS pedersen generator H"
+
+// ErrMissingBlinding: returned when a Pedersen share is verified without its blinding value.
+var ErrMissingBlinding = errors.New("share is missing its blinding value")
+
+// generatorH: second generator whose discrete logarithm with respect to the base point is unknown.
+var generatorH = hashToPoint(pedersenDomain)
+
+// hashToPoint: derives a point of the prime-order subgroup from a domain string by try-and-increment.
+// Nobody knows the discrete logarithm of the result, which is what makes Pedersen commitments hiding.
+// Returns the point.
+func hashToPoint(domain string) *edwards25519.Point {
+	var counter [4]byte
+
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		digest := sha512.Sum512(append([]byte(domain), counter[:]...))
+
+		p, err := edwards25519.NewIdentityPoint().SetBytes(digest[:32])
+		if err != nil {
+			continue
+		}
+
+		// clear the torsion component so the point lies in the prime-order subgroup
+		p.MultByCofactor(p)
+		if p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+			continue
+		}
+		return p
+	}
+}
+
+// Dealing: struct to hold a dealer's secret and blinding polynomials of a Pedersen dealing. Unlike SplitPedersen it
+// keeps the polynomials, so that shares can be handed out one at a time and Feldman commitments to the same secret
+// polynomial published later, as the dealers of a distributed key generation do.
+type Dealing struct {
+	coeffs         []*edwards25519.Scalar
+	blindingCoeffs []*edwards25519.Scalar
+}
+
+// NewPedersenDealing: draws the polynomials of a Pedersen dealing of a secret scalar with threshold k.
+// Returns the dealing and an error if the threshold is invalid, the secret is not a canonical scalar
+// or the random generation fails.
+func NewPedersenDealing(secret []byte, k int) (*Dealing, error) {
+	if k < 2 || k > MaxShares {
+		return nil, errors.New("threshold must be between 2 and " + strconv.Itoa(MaxShares))
+	}
+
+	s, err := decodeScalar(secret)
+	if err != nil {
+		return nil, errors.New("secret must be a canonical scalar: " + err.Error())
+	}
+
+	coeffs, err := randomPolynomial(s, k)
+	if err != nil {
+		return nil, err
+	}
+
+	blindingConstant, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	blindingCoeffs, err := randomPolynomial(blindingConstant, k)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dealing{coeffs: coeffs, blindingCoeffs: blindingCoeffs}, nil
+}
+
+// Share: evaluates the dealing at index, which must not be 0.
+// Returns the share with its blinding value.
+func (d *Dealing) Share(index int) Share {
+	return Share{
+		Index:    index,
+		Value:    evaluatePolynomial(d.coeffs, index).Bytes(),
+		Blinding: evaluatePolynomial(d.blindingCoeffs, index).Bytes(),
+	}
+}
+
+// PedersenCommitments: commits to the coefficients as a_j*G + b_j*H.
+// Returns the commitments.
+func (d *Dealing) PedersenCommitments() *Commitments {
+	commitments := &Commitments{
+		Mode:   PedersenMode,
+		Group:  GroupName,
+		Points: make([][]byte, len(d.coeffs)),
+	}
+	for j := range d.coeffs {
+		commitments.Points[j] = pedersenCommit(d.coeffs[j], d.blindingCoeffs[j]).Bytes()
+	}
+	return commitments
+}
+
+// FeldmanCommitments: commits to the secret coefficients alone as a_j*G, the first one revealing secret*G.
+// Returns the commitments.
+func (d *Dealing) FeldmanCommitments() *Commitments {
+	commitments := &Commitments{
+		Mode:   FeldmanMode,
+		Group:  GroupName,
+		Points: make([][]byte, len(d.coeffs)),
+	}
+	for j, coeff := range d.coeffs {
+		commitments.Points[j] = edwards25519.NewIdentityPoint().ScalarBaseMult(coeff).Bytes()
+	}
+	return commitments
+}
+
+// SplitPedersen: splits a secret scalar into n shares with threshold k and publishes Pedersen commitments.
+// The commitments are a_j*G + b_j*H for the secret polynomial a and a random blinding polynomial b,
+// which hide the secret information-theoretically. Each share carries its blinding value b(Index).
+// Returns the shares, the commitments and an error if the parameters are invalid or the random generation fails.
+func SplitPedersen(secret []byte, n, k int) ([]Share, *Commitments, error) {
+	if err := validateParams(n, k); err != nil {
+		return nil, nil, err
+	}
+
+	dealing, err := NewPedersenDealing(secret, k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = dealing.Share(i + 1)
+	}
+
+	return shares, dealing.PedersenCommitments(), nil
+}
+
+// pedersenCommit: computes the Pedersen commitment value*G + blinding*H.
+// Returns the commitment point.
+func pedersenCommit(value, blinding *edwards25519.Scalar) *edwards25519.Point {
+	commitment := edwards25519.NewIdentityPoint().ScalarBaseMult(value)
+	return commitment.Add(commitment, edwards25519.NewIdentityPoint().ScalarMult(blinding, generatorH))
+}
+
+// verifyPedersen: checks a share and its blinding value against Pedersen commitments.
+// Returns an error if the blinding value is missing or malformed, or the share does not match the commitments.
+func verifyPedersen(share Share, points []*edwards25519.Point) error {
+	if len(share.Blinding) == 0 {
+		return ErrMissingBlinding
+	}
+
+	value, err := decodeScalar(share.Value)
+	if err != nil {
+		return err
+	}
+	blinding, err := decodeScalar(share.Blinding)
+	if err != nil {
+		return errors.New("invalid blinding value: " + err.Error())
+	}
+
+	if pedersenCommit(value, blinding).Equal(evaluateCommitments(points, share.Index)) != 1 {
+		return errors.New("share " + strconv.Itoa(share.Index) + " does not match the commitments")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package strings
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	stdstrings "strings"
+)
+
+// ArmorType: block type of ASCII-armored shares.
+const ArmorType string = "SSS SHARE"
+
+// Armor: wraps binary data in a PEM-style block, e.g. "-----BEGIN SSS SHARE-----", with optional headers.
+// Returns the armored block.
+func Armor(data []byte, headers map[string]string) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: ArmorType, Headers: headers, Bytes: data}))
+}
+
+// Dearmor: extracts the binary data and headers from an armored share block.
+// Surrounding whitespace is ignored, any other text before or after the block is rejected.
+// Returns the data, the headers and an error if the input is not a single armored share.
+func Dearmor(s string) ([]byte, map[string]string, error) {
+	block, rest := pem.Decode([]byte(stdstrings.TrimSpace(s)))
+	if block == nil {
+		return nil, nil, errors.New("no armored block found")
+	}
+	if block.Type != ArmorType {
+		return nil, nil, errors.New("unexpected armored block type: " + block.Type)
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, nil, errors.New("unexpected data after the armored block")
+	}
+	return block.Bytes, block.Headers, nil
+}
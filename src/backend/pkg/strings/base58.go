@@ -0,0 +1,125 @@
+package strings
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"strconv"
+)
+
+// base58Alphabet: Bitcoin Base58 alphabet, without 0, O, I and l.
+const base58Alphabet string = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58ChecksumLen: number of double-SHA-256 bytes appended by Base58Check.
+const base58ChecksumLen int = 4
+
+var base58Index = func() [256]int {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i := range len(base58Alphabet) {
+		index[base58Alphabet[i]] = i
+	}
+	return index
+}()
+
+// Base58Encode: encodes data in Base58, keeping leading zero bytes as leading '1's.
+// Returns the encoded string.
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// repeated division of the big-endian number by 58, digits come out least significant first
+	digits := make([]byte, 0, len(data)*138/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := range zeros {
+		out[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
+
+// Base58Decode: decodes a Base58 string.
+// Returns the data and an error if the string contains a character outside the alphabet.
+func Base58Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	value := make([]byte, 0, len(s)*733/1000+1)
+	for i := zeros; i < len(s); i++ {
+		carry := base58Index[s[i]]
+		if carry < 0 {
+			return nil, errors.New("invalid Base58 character at position " + strconv.Itoa(i))
+		}
+		for j := range value {
+			carry += int(value[j]) * 58
+			value[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			value = append(value, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(value))
+	for i, b := range value {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+// base58Checksum: computes the first 4 bytes of the double SHA-256 of data.
+// Returns the checksum.
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:base58ChecksumLen]
+}
+
+// Base58CheckEncode: encodes a version byte and payload in Base58 with a 4-byte double-SHA-256 checksum.
+// Returns the encoded string.
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := make([]byte, 0, 1+len(payload)+base58ChecksumLen)
+	data = append(data, version)
+	data = append(data, payload...)
+	return Base58Encode(append(data, base58Checksum(data)...))
+}
+
+// Base58CheckDecode: decodes a Base58Check string and verifies its checksum.
+// Returns the version byte, the payload and an error if the string is malformed or the checksum does not match.
+func Base58CheckDecode(s string) (byte, []byte, error) {
+	data, err := Base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 1+base58ChecksumLen {
+		return 0, nil, errors.New("Base58Check string is too short")
+	}
+
+	body, sum := data[:len(data)-base58ChecksumLen], data[len(data)-base58ChecksumLen:]
+	if !bytes.Equal(base58Checksum(body), sum) {
+		return 0, nil, errors.New("Base58Check checksum mismatch")
+	}
+	return body[0], body[1:], nil
+}
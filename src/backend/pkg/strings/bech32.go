@@ -0,0 +1,177 @@
+package strings
+
+import (
+	"errors"
+	"strconv"
+	stdstrings "strings"
+)
+
+// ShareHRP: human-readable part used for Bech32m-encoded shares.
+const ShareHRP string = "sss"
+
+// bech32Charset: Bech32 data alphabet, one character per 5-bit group.
+const bech32Charset string = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32mConst: checksum constant distinguishing Bech32m (BIP-350) from the original Bech32.
+const bech32mConst uint32 = 0x2bc830a3
+
+// bech32MaxLen: maximum length of a Bech32m string.
+// BIP-173 stops at 90 characters for addresses, and its checksum is only guaranteed to detect any error affecting
+// up to 4 characters for strings of up to 89 characters. Shares are longer: above 89 characters fewer errors are
+// guaranteed to be detected, and other errors go unnoticed with a probability of about 2^-30.
+const bech32MaxLen int = 1023
+
+// bech32ChecksumLen: number of characters of the checksum.
+const bech32ChecksumLen int = 6
+
+// bech32Polymod: computes the BCH checksum polynomial over 5-bit values.
+// Returns the checksum state.
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range generator {
+			chk ^= generator[i] & -(top >> i & 1)
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand: expands the human-readable part for checksum computation.
+// Returns the high bits of each character, a zero separator and the low bits of each character.
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits: regroups a sequence of from-bit values into to-bit values.
+// With pad, the last group is zero-padded; without it, leftover bits must be fewer than from and all zero.
+// Returns the regrouped values and an error if a value is out of range or the padding is invalid.
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+	maxValue := uint32(1)<<to - 1
+
+	for _, v := range data {
+		if uint32(v)>>from != 0 {
+			return nil, errors.New("value out of range for bit conversion")
+		}
+		acc = acc<<from | uint32(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxValue))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxValue != 0 {
+		return nil, errors.New("invalid padding in Bech32m data")
+	}
+	return out, nil
+}
+
+// Bech32mEncode: encodes data under a human-readable part with a Bech32m checksum.
+// Returns the lowercase encoded string and an error if the human-readable part is invalid or the result is too long.
+func Bech32mEncode(hrp string, data []byte) (string, error) {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return "", errors.New("Bech32m human-readable part must have between 1 and 83 characters")
+	}
+	for i := range len(hrp) {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", errors.New("invalid character in Bech32m human-readable part")
+		}
+	}
+	hrp = stdstrings.ToLower(hrp)
+
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	if len(hrp)+1+len(values)+bech32ChecksumLen > bech32MaxLen {
+		return "", errors.New("data is too long for Bech32m")
+	}
+
+	checksumInput := append(bech32HRPExpand(hrp), values...)
+	checksumInput = append(checksumInput, make([]byte, bech32ChecksumLen)...)
+	polymod := bech32Polymod(checksumInput) ^ bech32mConst
+
+	var sb stdstrings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := range bech32ChecksumLen {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+// decodeBech32m: splits a Bech32m string into its human-readable part and 5-bit values and verifies the checksum.
+// Returns the lowercase human-readable part, the values without the checksum and an error if the string is invalid.
+func decodeBech32m(s string) (string, []byte, error) {
+	if len(s) > bech32MaxLen {
+		return "", nil, errors.New("Bech32m string is too long")
+	}
+	if stdstrings.ToLower(s) != s && stdstrings.ToUpper(s) != s {
+		return "", nil, errors.New("Bech32m string mixes upper and lower case")
+	}
+	for i := range len(s) {
+		if s[i] < 33 || s[i] > 126 {
+			return "", nil, errors.New("invalid Bech32m character at position " + strconv.Itoa(i))
+		}
+	}
+
+	s = stdstrings.ToLower(s)
+	sep := stdstrings.LastIndexByte(s, '1')
+	if sep < 1 || sep+1+bech32ChecksumLen > len(s) {
+		return "", nil, errors.New("Bech32m separator is missing or misplaced")
+	}
+
+	hrp := s[:sep]
+	values := make([]byte, len(s)-sep-1)
+	for i := range values {
+		v := stdstrings.IndexByte(bech32Charset, s[sep+1+i])
+		if v < 0 {
+			return "", nil, errors.New("invalid Bech32m character at position " + strconv.Itoa(sep+1+i))
+		}
+		values[i] = byte(v)
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != bech32mConst {
+		return "", nil, errors.New("Bech32m checksum mismatch")
+	}
+	return hrp, values[:len(values)-bech32ChecksumLen], nil
+}
+
+// Bech32mDecode: decodes a Bech32m string and verifies its checksum.
+// Returns the lowercase human-readable part, the data and an error if the string is malformed or the checksum does not match.
+func Bech32mDecode(s string) (string, []byte, error) {
+	hrp, values, err := decodeBech32m(s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := convertBits(values, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
@@ -0,0 +1,60 @@
+package strings
+
+import (
+	"encoding/hex"
+	stdstrings "strings"
+)
+
+// Encoding: text encoding of a share.
+type Encoding int
+
+const (
+	EncodingUnknown Encoding = iota
+	EncodingArmor
+	EncodingBech32m
+	EncodingBase58Check
+	EncodingHex
+)
+
+// String: returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingArmor:
+		return "armor"
+	case EncodingBech32m:
+		return "bech32m"
+	case EncodingBase58Check:
+		return "base58check"
+	case EncodingHex:
+		return "hex"
+	}
+	return "unknown"
+}
+
+// Detect: recognizes the encoding of a share string, ignoring surrounding whitespace.
+// Checksummed encodings are only reported if their checksum verifies, so a mistyped share is reported as unknown
+// rather than as another encoding that happens to share its alphabet.
+// Returns the detected encoding, EncodingUnknown if none matches.
+func Detect(s string) Encoding {
+	s = stdstrings.TrimSpace(s)
+	if s == "" {
+		return EncodingUnknown
+	}
+
+	if stdstrings.HasPrefix(s, "-----BEGIN "+ArmorType+"-----") {
+		if _, _, err := Dearmor(s); err == nil {
+			return EncodingArmor
+		}
+		return EncodingUnknown
+	}
+	if _, _, err := decodeBech32m(s); err == nil {
+		return EncodingBech32m
+	}
+	if _, _, err := Base58CheckDecode(s); err == nil {
+		return EncodingBase58Check
+	}
+	if _, err := hex.DecodeString(s); err == nil {
+		return EncodingHex
+	}
+	return EncodingUnknown
+}
@@ -0,0 +1,280 @@
+package test
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/engine"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// The constant-time tests follow dudect (Reparaz, Balasch, Verbauwhede, "Dude, is my code constant time?"):
+// an operation is timed on inputs from two classes, fixed and random, in random order, and Welch's t-test
+// checks whether the two timing distributions differ. They take a while and are sensitive to a busy machine,
+// so they only run when SSS_DUDECT is set, e.g.
+//
+//	SSS_DUDECT=1 go test ./test -run ConstantTime -v
+
+const (
+	dudectSamples   = 200_000
+	dudectRepeats   = 4    // operations per measurement, to stay well above the timer resolution
+	dudectThreshold = 10.0 // |t| above which the timings are considered to leak the input class
+)
+
+// welch: running means and variances of the timings of both classes.
+type welch struct {
+	n, mean, m2 [2]float64
+}
+
+func (w *welch) push(class int, x float64) {
+	w.n[class]++
+	delta := x - w.mean[class]
+	w.mean[class] += delta / w.n[class]
+	w.m2[class] += delta * (x - w.mean[class])
+}
+
+func (w *welch) t() float64 {
+	v0 := w.m2[0] / (w.n[0] - 1)
+	v1 := w.m2[1] / (w.n[1] - 1)
+	return (w.mean[0] - w.mean[1]) / math.Sqrt(v0/w.n[0]+v1/w.n[1])
+}
+
+// dudect: times run on inputs of both classes and returns the largest |t| over several cropping thresholds,
+// since cropping the slowest measurements removes interrupt noise but can also hide a leak in the tail.
+func dudect(t *testing.T, prepare func(class int) any, run func(input any)) float64 {
+	t.Helper()
+
+	classes := make([]byte, dudectSamples)
+	if _, err := rand.Read(classes); err != nil {
+		t.Fatalf("rand.Read() error = %v, want nil", err)
+	}
+	inputs := make([]any, dudectSamples)
+	for i := range inputs {
+		classes[i] &= 1
+		inputs[i] = prepare(int(classes[i]))
+	}
+
+	// warm up caches and the branch predictor on both classes
+	for i := range 1000 {
+		run(inputs[i])
+	}
+
+	timings := make([]float64, dudectSamples)
+	for i, input := range inputs {
+		start := time.Now()
+		for range dudectRepeats {
+			run(input)
+		}
+		timings[i] = float64(time.Since(start))
+	}
+
+	sorted := slices.Clone(timings)
+	slices.Sort(sorted)
+
+	var worst float64
+	for _, percentile := range []float64{1, 0.99, 0.95, 0.9, 0.75, 0.5} {
+		cutoff := sorted[int(percentile*float64(len(sorted)-1))]
+		var w welch
+		for i, timing := range timings {
+			if timing <= cutoff {
+				w.push(int(classes[i]), timing)
+			}
+		}
+		worst = max(worst, math.Abs(w.t()))
+	}
+	return worst
+}
+
+func requireDudect(t *testing.T) {
+	t.Helper()
+	if os.Getenv("SSS_DUDECT") == "" {
+		t.Skip("set SSS_DUDECT=1 to run the timing leakage tests")
+	}
+}
+
+// combineInputs: share sets of the same shape whose values are all zero (fixed class) or random (random class),
+// the split that made table-based multiplication leak through its zero shortcut and cache lines.
+func combineInputs(t *testing.T, size int) func(class int) any {
+	return func(class int) any {
+		shares := make([]sss.Share, 3)
+		for i := range shares {
+			shares[i] = sss.Share{X: byte(i + 1), Y: make([]byte, size)}
+			if class == 1 {
+				if _, err := rand.Read(shares[i].Y); err != nil {
+					t.Fatalf("rand.Read() error = %v, want nil", err)
+				}
+			}
+		}
+		return shares
+	}
+}
+
+func TestConstantTime_Combine(t *testing.T) {
+	requireDudect(t)
+
+	got := dudect(t, combineInputs(t, 32), func(input any) {
+		if _, err := sss.Combine(input.([]sss.Share)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("sss.Combine timing depends on the share values: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
+
+func TestConstantTime_EngineCombine(t *testing.T) {
+	requireDudect(t)
+
+	e := engine.New(1)
+	got := dudect(t, combineInputs(t, 64), func(input any) {
+		if _, err := e.Combine(input.([]sss.Share)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("engine Combine timing depends on the share values: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
+
+func TestConstantTime_CombineRobust(t *testing.T) {
+	requireDudect(t)
+
+	// five consistent shares of either an all-zero or a random secret, decoded with spare shares
+	prepare := func(class int) any {
+		secret := make([]byte, 16)
+		if class == 1 {
+			if _, err := rand.Read(secret); err != nil {
+				t.Fatalf("rand.Read() error = %v, want nil", err)
+			}
+		}
+		shares, err := sss.Split(secret, 5, 3)
+		if err != nil {
+			t.Fatalf("Split() error = %v, want nil", err)
+		}
+		return shares
+	}
+
+	got := dudect(t, prepare, func(input any) {
+		if _, err := sss.CombineRobust(input.([]sss.Share), 3); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("sss.CombineRobust timing depends on the secret: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
+
+// randomBelow: draws a uniform integer below max for a timing test.
+func randomBelow(t *testing.T, max *big.Int) *big.Int {
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		t.Fatalf("rand.Int() error = %v, want nil", err)
+	}
+	return v
+}
+
+func TestConstantTime_CombinePrime(t *testing.T) {
+	requireDudect(t)
+
+	p, err := sss.NamedPrime(sss.P256Order)
+	if err != nil {
+		t.Fatalf("NamedPrime() error = %v, want nil", err)
+	}
+
+	// three shares whose values are all zero or all random
+	prepare := func(class int) any {
+		shares := make([]sss.PrimeShare, 3)
+		for i := range shares {
+			shares[i] = sss.PrimeShare{Prime: p, X: i + 1, Y: new(big.Int)}
+			if class == 1 {
+				shares[i].Y = randomBelow(t, p)
+			}
+		}
+		return shares
+	}
+
+	got := dudect(t, prepare, func(input any) {
+		if _, err := sss.CombinePrime(input.([]sss.PrimeShare)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("sss.CombinePrime timing depends on the share values: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
+
+func TestConstantTime_CombineBlakley(t *testing.T) {
+	requireDudect(t)
+
+	p, err := sss.NamedPrime(sss.P256Order)
+	if err != nil {
+		t.Fatalf("NamedPrime() error = %v, want nil", err)
+	}
+
+	// three hyperplanes through a point with an all-zero or a random secret, the first one with its first
+	// coefficient zero so that elimination has to swap a pivot into place
+	prepare := func(class int) any {
+		secret := new(big.Int)
+		if class == 1 {
+			secret = randomBelow(t, p)
+		}
+		shares, err := sss.SplitBlakley(secret, 3, 3, p)
+		if err != nil {
+			t.Fatalf("SplitBlakley() error = %v, want nil", err)
+		}
+		shares[0].Const.Sub(shares[0].Const, new(big.Int).Mul(shares[0].Coeffs[0], secret))
+		shares[0].Const.Mod(shares[0].Const, p)
+		shares[0].Coeffs[0] = new(big.Int)
+		return shares
+	}
+
+	got := dudect(t, prepare, func(input any) {
+		if _, err := sss.CombineBlakley(input.([]sss.Hyperplane)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("sss.CombineBlakley timing depends on the secret: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
+
+func TestConstantTime_CombineCRT(t *testing.T) {
+	requireDudect(t)
+
+	moduli, err := sss.MignotteSequence(3, 3, 128)
+	if err != nil {
+		t.Fatalf("MignotteSequence() error = %v, want nil", err)
+	}
+
+	// residues modulo the same moduli that are all zero or all random
+	prepare := func(class int) any {
+		shares := make([]sss.CRTShare, len(moduli))
+		for i, m := range moduli {
+			shares[i] = sss.CRTShare{Index: i + 1, Modulus: m, Residue: new(big.Int)}
+			if class == 1 {
+				shares[i].Residue = randomBelow(t, m)
+			}
+		}
+		return shares
+	}
+
+	got := dudect(t, prepare, func(input any) {
+		if _, err := sss.CombineCRT(input.([]sss.CRTShare)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Logf("max |t| = %.2f", got)
+	if got > dudectThreshold {
+		t.Errorf("sss.CombineCRT timing depends on the residues: |t| = %.2f > %.1f", got, dudectThreshold)
+	}
+}
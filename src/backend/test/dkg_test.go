@@ -0,0 +1,384 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/gin-gonic/gin"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	api_dkg "github.com/culbec/CRYPTO-sss/src/backend/internal/api/dkg"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/dkg"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+// checkKeyShares checks that several threshold subsets of the key shares reconstruct the key behind the public key.
+func checkKeyShares(t *testing.T, result *dkg.Result) {
+	t.Helper()
+	k := result.Transcript.Threshold
+
+	subsets := [][]vss.Share{result.KeyShares[:k], result.KeyShares[len(result.KeyShares)-k:]}
+	for _, subset := range subsets {
+		key, err := vss.Combine(subset)
+		if err != nil {
+			t.Fatalf("Combine() error = %v, want nil", err)
+		}
+		scalar, err := edwards25519.NewScalar().SetCanonicalBytes(key)
+		if err != nil {
+			t.Fatalf("key is not a canonical scalar: %v", err)
+		}
+		if got := edwards25519.NewIdentityPoint().ScalarBaseMult(scalar).Bytes(); !bytes.Equal(got, result.Transcript.PublicKey) {
+			t.Errorf("key shares reconstruct a key with public key %x, want %x", got, result.Transcript.PublicKey)
+		}
+	}
+}
+
+// countMessages counts the broadcast messages of a round of a transcript.
+func countMessages(transcript *dkg.Transcript, round dkg.Round) int {
+	count := 0
+	for _, step := range transcript.Steps {
+		if step.Round != round {
+			continue
+		}
+		for _, message := range step.Messages {
+			if message.To == 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestDKG_Run(t *testing.T) {
+	tests := []struct {
+		name             string
+		n, k             int
+		behaviors        map[int]dkg.Behavior
+		wantQualified    []int
+		wantExposed      []int
+		wantComplaints   int
+		wantDisqualified map[dkg.Round][]int
+	}{
+		{
+			name:          "all participants honest",
+			n:             5,
+			k:             3,
+			wantQualified: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:           "corrupt share answered",
+			n:              5,
+			k:              3,
+			behaviors:      map[int]dkg.Behavior{2: {CorruptShares: []int{4}}},
+			wantQualified:  []int{1, 2, 3, 4, 5},
+			wantComplaints: 1,
+		},
+		{
+			name:             "corrupt share left unanswered",
+			n:                5,
+			k:                3,
+			behaviors:        map[int]dkg.Behavior{2: {CorruptShares: []int{4}, WithholdAnswers: true}},
+			wantQualified:    []int{1, 3, 4, 5},
+			wantComplaints:   1,
+			wantDisqualified: map[dkg.Round][]int{dkg.RoundAnswer: {2}},
+		},
+		{
+			name:             "too many complaints",
+			n:                5,
+			k:                3,
+			behaviors:        map[int]dkg.Behavior{5: {CorruptShares: []int{1, 2, 3}}},
+			wantQualified:    []int{1, 2, 3, 4},
+			wantComplaints:   3,
+			wantDisqualified: map[dkg.Round][]int{dkg.RoundAnswer: {5}},
+		},
+		{
+			name:           "false complaint against an honest dealer",
+			n:              4,
+			k:              2,
+			behaviors:      map[int]dkg.Behavior{3: {FalseComplaints: []int{1}}},
+			wantQualified:  []int{1, 2, 3, 4},
+			wantComplaints: 1,
+		},
+		{
+			name:          "corrupt Feldman commitments",
+			n:             5,
+			k:             3,
+			behaviors:     map[int]dkg.Behavior{3: {CorruptFeldman: true}},
+			wantQualified: []int{1, 2, 3, 4, 5},
+			wantExposed:   []int{3},
+		},
+		{
+			name: "several deviations at once",
+			n:    7,
+			k:    3,
+			behaviors: map[int]dkg.Behavior{
+				1: {CorruptShares: []int{2}, WithholdAnswers: true},
+				4: {CorruptFeldman: true},
+				6: {FalseComplaints: []int{7}},
+			},
+			wantQualified:    []int{2, 3, 4, 5, 6, 7},
+			wantExposed:      []int{4},
+			wantComplaints:   2,
+			wantDisqualified: map[dkg.Round][]int{dkg.RoundAnswer: {1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := dkg.Run(tt.n, tt.k, tt.behaviors)
+			if err != nil {
+				t.Fatalf("Run() error = %v, want nil", err)
+			}
+
+			transcript := result.Transcript
+			if !slices.Equal(transcript.Qualified, tt.wantQualified) {
+				t.Errorf("Run() qualified = %v, want %v", transcript.Qualified, tt.wantQualified)
+			}
+			if got := countMessages(transcript, dkg.RoundComplain); got != tt.wantComplaints {
+				t.Errorf("Run() complaints = %d, want %d", got, tt.wantComplaints)
+			}
+			if len(result.KeyShares) != tt.n {
+				t.Errorf("Run() returned %d key shares, want %d", len(result.KeyShares), tt.n)
+			}
+			checkKeyShares(t, result)
+
+			checkpoints, err := dkg.Replay(transcript)
+			if err != nil {
+				t.Fatalf("Replay() error = %v, want nil", err)
+			}
+			if len(checkpoints) != len(transcript.Steps) {
+				t.Fatalf("Replay() returned %d checkpoints, want %d", len(checkpoints), len(transcript.Steps))
+			}
+
+			last := checkpoints[len(checkpoints)-1]
+			if !slices.Equal(last.Exposed, tt.wantExposed) {
+				t.Errorf("Replay() exposed = %v, want %v", last.Exposed, tt.wantExposed)
+			}
+			if !bytes.Equal(last.PublicKey, transcript.PublicKey) {
+				t.Errorf("Replay() public key = %x, want %x", last.PublicKey, transcript.PublicKey)
+			}
+			for _, checkpoint := range checkpoints {
+				if !slices.Equal(checkpoint.Disqualified, tt.wantDisqualified[checkpoint.Round]) {
+					t.Errorf("Replay() disqualified in the %v round = %v, want %v",
+						checkpoint.Round, checkpoint.Disqualified, tt.wantDisqualified[checkpoint.Round])
+				}
+			}
+		})
+	}
+}
+
+func TestDKG_TranscriptHidesPrivateShares(t *testing.T) {
+	result, err := dkg.Run(4, 2, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	private := 0
+	for _, step := range result.Transcript.Steps {
+		for _, message := range step.Messages {
+			if message.To == 0 {
+				continue
+			}
+			private++
+			if message.Share != nil {
+				t.Errorf("private message from %d to %d carries its share", message.From, message.To)
+			}
+		}
+	}
+	if private != 4*3 {
+		t.Errorf("transcript holds %d private messages, want %d", private, 4*3)
+	}
+}
+
+func TestDKG_RunInvalidParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		n, k      int
+		behaviors map[int]dkg.Behavior
+	}{
+		{name: "threshold too low", n: 3, k: 1},
+		{name: "fewer participants than the threshold", n: 2, k: 3},
+		{name: "too many participants", n: dkg.MaxParticipants + 1, k: 2},
+		{name: "behavior of an unknown participant", n: 3, k: 2, behaviors: map[int]dkg.Behavior{4: {}}},
+		{name: "corrupt share to itself", n: 3, k: 2, behaviors: map[int]dkg.Behavior{1: {CorruptShares: []int{1}}}},
+		{name: "complaint against an unknown dealer", n: 3, k: 2, behaviors: map[int]dkg.Behavior{1: {FalseComplaints: []int{9}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dkg.Run(tt.n, tt.k, tt.behaviors); err == nil {
+				t.Error("Run() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDKG_RunEveryDealerDisqualified(t *testing.T) {
+	behaviors := map[int]dkg.Behavior{
+		1: {CorruptShares: []int{2}, WithholdAnswers: true},
+		2: {CorruptShares: []int{1}, WithholdAnswers: true},
+	}
+	if _, err := dkg.Run(2, 2, behaviors); !errors.Is(err, dkg.ErrNoQualifiedDealers) {
+		t.Errorf("Run() error = %v, want %v", err, dkg.ErrNoQualifiedDealers)
+	}
+}
+
+func TestDKG_ReplayTampered(t *testing.T) {
+	result, err := dkg.Run(5, 3, map[int]dkg.Behavior{2: {CorruptShares: []int{4}}})
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	// each case tampers with a fresh copy of the transcript
+	encoded, err := json.Marshal(result.Transcript)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(transcript *dkg.Transcript)
+	}{
+		{
+			name:   "public key replaced",
+			tamper: func(transcript *dkg.Transcript) { transcript.PublicKey = result.KeyShares[0].Value },
+		},
+		{
+			name:   "qualified dealer dropped",
+			tamper: func(transcript *dkg.Transcript) { transcript.Qualified = transcript.Qualified[1:] },
+		},
+		{
+			name:   "round missing",
+			tamper: func(transcript *dkg.Transcript) { transcript.Steps = transcript.Steps[:5] },
+		},
+		{
+			name: "rounds swapped",
+			tamper: func(transcript *dkg.Transcript) {
+				transcript.Steps[1], transcript.Steps[2] = transcript.Steps[2], transcript.Steps[1]
+			},
+		},
+		{
+			name: "answer withheld after the fact",
+			tamper: func(transcript *dkg.Transcript) {
+				transcript.Steps[dkg.RoundAnswer-1].Messages = nil
+			},
+		},
+		{
+			name: "message from an unknown participant",
+			tamper: func(transcript *dkg.Transcript) {
+				transcript.Steps[0].Messages[0].From = 9
+			},
+		},
+		{
+			name:   "unsupported group",
+			tamper: func(transcript *dkg.Transcript) { transcript.Group = "P-256" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transcript dkg.Transcript
+			if err := json.Unmarshal(encoded, &transcript); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+			}
+			if _, err := dkg.Replay(&transcript); err != nil {
+				t.Fatalf("Replay() of the untouched transcript error = %v, want nil", err)
+			}
+
+			tt.tamper(&transcript)
+			if _, err := dkg.Replay(&transcript); err == nil {
+				t.Error("Replay() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDKG_ReplayRejectsFloodedRound(t *testing.T) {
+	result, err := dkg.Run(4, 2, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	// the complaint round of 4 participants holds at most 4*3 messages
+	flood := make([]dkg.Message, 13)
+	for i := range flood {
+		flood[i] = dkg.Message{Round: dkg.RoundComplain, From: 1, Accused: 2}
+	}
+	result.Transcript.Steps[dkg.RoundComplain-1].Messages = flood
+
+	_, err = dkg.Replay(result.Transcript)
+	if err == nil || !strings.Contains(err.Error(), "at most 12 are possible") {
+		t.Errorf("Replay() error = %v, want the message limit of the complain round", err)
+	}
+}
+
+func TestDKG_Handler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := api_dkg.NewDKGHandler()
+
+	// the handlers log through the request context, which keeps the default file logger out of the test run
+	reqCtx := logging.WithContext(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	serve := func(run func(ctx *gin.Context) error, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest("POST", "/dkg", strings.NewReader(body)).WithContext(reqCtx)
+		_ = run(ctx)
+		return recorder
+	}
+
+	recorder := serve(handler.Simulate, `{"participants": 4, "threshold": 3, "behaviors": {"2": {"corrupt_feldman": true}}}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Simulate() status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+	var simulated types.DKGResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &simulated); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+
+	replayBody, _ := json.Marshal(types.DKGReplayRequest{Transcript: simulated.Transcript})
+	recorder = serve(handler.Replay, string(replayBody))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Replay() status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+	var replayed types.DKGReplayResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &replayed); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+	if len(replayed.Checkpoints) != 6 || !slices.Equal(replayed.Checkpoints[5].Exposed, []int{2}) {
+		t.Errorf("Replay() checkpoints = %+v, want 6 rounds exposing dealer 2", replayed.Checkpoints)
+	}
+
+	tests := []struct {
+		name       string
+		run        func(ctx *gin.Context) error
+		body       string
+		wantStatus int
+	}{
+		{name: "malformed request", run: handler.Simulate, body: `{"participants": "four"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid parameters", run: handler.Simulate, body: `{"participants": 2, "threshold": 3}`, wantStatus: http.StatusBadRequest},
+		{name: "missing transcript", run: handler.Replay, body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "oversized body", run: handler.Replay, body: `{"padding": "` + strings.Repeat("a", int(constants.MAX_DKG_BODY_BYTES)) + `"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "inconsistent transcript", run: handler.Replay, body: `{"transcript": {"participants": 3, "threshold": 2, "group": "edwards25519"}}`, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if recorder := serve(tt.run, tt.body); recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
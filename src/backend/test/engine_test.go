@@ -0,0 +1,355 @@
+package test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/engine"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func randomBytes(tb testing.TB, n int) []byte {
+	tb.Helper()
+
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		tb.Fatalf("rand.Read() error = %v, want nil", err)
+	}
+	return data
+}
+
+func TestEngine_InteroperatesWithSSS(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		workers int
+	}{
+		{name: "small secret on one worker", size: 32, workers: 1},
+		{name: "small secret on several workers", size: 32, workers: 4},
+		{name: "payload above the parallel threshold", size: 4*engine.DefaultParallelThreshold + 17, workers: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := engine.New(tt.workers)
+			secret := randomBytes(t, tt.size)
+
+			shares, err := e.Split(secret, 5, 3)
+			if err != nil {
+				t.Fatalf("Split() error = %v, want nil", err)
+			}
+			got, err := sss.Combine([]sss.Share{shares[4], shares[0], shares[2]})
+			if err != nil {
+				t.Fatalf("sss.Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("sss.Combine() of engine shares differs from the secret")
+			}
+
+			shares, err = sss.Split(secret, 5, 3)
+			if err != nil {
+				t.Fatalf("sss.Split() error = %v, want nil", err)
+			}
+			got, err = e.Combine(shares[1:4])
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("Combine() of sss shares differs from the secret")
+			}
+		})
+	}
+}
+
+func TestEngine_Coefficients(t *testing.T) {
+	e := engine.New(0)
+
+	coeffs, err := e.Coefficients([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Coefficients() error = %v, want nil", err)
+	}
+	// the basis polynomials at 0 sum to 1, the value of the constant polynomial 1
+	if coeffs[0]^coeffs[1]^coeffs[2] != 1 {
+		t.Errorf("Coefficients() = %v, want coefficients summing to 1", coeffs)
+	}
+
+	coeffs[0] ^= 0xff
+	again, err := e.Coefficients([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Coefficients() error = %v, want nil", err)
+	}
+	if again[0] == coeffs[0] {
+		t.Errorf("Coefficients() returned the cached slice, want a copy")
+	}
+
+	for _, xs := range [][]byte{{0, 1}, {3, 3}} {
+		if _, err := e.Coefficients(xs); err == nil {
+			t.Errorf("Coefficients(%v) error = nil, want error", xs)
+		}
+	}
+}
+
+func TestEngine_Batch(t *testing.T) {
+	e := engine.New(4)
+
+	secrets := make([][]byte, 100)
+	for i := range secrets {
+		secrets[i] = randomBytes(t, 16+i)
+	}
+
+	batch, err := e.SplitBatch(secrets, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitBatch() error = %v, want nil", err)
+	}
+
+	refreshed, err := e.RefreshBatch(batch, 3)
+	if err != nil {
+		t.Fatalf("RefreshBatch() error = %v, want nil", err)
+	}
+
+	subsets := make([][]sss.Share, len(refreshed))
+	for i, shares := range refreshed {
+		if shares[0].Epoch != 1 {
+			t.Fatalf("RefreshBatch() epoch = %d, want 1", shares[0].Epoch)
+		}
+		if bytes.Equal(shares[0].Y, batch[i][0].Y) {
+			t.Errorf("RefreshBatch() left share %d of item %d unchanged", shares[0].X, i)
+		}
+		subsets[i] = []sss.Share{shares[i%5], shares[(i+1)%5], shares[(i+3)%5]}
+	}
+
+	got, err := e.CombineBatch(subsets)
+	if err != nil {
+		t.Fatalf("CombineBatch() error = %v, want nil", err)
+	}
+	for i := range secrets {
+		if !bytes.Equal(got[i], secrets[i]) {
+			t.Errorf("CombineBatch() item %d differs from the secret", i)
+		}
+	}
+}
+
+// TestEngine_SetParallelThresholdConcurrently changes the threshold while splits and combines run,
+// which the race detector flags unless the threshold is accessed atomically.
+func TestEngine_SetParallelThresholdConcurrently(t *testing.T) {
+	e := engine.New(2)
+	secret := randomBytes(t, 4096)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.SetParallelThreshold(1024 * (i + 1))
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shares, err := e.Split(secret, 3, 2)
+			if err != nil {
+				t.Errorf("Split() error = %v, want nil", err)
+				return
+			}
+			got, err := e.Combine(shares[1:])
+			if err != nil {
+				t.Errorf("Combine() error = %v, want nil", err)
+				return
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("Combine() differs from the secret")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEngine_InvalidInput(t *testing.T) {
+	e := engine.New(2)
+	shares, err := e.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	refreshed, err := e.RefreshBatch([][]sss.Share{shares}, 2)
+	if err != nil {
+		t.Fatalf("RefreshBatch() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "split rejects empty secret",
+			run:  func() error { _, err := e.Split(nil, 3, 2); return err },
+		},
+		{
+			name: "split rejects threshold below 2",
+			run:  func() error { _, err := e.Split([]byte("s"), 3, 1); return err },
+		},
+		{
+			name: "split rejects more than 255 shares",
+			run:  func() error { _, err := e.Split([]byte("s"), 256, 2); return err },
+		},
+		{
+			name: "combine rejects a single share",
+			run:  func() error { _, err := e.Combine(shares[:1]); return err },
+		},
+		{
+			name: "combine rejects duplicate X values",
+			run:  func() error { _, err := e.Combine([]sss.Share{shares[0], shares[0]}); return err },
+		},
+		{
+			name: "combine rejects shares from different epochs",
+			run:  func() error { _, err := e.Combine([]sss.Share{shares[0], refreshed[0][1]}); return err },
+		},
+		{
+			name: "split batch rejects an empty secret",
+			run:  func() error { _, err := e.SplitBatch([][]byte{[]byte("s"), nil}, 3, 2); return err },
+		},
+		{
+			name: "combine batch rejects a malformed item",
+			run: func() error {
+				_, err := e.CombineBatch([][]sss.Share{shares[:2], {shares[0], {X: 2, Y: []byte{1}}}})
+				return err
+			},
+		},
+		{
+			name: "refresh batch rejects threshold below 2",
+			run:  func() error { _, err := e.RefreshBatch([][]sss.Share{shares}, 1); return err },
+		},
+		{
+			name: "refresh batch rejects threshold above the number of shares",
+			run:  func() error { _, err := e.RefreshBatch([][]sss.Share{shares}, len(shares)+1); return err },
+		},
+		{
+			name: "refresh batch rejects an item with fewer shares than the threshold",
+			run:  func() error { _, err := e.RefreshBatch([][]sss.Share{shares, shares[:2]}, 3); return err },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err == nil {
+				t.Errorf("error = nil, want error")
+			}
+		})
+	}
+}
+
+// The benchmarks compare the engine with package sss on a large payload and on a batch of small secrets,
+// e.g. go test ./test -run '^$' -bench 'Engine|SSS_Bulk' -benchmem
+
+const benchPayload = 1 << 20
+
+func BenchmarkSSS_BulkSplit(b *testing.B) {
+	secret := randomBytes(b, benchPayload)
+	b.SetBytes(benchPayload)
+	for b.Loop() {
+		if _, err := sss.Split(secret, 5, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEngine_Split(b *testing.B) {
+	e := engine.New(0)
+	secret := randomBytes(b, benchPayload)
+	b.SetBytes(benchPayload)
+	for b.Loop() {
+		if _, err := e.Split(secret, 5, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSSS_BulkCombine(b *testing.B) {
+	shares, err := sss.Split(randomBytes(b, benchPayload), 5, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(benchPayload)
+	for b.Loop() {
+		if _, err := sss.Combine(shares[:3]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEngine_Combine(b *testing.B) {
+	e := engine.New(0)
+	shares, err := e.Split(randomBytes(b, benchPayload), 5, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(benchPayload)
+	for b.Loop() {
+		if _, err := e.Combine(shares[:3]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchBatch: a thousand stored 32-byte secrets, the shape of a bulk refresh.
+func benchBatch(b *testing.B) [][]sss.Share {
+	b.Helper()
+
+	batch := make([][]sss.Share, 1000)
+	for i := range batch {
+		shares, err := sss.Split(randomBytes(b, 32), 5, 3)
+		if err != nil {
+			b.Fatal(err)
+		}
+		batch[i] = shares
+	}
+	return batch
+}
+
+func BenchmarkSSS_BulkCombineBatch(b *testing.B) {
+	batch := benchBatch(b)
+	for b.Loop() {
+		for _, shares := range batch {
+			if _, err := sss.Combine(shares[:3]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkEngine_CombineBatch(b *testing.B) {
+	e := engine.New(0)
+	batch := benchBatch(b)
+	subsets := make([][]sss.Share, len(batch))
+	for i, shares := range batch {
+		subsets[i] = shares[:3]
+	}
+	for b.Loop() {
+		if _, err := e.CombineBatch(subsets); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSSS_BulkRefreshBatch(b *testing.B) {
+	batch := benchBatch(b)
+	for b.Loop() {
+		for _, shares := range batch {
+			if _, err := sss.Refresh(shares, 3); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkEngine_RefreshBatch(b *testing.B) {
+	e := engine.New(0)
+	batch := benchBatch(b)
+	for b.Loop() {
+		if _, err := e.RefreshBatch(batch, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/engine"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/entropy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// readN reads n bytes from r, failing the test on error.
+func readN(t *testing.T, r io.Reader, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	return b
+}
+
+// repeatingReader yields the same byte forever.
+type repeatingReader byte
+
+func (r repeatingReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(r)
+	}
+	return len(b), nil
+}
+
+// biasedReader yields 0 every other byte and random bytes in between.
+type biasedReader struct {
+	count int
+}
+
+func (r *biasedReader) Read(b []byte) (int, error) {
+	if _, err := rand.Read(b); err != nil {
+		return 0, err
+	}
+	for i := range b {
+		if (r.count+i)%2 == 0 {
+			b[i] = 0
+		}
+	}
+	r.count += len(b)
+	return len(b), nil
+}
+
+func TestEntropy_DRBG(t *testing.T) {
+	tests := []struct {
+		name      string
+		seedA     []byte
+		seedB     []byte
+		wantEqual bool
+	}{
+		{name: "same seed gives the same stream", seedA: []byte("seed"), seedB: []byte("seed"), wantEqual: true},
+		{name: "different seeds give different streams", seedA: []byte("seed-a"), seedB: []byte("seed-b"), wantEqual: false},
+		{name: "empty seed is deterministic", seedA: nil, seedB: []byte{}, wantEqual: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := entropy.NewDRBG(tt.seedA)
+			b := entropy.NewDRBG(tt.seedB)
+
+			// reads of different sizes must still agree, since every read rekeys the generator
+			outA := append(readN(t, a, 7), readN(t, a, 100)...)
+			outB := append(readN(t, b, 7), readN(t, b, 100)...)
+			if bytes.Equal(outA, outB) != tt.wantEqual {
+				t.Errorf("streams equal = %v, want %v", !tt.wantEqual, tt.wantEqual)
+			}
+		})
+	}
+
+	t.Run("successive reads differ", func(t *testing.T) {
+		d := entropy.NewDRBG([]byte("seed"))
+		if bytes.Equal(readN(t, d, 32), readN(t, d, 32)) {
+			t.Error("successive reads returned the same bytes")
+		}
+	})
+
+	// the key is SHA-256 of the seed, and each read outputs the ChaCha20 keystream (zero nonce) after the 32 bytes
+	// that become the next key; cross-checked against an independent ChaCha20 implementation
+	t.Run("known answer", func(t *testing.T) {
+		d := entropy.NewDRBG([]byte("seed"))
+		reads := []struct {
+			n    int
+			want string
+		}{
+			{n: 7, want: "e08fbc64f93c9c"},
+			{n: 100, want: "96ecaacd59f3c6a14689d3ef1b71ce22e4750b2257b0fe227c384c97e2f051d671beedc6fa8933ecf90091b84e56f21a" +
+				"8e2d6acdb9943a8f2f6e2143e2d8ce3a1d7b40c4332c4d78c443da383655b1d12e099279c0e14e3bda4b1bf4129fd5934c391bab"},
+		}
+		for _, read := range reads {
+			if got := hex.EncodeToString(readN(t, d, read.n)); got != read.want {
+				t.Errorf("read of %d bytes = %s, want %s", read.n, got, read.want)
+			}
+		}
+	})
+}
+
+func TestEntropy_SeededComponents(t *testing.T) {
+	seed := []byte("known-answer seed")
+
+	t.Run("Argon2id salts are reproducible", func(t *testing.T) {
+		hashA := security.NewArgon2idHash(1, 64*1024, 1, 32, 16, entropy.WithSeed(seed))
+		hashB := security.NewArgon2idHash(1, 64*1024, 1, 32, 16, entropy.WithSeed(seed))
+		hashC := security.NewArgon2idHash(1, 64*1024, 1, 32, 16, entropy.WithSeed([]byte("other seed")))
+
+		a, err := hashA.GenerateHash([]byte("password"), nil)
+		if err != nil {
+			t.Fatalf("GenerateHash failed: %v", err)
+		}
+		b, err := hashB.GenerateHash([]byte("password"), nil)
+		if err != nil {
+			t.Fatalf("GenerateHash failed: %v", err)
+		}
+		c, err := hashC.GenerateHash([]byte("password"), nil)
+		if err != nil {
+			t.Fatalf("GenerateHash failed: %v", err)
+		}
+
+		if !bytes.Equal(a.Salt, b.Salt) || !bytes.Equal(a.Hash, b.Hash) {
+			t.Error("hashers with the same seed produced different salts or hashes")
+		}
+		if bytes.Equal(a.Salt, c.Salt) {
+			t.Error("hashers with different seeds produced the same salt")
+		}
+
+		// the salt is the first 16 bytes of the DRBG seeded with seed
+		if got, want := hex.EncodeToString(a.Salt), "f4f9770399a2c1f8e399d1286c21d223"; got != want {
+			t.Errorf("salt = %s, want %s", got, want)
+		}
+		if got, want := string(a.Hash), "086e46968ac2d7d88d105183e0cd3918cc19769f1c03c88f0331f49dae000f8c"; got != want {
+			t.Errorf("hash = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("engine splits are reproducible", func(t *testing.T) {
+		secret := []byte("engine secret")
+		sharesA, err := engine.New(2, entropy.WithSeed(seed)).Split(secret, 5, 3)
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+		sharesB, err := engine.New(2, entropy.WithSeed(seed)).Split(secret, 5, 3)
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+
+		for i := range sharesA {
+			if !bytes.Equal(sharesA[i].Y, sharesB[i].Y) {
+				t.Errorf("share %d differs for the same seed", i+1)
+			}
+		}
+
+		got, err := sss.Combine(sharesA[:3])
+		if err != nil {
+			t.Fatalf("Combine failed: %v", err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("Combine = %q, want %q", got, secret)
+		}
+	})
+
+	t.Run("engine batches are reproducible", func(t *testing.T) {
+		// enough secrets to keep every worker busy, so that scheduling would show in the output
+		secrets := make([][]byte, 64)
+		for i := range secrets {
+			secrets[i] = bytes.Repeat([]byte{byte(i)}, 1+i%17)
+		}
+
+		splitA, err := engine.New(4, entropy.WithSeed(seed)).SplitBatch(secrets, 5, 3)
+		if err != nil {
+			t.Fatalf("SplitBatch failed: %v", err)
+		}
+		splitB, err := engine.New(4, entropy.WithSeed(seed)).SplitBatch(secrets, 5, 3)
+		if err != nil {
+			t.Fatalf("SplitBatch failed: %v", err)
+		}
+
+		// a batch draws its randomness in secret order, as splitting the secrets one by one does
+		sequential := engine.New(4, entropy.WithSeed(seed))
+		for i, secret := range secrets {
+			shares, err := sequential.Split(secret, 5, 3)
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+			for j := range shares {
+				if !bytes.Equal(splitA[i][j].Y, splitB[i][j].Y) || !bytes.Equal(splitA[i][j].Y, shares[j].Y) {
+					t.Fatalf("secret %d share %d differs for the same seed", i, j+1)
+				}
+			}
+		}
+
+		refreshA, err := engine.New(4, entropy.WithSeed(seed)).RefreshBatch(splitA, 3)
+		if err != nil {
+			t.Fatalf("RefreshBatch failed: %v", err)
+		}
+		refreshB, err := engine.New(4, entropy.WithSeed(seed)).RefreshBatch(splitA, 3)
+		if err != nil {
+			t.Fatalf("RefreshBatch failed: %v", err)
+		}
+		for i := range refreshA {
+			for j := range refreshA[i] {
+				if !bytes.Equal(refreshA[i][j].Y, refreshB[i][j].Y) {
+					t.Fatalf("refreshed secret %d share %d differs for the same seed", i, j+1)
+				}
+			}
+
+			got, err := sss.Combine(refreshA[i][2:])
+			if err != nil {
+				t.Fatalf("Combine failed: %v", err)
+			}
+			if !bytes.Equal(got, secrets[i]) {
+				t.Errorf("refreshed secret %d = %x, want %x", i, got, secrets[i])
+			}
+		}
+	})
+}
+
+func TestEntropy_SetDefault(t *testing.T) {
+	seed := []byte("process-wide seed")
+	secret := []byte("package-level secret")
+
+	split := func() []sss.Share {
+		previous := entropy.SetDefault(entropy.NewDRBG(seed))
+		defer entropy.SetDefault(previous)
+
+		shares, err := sss.Split(secret, 4, 2)
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+		return shares
+	}
+
+	a, b := split(), split()
+	for i := range a {
+		if !bytes.Equal(a[i].Y, b[i].Y) {
+			t.Errorf("share %d differs under the same default seed", i+1)
+		}
+	}
+
+	if entropy.Default() != rand.Reader {
+		t.Error("default source was not restored")
+	}
+
+	// a nil reader restores crypto/rand
+	entropy.SetDefault(entropy.NewDRBG(seed))
+	entropy.SetDefault(nil)
+	if entropy.Default() != rand.Reader {
+		t.Error("SetDefault(nil) did not restore crypto/rand")
+	}
+}
+
+func TestEntropy_HealthChecked(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        io.Reader
+		minEntropy float64
+		wantErr    error
+	}{
+		{name: "operating system source passes", src: rand.Reader, minEntropy: 8},
+		{name: "DRBG passes", src: entropy.NewDRBG([]byte("seed")), minEntropy: 8},
+		{name: "stuck source fails the repetition count test", src: repeatingReader(0x42), minEntropy: 8, wantErr: entropy.ErrHealthTest},
+		{name: "zero source fails", src: bytes.NewReader(make([]byte, 4096)), minEntropy: 8, wantErr: entropy.ErrHealthTest},
+		{name: "biased source fails the adaptive proportion test", src: &biasedReader{}, minEntropy: 8, wantErr: entropy.ErrHealthTest},
+		{name: "biased source passes under a lower entropy claim", src: &biasedReader{}, minEntropy: 0.5},
+		{name: "short source reports its own error", src: bytes.NewReader(make([]byte, 10)), minEntropy: 8, wantErr: io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := entropy.NewHealthChecked(tt.src, tt.minEntropy)
+			if err != nil {
+				t.Fatalf("NewHealthChecked failed: %v", err)
+			}
+
+			b := make([]byte, 64*1024)
+			_, err = h.Read(b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("a failure is latched", func(t *testing.T) {
+		stuck, err := entropy.NewHealthChecked(repeatingReader(0), 8)
+		if err != nil {
+			t.Fatalf("NewHealthChecked failed: %v", err)
+		}
+		if _, err := stuck.Read(make([]byte, 1)); !errors.Is(err, entropy.ErrHealthTest) {
+			t.Fatalf("Read error = %v, want %v", err, entropy.ErrHealthTest)
+		}
+		for range 3 {
+			if _, err := stuck.Read(make([]byte, 1)); !errors.Is(err, entropy.ErrHealthTest) {
+				t.Errorf("Read after failure error = %v, want %v", err, entropy.ErrHealthTest)
+			}
+		}
+	})
+
+	t.Run("invalid min-entropy is rejected", func(t *testing.T) {
+		for _, minEntropy := range []float64{0, -1, 8.5, 9} {
+			if _, err := entropy.NewHealthChecked(rand.Reader, minEntropy); err == nil {
+				t.Errorf("NewHealthChecked(%v) succeeded, want an error", minEntropy)
+			}
+		}
+	})
+}
+
+func TestEntropy_NewReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []entropy.Option
+		wantErr bool
+	}{
+		{name: "defaults to the process-wide source"},
+		{name: "explicit reader", opts: []entropy.Option{entropy.WithReader(entropy.NewDRBG([]byte("seed")))}},
+		{name: "health-checked seed", opts: []entropy.Option{entropy.WithSeed([]byte("seed")), entropy.WithHealthChecks(8)}},
+		{name: "health-checked default", opts: []entropy.Option{entropy.WithHealthChecks(8)}},
+		{name: "health checks over a stuck source", opts: []entropy.Option{entropy.WithReader(repeatingReader(1)), entropy.WithHealthChecks(8)}, wantErr: true},
+		{name: "invalid min-entropy", opts: []entropy.Option{entropy.WithHealthChecks(0)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := entropy.NewReader(tt.opts...)
+			_, err := io.ReadFull(r, make([]byte, 32))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid options surface through components", func(t *testing.T) {
+		hash := security.NewArgon2idHash(1, 64*1024, 1, 32, 16, entropy.WithHealthChecks(9))
+		if _, err := hash.GenerateHash([]byte("password"), nil); err == nil {
+			t.Error("GenerateHash succeeded with an invalid entropy configuration")
+		}
+	})
+}
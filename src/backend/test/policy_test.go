@@ -0,0 +1,236 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/policy"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestPolicy_Parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		want      string
+		wantError bool
+	}{
+		{
+			name:      "parses AND, OR and threshold gates",
+			expr:      "(alice AND bob) OR 2-of(carol, dave, erin)",
+			want:      "(alice AND bob) OR 2-of(carol, dave, erin)",
+			wantError: false,
+		},
+		{
+			name:      "AND binds tighter than OR",
+			expr:      "alice and bob or carol",
+			want:      "(alice AND bob) OR carol",
+			wantError: false,
+		},
+		{
+			name:      "flattens chains of the same operator",
+			expr:      "a OR b OR c",
+			want:      "a OR b OR c",
+			wantError: false,
+		},
+		{
+			name:      "nests gates inside thresholds",
+			expr:      "2-of(alice, bob AND carol, 1-of(dave, erin))",
+			want:      "2-of(alice, bob AND carol, 1-of(dave, erin))",
+			wantError: false,
+		},
+		{
+			name:      "accepts e-mail style labels",
+			expr:      "alice@example.com AND bob_smith",
+			want:      "alice@example.com AND bob_smith",
+			wantError: false,
+		},
+		{
+			name:      "accepts labels ending in -of",
+			expr:      "chief-of AND 2-of(head-of, alice, 3-of)",
+			want:      "chief-of AND 2-of(head-of, alice, 3-of)",
+			wantError: false,
+		},
+		{
+			name:      "rejects a threshold above its operands",
+			expr:      "3-of(alice, bob)",
+			wantError: true,
+		},
+		{
+			name:      "rejects a zero threshold",
+			expr:      "0-of(alice, bob)",
+			wantError: true,
+		},
+		{
+			name:      "rejects unbalanced parentheses",
+			expr:      "(alice AND bob",
+			wantError: true,
+		},
+		{
+			name:      "rejects a dangling operator",
+			expr:      "alice AND",
+			wantError: true,
+		},
+		{
+			name:      "rejects trailing tokens",
+			expr:      "alice bob",
+			wantError: true,
+		},
+		{
+			name:      "rejects invalid characters",
+			expr:      "alice & bob",
+			wantError: true,
+		},
+		{
+			name:      "rejects an empty expression",
+			expr:      "",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := policy.Parse(tt.expr)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Parse() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if got := node.String(); got != tt.want {
+				t.Errorf("Parse().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_SplitAndCombine(t *testing.T) {
+	secret := []byte("launch codes")
+	node, err := policy.Parse("(alice AND bob) OR 2-of(carol, dave, erin AND alice)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want nil", err)
+	}
+
+	shares, err := policy.Split(node, secret)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	if len(shares) != 6 {
+		t.Fatalf("Split() returned %d shares, want 6", len(shares))
+	}
+
+	holding := func(labels ...string) []policy.Share {
+		var out []policy.Share
+		for _, share := range shares {
+			if slices.Contains(labels, share.Label) {
+				out = append(out, share)
+			}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name          string
+		shares        []policy.Share
+		wantBranch    string
+		wantAccessErr bool
+	}{
+		{
+			name:       "alice and bob satisfy the AND branch",
+			shares:     holding("alice", "bob"),
+			wantBranch: "alice AND bob",
+		},
+		{
+			name:       "carol and dave satisfy the threshold branch",
+			shares:     holding("carol", "dave"),
+			wantBranch: "2-of(carol, dave)",
+		},
+		{
+			name:       "dave, erin and alice satisfy the threshold through the nested AND",
+			shares:     holding("dave", "erin", "alice"),
+			wantBranch: "2-of(dave, erin AND alice)",
+		},
+		{
+			name:       "everyone reports the first satisfied branch",
+			shares:     shares,
+			wantBranch: "alice AND bob",
+		},
+		{
+			name:          "alice and carol do not satisfy the policy",
+			shares:        holding("alice", "carol"),
+			wantAccessErr: true,
+		},
+		{
+			name:          "erin alone does not satisfy the policy",
+			shares:        holding("erin"),
+			wantAccessErr: true,
+		},
+		{
+			name:          "no shares do not satisfy the policy",
+			shares:        nil,
+			wantAccessErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := policy.Combine(node, tt.shares)
+			var accessErr *sss.AccessError
+			if errors.As(err, &accessErr) != tt.wantAccessErr {
+				t.Fatalf("Combine() error = %v, want access error %v", err, tt.wantAccessErr)
+			}
+			if tt.wantAccessErr {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(result.Secret, secret) {
+				t.Errorf("Combine() secret = %q, want %q", result.Secret, secret)
+			}
+			if got := result.Satisfied.String(); got != tt.wantBranch {
+				t.Errorf("Combine() satisfied = %q, want %q", got, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestPolicy_Combine_InvalidShares(t *testing.T) {
+	node, err := policy.Parse("alice AND bob")
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want nil", err)
+	}
+	shares, err := policy.Split(node, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	mislabelled := slices.Clone(shares)
+	mislabelled[1].Label = "mallory"
+
+	conflicting := append(slices.Clone(shares), policy.Share{Label: "bob", Path: shares[1].Path, Value: []byte("forged")})
+
+	tests := []struct {
+		name   string
+		shares []policy.Share
+	}{
+		{
+			name:   "rejects a share under the wrong label",
+			shares: mislabelled,
+		},
+		{
+			name:   "rejects conflicting shares for the same leaf",
+			shares: conflicting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := policy.Combine(node, tt.shares); err == nil {
+				t.Error("Combine() error = nil, want error")
+			}
+		})
+	}
+}
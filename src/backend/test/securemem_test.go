@@ -0,0 +1,176 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/securemem"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSecureMem_Lifecycle(t *testing.T) {
+	source := []byte("correct horse battery staple")
+	want := append([]byte{}, source...)
+
+	buf, err := securemem.FromBytes(source)
+	if err != nil {
+		t.Fatalf("FromBytes() error = %v, want nil", err)
+	}
+	if !bytes.Equal(source, make([]byte, len(source))) {
+		t.Errorf("FromBytes() left the source unwiped: %q", source)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Bytes() = %q, want %q", buf.Bytes(), want)
+	}
+	if buf.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", buf.Len(), len(want))
+	}
+	t.Logf("Locked() = %v", buf.Locked())
+
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+	if buf.Bytes() != nil || buf.Len() != 0 {
+		t.Errorf("Bytes() after Close() = %q, want nil", buf.Bytes())
+	}
+	if err := buf.Close(); err != nil {
+		t.Errorf("second Close() error = %v, want nil", err)
+	}
+
+	if _, err := securemem.New(0); err == nil {
+		t.Errorf("New(0) error = nil, want error")
+	}
+}
+
+func TestSecureMem_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		want      string
+		wantError bool
+	}{
+		{name: "plain string", json: `"hunter2"`, want: "hunter2"},
+		{name: "escapes", json: `"a\"b\\c\/d\n\t"`, want: "a\"b\\c/d\n\t"},
+		{name: "unicode escapes", json: `"caf\u00e9 \ud83d\udd11"`, want: "café 🔑"},
+		{name: "raw UTF-8", json: `"pässwörd"`, want: "pässwörd"},
+		{name: "lone surrogate", json: `"\ud83d!"`, want: "�!"},
+		{name: "rejects a number", json: `42`, wantError: true},
+		{name: "rejects an empty string", json: `""`, wantError: true},
+		{name: "rejects an invalid escape", json: `"\x"`, wantError: true},
+		{name: "rejects a truncated unicode escape", json: `"\u12"`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf securemem.SecretBuffer
+			defer buf.Close()
+
+			err := buf.UnmarshalJSON([]byte(tt.json))
+			if (err != nil) != tt.wantError {
+				t.Fatalf("UnmarshalJSON() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && string(buf.Bytes()) != tt.want {
+				t.Errorf("UnmarshalJSON() = %q, want %q", buf.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSecureMem_RequestBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantError bool
+	}{
+		{name: "binds the password into a secret buffer", body: `{"username":"alice","password":"s3cret"}`},
+		{name: "requires the password", body: `{"username":"alice"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("POST", "/login", strings.NewReader(tt.body))
+
+			var req types.LoginRequest
+			defer func() { _ = req.Password.Close() }()
+
+			err := ctx.ShouldBindJSON(&req)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ShouldBindJSON() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && string(req.Password.Bytes()) != "s3cret" {
+				t.Errorf("ShouldBindJSON() password = %q, want %q", req.Password.Bytes(), "s3cret")
+			}
+		})
+	}
+
+	// a SecretBuffer has no exported fields, so marshaling a request never reveals the password
+	password, err := securemem.FromBytes([]byte("s3cret"))
+	if err != nil {
+		t.Fatalf("FromBytes() error = %v, want nil", err)
+	}
+	defer password.Close()
+	if out, err := json.Marshal(types.LoginRequest{Username: "alice", Password: password}); err == nil && strings.Contains(string(out), "s3cret") {
+		t.Errorf("json.Marshal() = %s, want no password", out)
+	}
+}
+
+func TestSecureMem_Hasher(t *testing.T) {
+	hasher := security.NewArgon2idHash(1, 8*1024, 1, constants.ARGON2ID_DEFAULT_KEY_LEN, constants.ARGON2ID_DEFAULT_SALT_LEN)
+
+	password, err := securemem.FromBytes([]byte("password"))
+	if err != nil {
+		t.Fatalf("FromBytes() error = %v, want nil", err)
+	}
+
+	hashSalt, err := hasher.GenerateHashBuffer(password, nil)
+	if err != nil {
+		t.Fatalf("GenerateHashBuffer() error = %v, want nil", err)
+	}
+	if err := hasher.ComparePasswords([]byte("password"), hashSalt.Salt, hashSalt.Hash); err != nil {
+		t.Errorf("ComparePasswords() error = %v, want nil for the hash of the buffered password", err)
+	}
+	if err := hasher.ComparePasswordBuffer(password, hashSalt.Salt, hashSalt.Hash); err != nil {
+		t.Errorf("ComparePasswordBuffer() error = %v, want nil", err)
+	}
+
+	password.Close()
+	if err := hasher.ComparePasswordBuffer(password, hashSalt.Salt, hashSalt.Hash); err == nil {
+		t.Errorf("ComparePasswordBuffer() error = nil, want error for a closed buffer")
+	}
+}
+
+func TestSecureMem_CombineBuffer(t *testing.T) {
+	secret := []byte("the launch codes")
+	shares, err := sss.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	buf, err := sss.CombineBuffer(shares[1:4])
+	if err != nil {
+		t.Fatalf("CombineBuffer() error = %v, want nil", err)
+	}
+	defer buf.Close()
+	if !bytes.Equal(buf.Bytes(), secret) {
+		t.Errorf("CombineBuffer() = %q, want %q", buf.Bytes(), secret)
+	}
+
+	shares[0].Wipe()
+	if !bytes.Equal(shares[0].Y, make([]byte, len(secret))) {
+		t.Errorf("Wipe() left the share value %x", shares[0].Y)
+	}
+	if _, err := sss.CombineBuffer(shares[:1]); err == nil {
+		t.Errorf("CombineBuffer() error = nil, want error for a single share")
+	}
+}
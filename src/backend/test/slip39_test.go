@@ -0,0 +1,227 @@
+package test
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/slip39"
+)
+
+// slip39Vectors: the unmodified vectors.json of the SLIP-0039 reference implementation (trezor/python-shamir-mnemonic),
+// entries as [description, mnemonics, master secret, BIP-32 xprv], all using the passphrase "TREZOR".
+// An empty master secret marks an invalid set. The xprv is not checked since this package stops at the master secret.
+//
+//go:embed slip39_vectors.json
+var slip39Vectors []byte
+
+// slip39VectorCount: number of entries in the upstream vectors.json, guarding against a truncated fixture.
+const slip39VectorCount int = 45
+
+func TestSLIP39_Vectors(t *testing.T) {
+	var vectors [][]json.RawMessage
+	if err := json.Unmarshal(slip39Vectors, &vectors); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+	if len(vectors) != slip39VectorCount {
+		t.Fatalf("len(vectors) = %d, want %d", len(vectors), slip39VectorCount)
+	}
+
+	for i, vector := range vectors {
+		if len(vector) != 4 {
+			t.Fatalf("vector %d has %d fields, want 4", i, len(vector))
+		}
+
+		var (
+			name      string
+			mnemonics []string
+			secretHex string
+		)
+		if err := errors.Join(
+			json.Unmarshal(vector[0], &name),
+			json.Unmarshal(vector[1], &mnemonics),
+			json.Unmarshal(vector[2], &secretHex),
+		); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+		}
+
+		t.Run(name, func(t *testing.T) {
+			got, err := slip39.CombineMnemonics(mnemonics, []byte("TREZOR"))
+			if secretHex == "" {
+				if err == nil {
+					t.Errorf("CombineMnemonics() = %x, want error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CombineMnemonics() error = %v, want nil", err)
+			}
+			if hex.EncodeToString(got) != secretHex {
+				t.Errorf("CombineMnemonics() = %x, want %s", got, secretHex)
+			}
+		})
+	}
+}
+
+func TestSLIP39_GenerateAndCombineMnemonics(t *testing.T) {
+	masterSecret := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	tests := []struct {
+		name           string
+		groupThreshold int
+		groups         []slip39.Group
+		extendable     bool
+		pick           func([][]string) []string
+	}{
+		{
+			name:           "single 1-of-1 group",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 1, Count: 1}},
+			pick:           func(m [][]string) []string { return m[0] },
+		},
+		{
+			name:           "single 3-of-5 group",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 3, Count: 5}},
+			extendable:     true,
+			pick:           func(m [][]string) []string { return []string{m[0][4], m[0][1], m[0][2]} },
+		},
+		{
+			name:           "2 of 4 groups with mixed thresholds",
+			groupThreshold: 2,
+			groups: []slip39.Group{
+				{Threshold: 1, Count: 1},
+				{Threshold: 2, Count: 3},
+				{Threshold: 3, Count: 5},
+				{Threshold: 2, Count: 6},
+			},
+			pick: func(m [][]string) []string {
+				return []string{m[2][0], m[0][0], m[2][3], m[2][4]}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mnemonics, err := slip39.GenerateMnemonics(tt.groupThreshold, tt.groups, masterSecret, []byte("TREZOR"), tt.extendable, 0)
+			if err != nil {
+				t.Fatalf("GenerateMnemonics() error = %v, want nil", err)
+			}
+			for i, group := range tt.groups {
+				if len(mnemonics[i]) != group.Count {
+					t.Fatalf("GenerateMnemonics() group %d has %d mnemonics, want %d", i, len(mnemonics[i]), group.Count)
+				}
+			}
+
+			got, err := slip39.CombineMnemonics(tt.pick(mnemonics), []byte("TREZOR"))
+			if err != nil {
+				t.Fatalf("CombineMnemonics() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, masterSecret) {
+				t.Errorf("CombineMnemonics() = %q, want %q", got, masterSecret)
+			}
+
+			wrong, err := slip39.CombineMnemonics(tt.pick(mnemonics), []byte("wrong"))
+			if err != nil {
+				t.Fatalf("CombineMnemonics() error = %v, want nil for a wrong passphrase", err)
+			}
+			if bytes.Equal(wrong, masterSecret) {
+				t.Error("CombineMnemonics() with a wrong passphrase returned the master secret")
+			}
+		})
+	}
+}
+
+func TestSLIP39_GenerateMnemonics_InvalidParams(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x42}, 16)
+
+	tests := []struct {
+		name           string
+		groupThreshold int
+		groups         []slip39.Group
+		secret         []byte
+		passphrase     []byte
+		exponent       int
+	}{
+		{
+			name:           "rejects a master secret below 128 bits",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 2, Count: 3}},
+			secret:         secret[:14],
+		},
+		{
+			name:           "rejects an odd-length master secret",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 2, Count: 3}},
+			secret:         append(bytes.Clone(secret), 0x01),
+		},
+		{
+			name:           "rejects a group threshold above the group count",
+			groupThreshold: 2,
+			groups:         []slip39.Group{{Threshold: 2, Count: 3}},
+			secret:         secret,
+		},
+		{
+			name:           "rejects several members with threshold 1",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 1, Count: 3}},
+			secret:         secret,
+		},
+		{
+			name:           "rejects more than 16 members",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 2, Count: 17}},
+			secret:         secret,
+		},
+		{
+			name:           "rejects a non-printable passphrase",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 2, Count: 3}},
+			secret:         secret,
+			passphrase:     []byte("tab\tbed"),
+		},
+		{
+			name:           "rejects an iteration exponent above 15",
+			groupThreshold: 1,
+			groups:         []slip39.Group{{Threshold: 2, Count: 3}},
+			secret:         secret,
+			exponent:       16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := slip39.GenerateMnemonics(tt.groupThreshold, tt.groups, tt.secret, tt.passphrase, false, tt.exponent); err == nil {
+				t.Error("GenerateMnemonics() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSLIP39_ParseShare(t *testing.T) {
+	mnemonic := "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"
+
+	share, err := slip39.ParseShare(strings.ToUpper(mnemonic))
+	if err != nil {
+		t.Fatalf("ParseShare() error = %v, want nil", err)
+	}
+	if share.GroupThreshold != 1 || share.GroupCount != 1 || share.MemberThreshold != 1 || len(share.Value) != 16 {
+		t.Errorf("ParseShare() = %+v, want a 1-of-1 share of 16 bytes", share)
+	}
+	if got := share.Mnemonic(); got != mnemonic {
+		t.Errorf("Mnemonic() = %q, want %q", got, mnemonic)
+	}
+
+	mistyped := strings.Replace(mnemonic, "kidney", "keyboard", 1)
+	if _, err := slip39.ParseShare(mistyped); !errors.Is(err, slip39.ErrInvalidChecksum) {
+		t.Errorf("ParseShare() error = %v, want %v", err, slip39.ErrInvalidChecksum)
+	}
+	if _, err := slip39.ParseShare(strings.Replace(mnemonic, "kidney", "bitcoin", 1)); err == nil {
+		t.Error("ParseShare() error = nil, want error for a word outside the list")
+	}
+}
@@ -0,0 +1,218 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_SplitBlakleyAndCombineBlakley(t *testing.T) {
+	m127, _ := sss.NamedPrime(sss.Mersenne127)
+	p256, _ := sss.NamedPrime(sss.P256Order)
+
+	tests := []struct {
+		name      string
+		secret    *big.Int
+		n         int
+		k         int
+		prime     *big.Int
+		wantError bool
+	}{
+		{
+			name:      "shares a secret in 3 dimensions",
+			secret:    big.NewInt(123456789),
+			n:         5,
+			k:         3,
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "shares the largest field element",
+			secret:    new(big.Int).Sub(m127, big.NewInt(1)),
+			n:         4,
+			k:         4,
+			prime:     m127,
+			wantError: false,
+		},
+		{
+			name:      "shares zero in the plane",
+			secret:    big.NewInt(0),
+			n:         3,
+			k:         2,
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "rejects composite modulus",
+			secret:    big.NewInt(5),
+			n:         3,
+			k:         2,
+			prime:     big.NewInt(7917),
+			wantError: true,
+		},
+		{
+			name:      "rejects secret outside the field",
+			secret:    big.NewInt(7919),
+			n:         3,
+			k:         2,
+			prime:     big.NewInt(7919),
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			secret:    big.NewInt(1),
+			n:         3,
+			k:         1,
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects fewer shares than the threshold",
+			secret:    big.NewInt(1),
+			n:         2,
+			k:         3,
+			prime:     p256,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitBlakley(tt.secret, tt.n, tt.k, tt.prime)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitBlakley() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("SplitBlakley() returned %d shares, want %d", len(shares), tt.n)
+			}
+
+			got, err := sss.CombineBlakley(shares[len(shares)-tt.k:])
+			if err != nil {
+				t.Fatalf("CombineBlakley() error = %v, want nil", err)
+			}
+			if got.Cmp(tt.secret) != 0 {
+				t.Errorf("CombineBlakley() = %v, want %v", got, tt.secret)
+			}
+		})
+	}
+}
+
+func TestSSS_Hyperplane_Serialization(t *testing.T) {
+	p256, _ := sss.NamedPrime(sss.P256Order)
+	secret := big.NewInt(424242)
+
+	shares, err := sss.SplitBlakley(secret, 3, 2, p256)
+	if err != nil {
+		t.Fatalf("SplitBlakley() error = %v, want nil", err)
+	}
+
+	jsonData, err := json.Marshal(shares[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v, want nil", err)
+	}
+	var fromJSON sss.Hyperplane
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+
+	bsonData, err := bson.Marshal(shares[1])
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v, want nil", err)
+	}
+	var fromBSON sss.Hyperplane
+	if err := bson.Unmarshal(bsonData, &fromBSON); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v, want nil", err)
+	}
+
+	got, err := sss.CombineBlakley([]sss.Hyperplane{fromJSON, fromBSON})
+	if err != nil {
+		t.Fatalf("CombineBlakley() error = %v, want nil", err)
+	}
+	if got.Cmp(secret) != 0 {
+		t.Errorf("CombineBlakley() = %v, want %v", got, secret)
+	}
+}
+
+func TestSSS_CombineBlakley_InvalidShares(t *testing.T) {
+	p := big.NewInt(7919)
+	plane := func(index int, coeffs []int64, constant int64) sss.Hyperplane {
+		values := make([]*big.Int, len(coeffs))
+		for i, c := range coeffs {
+			values[i] = big.NewInt(c)
+		}
+		return sss.Hyperplane{Prime: p, Index: index, Coeffs: values, Const: big.NewInt(constant)}
+	}
+
+	tests := []struct {
+		name   string
+		shares []sss.Hyperplane
+	}{
+		{
+			name:   "rejects too few hyperplanes",
+			shares: []sss.Hyperplane{plane(1, []int64{1, 2, 3}, 4), plane(2, []int64{5, 6, 7}, 8)},
+		},
+		{
+			name:   "rejects parallel hyperplanes",
+			shares: []sss.Hyperplane{plane(1, []int64{1, 2}, 3), plane(2, []int64{2, 4}, 5)},
+		},
+		{
+			name:   "rejects duplicate indices",
+			shares: []sss.Hyperplane{plane(1, []int64{1, 2}, 3), plane(1, []int64{3, 1}, 5)},
+		},
+		{
+			name:   "rejects mismatched dimensions",
+			shares: []sss.Hyperplane{plane(1, []int64{1, 2}, 3), plane(2, []int64{3, 1, 4}, 5)},
+		},
+		{
+			name:   "rejects coefficients outside the field",
+			shares: []sss.Hyperplane{plane(1, []int64{1, 7919}, 3), plane(2, []int64{3, 1}, 5)},
+		},
+		{
+			name:   "rejects empty share list",
+			shares: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombineBlakley(tt.shares); err == nil {
+				t.Error("CombineBlakley() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_Blakley_Verify(t *testing.T) {
+	shares, err := sss.SplitWith(sss.Blakley, []byte("secret"), 5, 3)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+	if err := sss.VerifyShares(shares); err != nil {
+		t.Fatalf("VerifyShares() error = %v, want nil", err)
+	}
+
+	tampered := make([]sss.SchemeShare, len(shares))
+	copy(tampered, shares)
+	payload := append([]byte{}, tampered[4].Payload...)
+	payload[len(payload)-1] ^= 0x01
+	tampered[4].Payload = payload
+
+	if err := sss.VerifyShares(tampered); err == nil {
+		t.Error("VerifyShares() error = nil, want error for tampered share")
+	}
+
+	got, err := sss.CombineShares(tampered[:3])
+	if err != nil {
+		t.Fatalf("CombineShares() error = %v, want nil", err)
+	}
+	if string(got) != "secret" {
+		t.Errorf("CombineShares() = %q, want %q", got, "secret")
+	}
+}
@@ -0,0 +1,374 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_MignotteSequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		k         int
+		bits      int
+		wantError bool
+	}{
+		{
+			name:      "generates a (3, 5) sequence",
+			n:         5,
+			k:         3,
+			bits:      32,
+			wantError: false,
+		},
+		{
+			name:      "generates an (n, n) sequence",
+			n:         4,
+			k:         4,
+			bits:      64,
+			wantError: false,
+		},
+		{
+			name:      "rejects threshold below 2",
+			n:         3,
+			k:         1,
+			bits:      32,
+			wantError: true,
+		},
+		{
+			name:      "rejects fewer shares than the threshold",
+			n:         2,
+			k:         3,
+			bits:      32,
+			wantError: true,
+		},
+		{
+			name:      "rejects tiny moduli",
+			n:         5,
+			k:         3,
+			bits:      8,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduli, err := sss.MignotteSequence(tt.n, tt.k, tt.bits)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("MignotteSequence() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(moduli) != tt.n {
+				t.Fatalf("MignotteSequence() returned %d moduli, want %d", len(moduli), tt.n)
+			}
+
+			alpha, beta := big.NewInt(1), big.NewInt(1)
+			for _, m := range moduli[:tt.k] {
+				alpha.Mul(alpha, m)
+			}
+			for _, m := range moduli[tt.n-tt.k+1:] {
+				beta.Mul(beta, m)
+			}
+			if alpha.Cmp(beta) <= 0 {
+				t.Errorf("MignotteSequence() alpha = %v, want greater than beta = %v", alpha, beta)
+			}
+		})
+	}
+}
+
+func TestSSS_SplitMignotteAndCombineCRT(t *testing.T) {
+	// the classic textbook (2, 3) sequence: alpha = 11*13 = 143, beta = 17
+	moduli := []*big.Int{big.NewInt(11), big.NewInt(13), big.NewInt(17)}
+
+	tests := []struct {
+		name      string
+		secret    *big.Int
+		moduli    []*big.Int
+		k         int
+		wantError bool
+	}{
+		{
+			name:      "shares a secret inside the bounds",
+			secret:    big.NewInt(123),
+			moduli:    moduli,
+			k:         2,
+			wantError: false,
+		},
+		{
+			name:      "shares the smallest allowed secret",
+			secret:    big.NewInt(18),
+			moduli:    moduli,
+			k:         2,
+			wantError: false,
+		},
+		{
+			name:      "rejects secret at the lower bound",
+			secret:    big.NewInt(17),
+			moduli:    moduli,
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects secret at the upper bound",
+			secret:    big.NewInt(143),
+			moduli:    moduli,
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects moduli that are not coprime",
+			secret:    big.NewInt(100),
+			moduli:    []*big.Int{big.NewInt(11), big.NewInt(13), big.NewInt(22)},
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects moduli that are not a Mignotte sequence",
+			secret:    big.NewInt(100),
+			moduli:    []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(101)},
+			k:         2,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitMignotte(tt.secret, tt.moduli, tt.k)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitMignotte() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+
+			for _, subset := range [][]sss.CRTShare{shares[:2], shares[1:], {shares[0], shares[2]}, shares} {
+				got, err := sss.CombineCRT(subset)
+				if err != nil {
+					t.Fatalf("CombineCRT() error = %v, want nil", err)
+				}
+				if got.Cmp(tt.secret) != 0 {
+					t.Errorf("CombineCRT() = %v, want %v", got, tt.secret)
+				}
+			}
+		})
+	}
+}
+
+func TestSSS_SplitAsmuthBloomAndCombineCRT(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     *big.Int
+		n          int
+		k          int
+		secretBits int
+		wantError  bool
+	}{
+		{
+			name:       "shares a small secret",
+			secret:     big.NewInt(42),
+			n:          5,
+			k:          3,
+			secretBits: 8,
+			wantError:  false,
+		},
+		{
+			name:       "shares a 256-bit secret",
+			secret:     new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+			n:          6,
+			k:          4,
+			secretBits: 256,
+			wantError:  false,
+		},
+		{
+			name:       "shares zero",
+			secret:     big.NewInt(0),
+			n:          3,
+			k:          2,
+			secretBits: 16,
+			wantError:  false,
+		},
+		{
+			name:       "rejects threshold below 2",
+			secret:     big.NewInt(1),
+			n:          3,
+			k:          1,
+			secretBits: 8,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m0, moduli, err := sss.AsmuthBloomSequence(tt.n, tt.k, tt.secretBits)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("AsmuthBloomSequence() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+
+			shares, err := sss.SplitAsmuthBloom(tt.secret, m0, moduli, tt.k)
+			if err != nil {
+				t.Fatalf("SplitAsmuthBloom() error = %v, want nil", err)
+			}
+
+			got, err := sss.CombineCRT(shares[tt.n-tt.k:])
+			if err != nil {
+				t.Fatalf("CombineCRT() error = %v, want nil", err)
+			}
+			if got.Cmp(tt.secret) != 0 {
+				t.Errorf("CombineCRT() = %v, want %v", got, tt.secret)
+			}
+		})
+	}
+}
+
+func TestSSS_CRTShare_Serialization(t *testing.T) {
+	// above every 16-bit modulus and below the product of any two, as Mignotte requires
+	secret := big.NewInt(1 << 20)
+
+	moduli, err := sss.MignotteSequence(3, 2, 16)
+	if err != nil {
+		t.Fatalf("MignotteSequence() error = %v, want nil", err)
+	}
+	mignotte, err := sss.SplitMignotte(secret, moduli, 2)
+	if err != nil {
+		t.Fatalf("SplitMignotte() error = %v, want nil", err)
+	}
+
+	m0, moduli, err := sss.AsmuthBloomSequence(3, 2, 21)
+	if err != nil {
+		t.Fatalf("AsmuthBloomSequence() error = %v, want nil", err)
+	}
+	asmuthBloom, err := sss.SplitAsmuthBloom(secret, m0, moduli, 2)
+	if err != nil {
+		t.Fatalf("SplitAsmuthBloom() error = %v, want nil", err)
+	}
+
+	for name, shares := range map[string][]sss.CRTShare{"Mignotte": mignotte, "Asmuth-Bloom": asmuthBloom} {
+		t.Run(name, func(t *testing.T) {
+			jsonData, err := json.Marshal(shares[0])
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v, want nil", err)
+			}
+			var fromJSON sss.CRTShare
+			if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+			}
+
+			bsonData, err := bson.Marshal(shares[1])
+			if err != nil {
+				t.Fatalf("bson.Marshal() error = %v, want nil", err)
+			}
+			var fromBSON sss.CRTShare
+			if err := bson.Unmarshal(bsonData, &fromBSON); err != nil {
+				t.Fatalf("bson.Unmarshal() error = %v, want nil", err)
+			}
+
+			got, err := sss.CombineCRT([]sss.CRTShare{fromJSON, fromBSON})
+			if err != nil {
+				t.Fatalf("CombineCRT() error = %v, want nil", err)
+			}
+			if got.Cmp(secret) != 0 {
+				t.Errorf("CombineCRT() = %v, want %v", got, secret)
+			}
+		})
+	}
+}
+
+func TestSSS_SplitAsmuthBloom_InvalidParams(t *testing.T) {
+	m0, moduli, err := sss.AsmuthBloomSequence(4, 3, 16)
+	if err != nil {
+		t.Fatalf("AsmuthBloomSequence() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		secret *big.Int
+		m0     *big.Int
+		moduli []*big.Int
+	}{
+		{
+			name:   "rejects secret not below m0",
+			secret: m0,
+			m0:     m0,
+			moduli: moduli,
+		},
+		{
+			name:   "rejects negative secret",
+			secret: big.NewInt(-1),
+			m0:     m0,
+			moduli: moduli,
+		},
+		{
+			name:   "rejects m0 sharing a factor with a modulus",
+			secret: big.NewInt(1),
+			m0:     moduli[0],
+			moduli: moduli,
+		},
+		{
+			name:   "rejects moduli too small for m0",
+			secret: big.NewInt(1),
+			m0:     big.NewInt(101),
+			moduli: []*big.Int{big.NewInt(103), big.NewInt(107), big.NewInt(109), big.NewInt(113)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.SplitAsmuthBloom(tt.secret, tt.m0, tt.moduli, 3); err == nil {
+				t.Error("SplitAsmuthBloom() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_CombineCRT_InvalidShares(t *testing.T) {
+	share := func(index int, modulus, residue int64) sss.CRTShare {
+		return sss.CRTShare{Index: index, Modulus: big.NewInt(modulus), Residue: big.NewInt(residue)}
+	}
+	withM0 := func(s sss.CRTShare) sss.CRTShare {
+		s.SecretModulus = big.NewInt(7)
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		shares []sss.CRTShare
+	}{
+		{
+			name:   "rejects a single share",
+			shares: []sss.CRTShare{share(1, 11, 2)},
+		},
+		{
+			name:   "rejects residue outside its modulus",
+			shares: []sss.CRTShare{share(1, 11, 11), share(2, 13, 2)},
+		},
+		{
+			name:   "rejects duplicate indices",
+			shares: []sss.CRTShare{share(1, 11, 2), share(1, 13, 2)},
+		},
+		{
+			name:   "rejects moduli that are not coprime",
+			shares: []sss.CRTShare{share(1, 11, 2), share(2, 22, 2)},
+		},
+		{
+			name:   "rejects mixing Mignotte and Asmuth-Bloom shares",
+			shares: []sss.CRTShare{share(1, 11, 2), withM0(share(2, 13, 2))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombineCRT(tt.shares); err == nil {
+				t.Error("CombineCRT() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_MarshalShareAndUnmarshalShare(t *testing.T) {
+	_, dealer, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v, want nil", err)
+	}
+
+	shares, err := sss.SplitWith(sss.ShamirGF256, []byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		dealer ed25519.PrivateKey
+	}{
+		{
+			name:   "round-trips an unsigned share",
+			dealer: nil,
+		},
+		{
+			name:   "round-trips a signed share",
+			dealer: dealer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelopes, err := sss.Seal(shares, tt.dealer)
+			if err != nil {
+				t.Fatalf("Seal() error = %v, want nil", err)
+			}
+
+			data, err := sss.MarshalShare(envelopes[0])
+			if err != nil {
+				t.Fatalf("MarshalShare() error = %v, want nil", err)
+			}
+			got, err := sss.UnmarshalShare(data)
+			if err != nil {
+				t.Fatalf("UnmarshalShare() error = %v, want nil", err)
+			}
+
+			want := envelopes[0]
+			if got.Group != want.Group || got.Share.Scheme != want.Share.Scheme ||
+				got.Share.Threshold != want.Share.Threshold || got.Share.Index != want.Share.Index ||
+				!bytes.Equal(got.Share.Payload, want.Share.Payload) || !bytes.Equal(got.Signature, want.Signature) {
+				t.Errorf("UnmarshalShare() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSSS_UnmarshalShare_Invalid(t *testing.T) {
+	shares, err := sss.SplitWith(sss.ShamirGF256, []byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+	envelopes, err := sss.Seal(shares, nil)
+	if err != nil {
+		t.Fatalf("Seal() error = %v, want nil", err)
+	}
+	valid, err := sss.MarshalShare(envelopes[0])
+	if err != nil {
+		t.Fatalf("MarshalShare() error = %v, want nil", err)
+	}
+
+	modified := func(f func([]byte) []byte) []byte {
+		return f(bytes.Clone(valid))
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "rejects empty input",
+			data: nil,
+		},
+		{
+			name: "rejects bad magic",
+			data: modified(func(b []byte) []byte { b[0] = 'X'; return b }),
+		},
+		{
+			name: "rejects a flipped payload bit",
+			data: modified(func(b []byte) []byte { b[len(b)-5] ^= 0x01; return b }),
+		},
+		{
+			name: "rejects a truncated share",
+			data: valid[:len(valid)-1],
+		},
+		{
+			name: "rejects trailing data",
+			data: append(bytes.Clone(valid), 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.UnmarshalShare(tt.data); err == nil {
+				t.Error("UnmarshalShare() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_CombineEnvelopes(t *testing.T) {
+	dealerPub, dealer, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v, want nil", err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v, want nil", err)
+	}
+
+	secret := []byte("enveloped secret")
+	split := func() []sss.Envelope {
+		shares, err := sss.SplitWith(sss.ShamirGF256, secret, 3, 2)
+		if err != nil {
+			t.Fatalf("SplitWith() error = %v, want nil", err)
+		}
+		envelopes, err := sss.Seal(shares, dealer)
+		if err != nil {
+			t.Fatalf("Seal() error = %v, want nil", err)
+		}
+		return envelopes
+	}
+	first, second := split(), split()
+
+	forged := first[1]
+	forged.Share.Payload = bytes.Clone(forged.Share.Payload)
+	forged.Share.Payload[0] ^= 0x01
+
+	unsigned := first[1]
+	unsigned.Signature = nil
+
+	tests := []struct {
+		name      string
+		envelopes []sss.Envelope
+		dealer    ed25519.PublicKey
+		wantErr   error
+		wantError bool
+	}{
+		{
+			name:      "combines signed shares of one split",
+			envelopes: first[:2],
+			dealer:    dealerPub,
+		},
+		{
+			name:      "combines without checking signatures when no dealer key is given",
+			envelopes: []sss.Envelope{first[0], unsigned},
+			dealer:    nil,
+		},
+		{
+			name:      "rejects shares from different splits",
+			envelopes: []sss.Envelope{first[0], second[1]},
+			dealer:    dealerPub,
+			wantErr:   sss.ErrGroupMismatch,
+			wantError: true,
+		},
+		{
+			name:      "rejects a share modified after signing",
+			envelopes: []sss.Envelope{first[0], forged},
+			dealer:    dealerPub,
+			wantErr:   sss.ErrInvalidSignature,
+			wantError: true,
+		},
+		{
+			name:      "rejects an unsigned share when a dealer key is given",
+			envelopes: []sss.Envelope{first[0], unsigned},
+			dealer:    dealerPub,
+			wantErr:   sss.ErrInvalidSignature,
+			wantError: true,
+		},
+		{
+			name:      "rejects shares signed by another dealer",
+			envelopes: first[:2],
+			dealer:    otherPub,
+			wantErr:   sss.ErrInvalidSignature,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.CombineEnvelopes(tt.envelopes, tt.dealer)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CombineEnvelopes() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CombineEnvelopes() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantError {
+				return
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("CombineEnvelopes() = %q, want %q", got, secret)
+			}
+		})
+	}
+}
+
+func FuzzSSS_UnmarshalShare(f *testing.F) {
+	_, dealer, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		f.Fatalf("GenerateKey() error = %v, want nil", err)
+	}
+	shares, err := sss.SplitWith(sss.ShamirPrime, []byte("fuzz"), 3, 2)
+	if err != nil {
+		f.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+	for _, key := range []ed25519.PrivateKey{nil, dealer} {
+		envelopes, err := sss.Seal(shares, key)
+		if err != nil {
+			f.Fatalf("Seal() error = %v, want nil", err)
+		}
+		data, err := sss.MarshalShare(envelopes[0])
+		if err != nil {
+			f.Fatalf("MarshalShare() error = %v, want nil", err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte("SSSH"))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		envelope, err := sss.UnmarshalShare(data)
+		if err != nil {
+			return
+		}
+
+		// anything that parses must re-encode to exactly the same bytes
+		again, err := sss.MarshalShare(*envelope)
+		if err != nil {
+			t.Fatalf("MarshalShare() error = %v, want nil for a parsed share", err)
+		}
+		if !bytes.Equal(again, data) {
+			t.Errorf("MarshalShare() = %x, want %x", again, data)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_SplitHierarchical(t *testing.T) {
+	p256, _ := sss.NamedPrime(sss.P256Order)
+
+	tests := []struct {
+		name      string
+		levels    []sss.Level
+		prime     *big.Int
+		wantError bool
+	}{
+		{
+			name:      "splits between directors and staff",
+			levels:    []sss.Level{{Holders: 2, Threshold: 1}, {Holders: 4, Threshold: 3}},
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "splits over three levels",
+			levels:    []sss.Level{{Holders: 2, Threshold: 1}, {Holders: 3, Threshold: 3}, {Holders: 5, Threshold: 5}},
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "rejects thresholds that do not increase",
+			levels:    []sss.Level{{Holders: 2, Threshold: 2}, {Holders: 4, Threshold: 2}},
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects an unreachable level threshold",
+			levels:    []sss.Level{{Holders: 1, Threshold: 2}, {Holders: 4, Threshold: 3}},
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects a single-holder threshold",
+			levels:    []sss.Level{{Holders: 3, Threshold: 1}},
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects an empty level",
+			levels:    []sss.Level{{Holders: 0, Threshold: 1}, {Holders: 4, Threshold: 3}},
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects composite modulus",
+			levels:    []sss.Level{{Holders: 2, Threshold: 1}, {Holders: 4, Threshold: 3}},
+			prime:     big.NewInt(7917),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitHierarchical(big.NewInt(42), tt.levels, tt.prime)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitHierarchical() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+
+			holders := 0
+			for _, level := range tt.levels {
+				holders += level.Holders
+			}
+			if len(shares) != holders {
+				t.Errorf("SplitHierarchical() returned %d shares, want %d", len(shares), holders)
+			}
+		})
+	}
+}
+
+func TestSSS_CombineHierarchical(t *testing.T) {
+	p256, _ := sss.NamedPrime(sss.P256Order)
+	secret := big.NewInt(987654321)
+
+	// any 3 holders, at least one of whom is a director
+	shares, err := sss.SplitHierarchical(secret, []sss.Level{{Holders: 2, Threshold: 1}, {Holders: 4, Threshold: 3}}, p256)
+	if err != nil {
+		t.Fatalf("SplitHierarchical() error = %v, want nil", err)
+	}
+	directors, staff := shares[:2], shares[2:]
+
+	tamperedPrime := shares[0]
+	tamperedPrime.Prime = big.NewInt(7919)
+
+	tests := []struct {
+		name          string
+		shares        []sss.HierarchicalShare
+		wantAccessErr bool
+		wantError     bool
+	}{
+		{
+			name:   "one director and two staff reconstruct",
+			shares: []sss.HierarchicalShare{directors[1], staff[0], staff[3]},
+		},
+		{
+			name:   "two directors and one staff reconstruct",
+			shares: []sss.HierarchicalShare{directors[0], directors[1], staff[2]},
+		},
+		{
+			name:   "everyone reconstructs",
+			shares: shares,
+		},
+		{
+			name:          "three staff without a director cannot reconstruct",
+			shares:        []sss.HierarchicalShare{staff[0], staff[1], staff[2]},
+			wantAccessErr: true,
+			wantError:     true,
+		},
+		{
+			name:          "two directors alone cannot reconstruct",
+			shares:        directors,
+			wantAccessErr: true,
+			wantError:     true,
+		},
+		{
+			name:      "rejects a share supplied twice",
+			shares:    []sss.HierarchicalShare{directors[0], directors[0], staff[0]},
+			wantError: true,
+		},
+		{
+			name:      "rejects shares over different primes",
+			shares:    []sss.HierarchicalShare{directors[1], staff[0], tamperedPrime},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.CombineHierarchical(tt.shares)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CombineHierarchical() error = %v, wantError %v", err, tt.wantError)
+			}
+			var accessErr *sss.AccessError
+			if errors.As(err, &accessErr) != tt.wantAccessErr {
+				t.Errorf("CombineHierarchical() error = %v, want access error %v", err, tt.wantAccessErr)
+			}
+			if tt.wantError {
+				return
+			}
+			if got.Cmp(secret) != 0 {
+				t.Errorf("CombineHierarchical() = %v, want %v", got, secret)
+			}
+		})
+	}
+}
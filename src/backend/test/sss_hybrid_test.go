@@ -0,0 +1,246 @@
+package test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_SplitHybridAndCombineHybrid(t *testing.T) {
+	large := make([]byte, 1<<20)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatalf("rand.Read() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		n         int
+		k         int
+		wantError bool
+	}{
+		{
+			name:      "shares a megabyte with fragments of a third of the size",
+			data:      large,
+			n:         5,
+			k:         3,
+			wantError: false,
+		},
+		{
+			name:      "shares a single byte",
+			data:      []byte{0x42},
+			n:         3,
+			k:         2,
+			wantError: false,
+		},
+		{
+			name:      "shares with n equal to k",
+			data:      []byte("all holders needed"),
+			n:         4,
+			k:         4,
+			wantError: false,
+		},
+		{
+			name:      "rejects empty data",
+			data:      nil,
+			n:         3,
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			data:      []byte("data"),
+			n:         3,
+			k:         1,
+			wantError: true,
+		},
+		{
+			name:      "rejects fewer shares than the threshold",
+			data:      []byte("data"),
+			n:         2,
+			k:         3,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitHybrid(tt.data, tt.n, tt.k)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitHybrid() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+
+			// GCM adds a 16-byte tag to the ciphertext
+			wantFragment := (len(tt.data) + 16 + tt.k - 1) / tt.k
+			for _, share := range shares {
+				if len(share.Fragment) != wantFragment {
+					t.Errorf("SplitHybrid() fragment length = %d, want %d", len(share.Fragment), wantFragment)
+				}
+				if len(share.Key.Y) != sss.HybridKeyLen {
+					t.Errorf("SplitHybrid() key share length = %d, want %d", len(share.Key.Y), sss.HybridKeyLen)
+				}
+			}
+
+			result, err := sss.CombineHybrid(shares[tt.n-tt.k:])
+			if err != nil {
+				t.Fatalf("CombineHybrid() error = %v, want nil", err)
+			}
+			if !bytes.Equal(result.Secret, tt.data) {
+				t.Errorf("CombineHybrid() secret differs from the original data")
+			}
+			if len(result.Corrupt) != 0 {
+				t.Errorf("CombineHybrid() corrupt = %v, want none", result.Corrupt)
+			}
+		})
+	}
+}
+
+func TestSSS_CombineHybrid_ReportsCorruptShares(t *testing.T) {
+	data := []byte("a backup large enough to be worth dispersing rather than sharing whole")
+
+	tests := []struct {
+		name        string
+		corrupt     func(shares []sss.HybridShare)
+		wantCorrupt []byte
+		wantError   error
+	}{
+		{
+			name: "reports a corrupt fragment",
+			corrupt: func(shares []sss.HybridShare) {
+				shares[1].Fragment[0] ^= 0x01
+			},
+			wantCorrupt: []byte{2},
+		},
+		{
+			name: "reports a corrupt fragment whose holder also rewrote the digests",
+			corrupt: func(shares []sss.HybridShare) {
+				shares[3].Fragment[2] ^= 0xff
+				digests := make([][]byte, len(shares[3].Digests))
+				copy(digests, shares[3].Digests)
+				digests[3] = make([]byte, len(digests[3]))
+				shares[3].Digests = digests
+			},
+			wantCorrupt: []byte{4},
+		},
+		{
+			name: "reports two corrupt fragments while k remain intact",
+			corrupt: func(shares []sss.HybridShare) {
+				shares[0].Fragment[0] ^= 0x01
+				shares[4].Fragment[1] ^= 0x01
+			},
+			wantCorrupt: []byte{1, 5},
+		},
+		{
+			name: "reports a corrupt key share",
+			corrupt: func(shares []sss.HybridShare) {
+				shares[2].Key.Y[0] ^= 0x01
+			},
+			wantCorrupt: []byte{3},
+		},
+		{
+			name: "fails when fewer than k fragments are intact",
+			corrupt: func(shares []sss.HybridShare) {
+				shares[0].Fragment[0] ^= 0x01
+				shares[1].Fragment[0] ^= 0x01
+				shares[2].Fragment[0] ^= 0x01
+			},
+			wantError: sss.ErrHybridDecrypt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitHybrid(data, 5, 3)
+			if err != nil {
+				t.Fatalf("SplitHybrid() error = %v, want nil", err)
+			}
+			for i := range shares {
+				shares[i].Fragment = append([]byte{}, shares[i].Fragment...)
+				shares[i].Key.Y = append([]byte{}, shares[i].Key.Y...)
+			}
+			tt.corrupt(shares)
+
+			result, err := sss.CombineHybrid(shares)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("CombineHybrid() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CombineHybrid() error = %v, want nil", err)
+			}
+			if !bytes.Equal(result.Secret, data) {
+				t.Errorf("CombineHybrid() = %q, want %q", result.Secret, data)
+			}
+			if !bytes.Equal(result.Corrupt, tt.wantCorrupt) {
+				t.Errorf("CombineHybrid() corrupt = %v, want %v", result.Corrupt, tt.wantCorrupt)
+			}
+		})
+	}
+}
+
+func TestSSS_CombineHybrid_InvalidShares(t *testing.T) {
+	shares, err := sss.SplitHybrid([]byte("hybrid data"), 4, 3)
+	if err != nil {
+		t.Fatalf("SplitHybrid() error = %v, want nil", err)
+	}
+	other, err := sss.SplitHybrid([]byte("other data"), 4, 3)
+	if err != nil {
+		t.Fatalf("SplitHybrid() error = %v, want nil", err)
+	}
+
+	modified := func(i int, change func(share *sss.HybridShare)) []sss.HybridShare {
+		out := append([]sss.HybridShare{}, shares[:3]...)
+		change(&out[i])
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		shares []sss.HybridShare
+	}{
+		{
+			name:   "rejects empty share list",
+			shares: nil,
+		},
+		{
+			name:   "rejects fewer shares than the threshold",
+			shares: shares[:2],
+		},
+		{
+			name:   "rejects duplicate indices",
+			shares: []sss.HybridShare{shares[0], shares[0], shares[1]},
+		},
+		{
+			name:   "rejects shares from different splits",
+			shares: []sss.HybridShare{shares[0], shares[1], other[2]},
+		},
+		{
+			name:   "rejects mismatched thresholds",
+			shares: modified(1, func(share *sss.HybridShare) { share.Threshold = 2 }),
+		},
+		{
+			name:   "rejects mismatched ciphertext lengths",
+			shares: modified(2, func(share *sss.HybridShare) { share.Length++ }),
+		},
+		{
+			name:   "rejects short key share",
+			shares: modified(0, func(share *sss.HybridShare) { share.Key.Y = share.Key.Y[:16] }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombineHybrid(tt.shares); err == nil {
+				t.Errorf("CombineHybrid() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package test
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/ssss"
+)
+
+// interopVectors: shares produced by other tools. The Vault entries come from HashiCorp Vault's shamir package
+// (base64, as the Vault CLI prints unseal keys), the last of them being backend shares that Vault's Combine accepted;
+// the ssss entry is the example from the ssss-split manual page.
+//
+//go:embed interop_vectors.json
+var interopVectors []byte
+
+type interopVector struct {
+	Name      string   `json:"name"`
+	Threshold int      `json:"threshold"`
+	Secret    string   `json:"secret"`
+	Shares    []string `json:"shares"`
+}
+
+func loadInteropVectors(t *testing.T) map[string][]interopVector {
+	t.Helper()
+
+	var vectors map[string][]interopVector
+	if err := json.Unmarshal(interopVectors, &vectors); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+	return vectors
+}
+
+func TestSSS_VaultVectors(t *testing.T) {
+	for _, vector := range loadInteropVectors(t)["vault"] {
+		t.Run(vector.Name, func(t *testing.T) {
+			want, _ := hex.DecodeString(vector.Secret)
+
+			shares := make([]sss.Share, len(vector.Shares))
+			for i, encoded := range vector.Shares {
+				data, err := base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					t.Fatalf("base64 decode error = %v, want nil", err)
+				}
+
+				shares[i], err = sss.ImportVaultShare(data)
+				if err != nil {
+					t.Fatalf("ImportVaultShare() error = %v, want nil", err)
+				}
+
+				exported, err := sss.ExportVaultShare(shares[i])
+				if err != nil {
+					t.Fatalf("ExportVaultShare() error = %v, want nil", err)
+				}
+				if !bytes.Equal(exported, data) {
+					t.Errorf("ExportVaultShare() = %x, want %x", exported, data)
+				}
+			}
+
+			got, err := sss.Combine(shares[len(shares)-vector.Threshold:])
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Combine() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSSS_VaultShare_Invalid(t *testing.T) {
+	importTests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "rejects empty share",
+			data: nil,
+		},
+		{
+			name: "rejects share without value bytes",
+			data: []byte{0x01},
+		},
+		{
+			name: "rejects X tag of 0",
+			data: []byte{0xaa, 0xbb, 0x00},
+		},
+	}
+
+	for _, tt := range importTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.ImportVaultShare(tt.data); err == nil {
+				t.Errorf("ImportVaultShare() error = nil, want error")
+			}
+		})
+	}
+
+	exportTests := []struct {
+		name  string
+		share sss.Share
+	}{
+		{
+			name:  "rejects X value of 0",
+			share: sss.Share{X: 0, Y: []byte{0x01}},
+		},
+		{
+			name:  "rejects empty Y value",
+			share: sss.Share{X: 1},
+		},
+	}
+
+	for _, tt := range exportTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.ExportVaultShare(tt.share); err == nil {
+				t.Errorf("ExportVaultShare() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSSS_Vectors(t *testing.T) {
+	for _, vector := range loadInteropVectors(t)["ssss"] {
+		t.Run(vector.Name, func(t *testing.T) {
+			want, _ := hex.DecodeString(vector.Secret)
+			k := vector.Threshold
+
+			for _, shares := range [][]string{vector.Shares[:k], vector.Shares[len(vector.Shares)-k:], vector.Shares} {
+				got, err := ssss.Combine(shares, k, ssss.Params{})
+				if err != nil {
+					t.Fatalf("Combine() error = %v, want nil", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("Combine() = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSSSS_SplitAndCombine(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    []byte
+		n         int
+		k         int
+		params    ssss.Params
+		wantWidth int
+		wantError bool
+	}{
+		{
+			name:      "shares a passphrase at the dynamic security level",
+			secret:    []byte("correct horse battery staple"),
+			n:         5,
+			k:         3,
+			wantWidth: 1,
+			wantError: false,
+		},
+		{
+			name:      "pads indices when there are ten or more shares",
+			secret:    []byte("twelve shares"),
+			n:         12,
+			k:         4,
+			wantWidth: 2,
+			wantError: false,
+		},
+		{
+			name:      "shares with a token and a fixed security level",
+			secret:    []byte("short"),
+			n:         3,
+			k:         2,
+			params:    ssss.Params{Token: "backup", SecurityLevel: 256},
+			wantWidth: 1,
+			wantError: false,
+		},
+		{
+			name:      "shares without the diffusion layer",
+			secret:    []byte("no diffusion here"),
+			n:         4,
+			k:         4,
+			params:    ssss.Params{NoDiffusion: true},
+			wantWidth: 1,
+			wantError: false,
+		},
+		{
+			name:      "shares a secret too small for diffusion",
+			secret:    []byte("pin"),
+			n:         3,
+			k:         2,
+			wantWidth: 1,
+			wantError: false,
+		},
+		{
+			name:      "shares at the maximum security level",
+			secret:    bytes.Repeat([]byte{0xa5}, ssss.MaxDegree/8),
+			n:         3,
+			k:         2,
+			wantWidth: 1,
+			wantError: false,
+		},
+		{
+			name:      "rejects secret longer than the security level",
+			secret:    []byte("too long for 64 bits"),
+			n:         3,
+			k:         2,
+			params:    ssss.Params{SecurityLevel: 64},
+			wantError: true,
+		},
+		{
+			name:      "rejects security level that is not a multiple of 8",
+			secret:    []byte("secret"),
+			n:         3,
+			k:         2,
+			params:    ssss.Params{SecurityLevel: 60},
+			wantError: true,
+		},
+		{
+			name:      "rejects token with a dash",
+			secret:    []byte("secret"),
+			n:         3,
+			k:         2,
+			params:    ssss.Params{Token: "a-b"},
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			secret:    []byte("secret"),
+			n:         3,
+			k:         1,
+			wantError: true,
+		},
+		{
+			name:      "rejects fewer shares than the threshold",
+			secret:    []byte("secret"),
+			n:         2,
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects empty secret",
+			secret:    nil,
+			n:         3,
+			k:         2,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := ssss.Split(tt.secret, tt.n, tt.k, tt.params)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Split() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("Split() returned %d shares, want %d", len(shares), tt.n)
+			}
+
+			for _, share := range shares {
+				index := strings.TrimPrefix(share, tt.params.Token+"-")
+				if tt.params.Token == "" {
+					index = share
+				}
+				if dash := strings.IndexByte(index, '-'); dash != tt.wantWidth {
+					t.Errorf("Split() share %q has index width %d, want %d", share, dash, tt.wantWidth)
+				}
+			}
+
+			got, err := ssss.Combine(shares[tt.n-tt.k:], tt.k, tt.params)
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			want := make([]byte, len(got)-len(tt.secret), len(got))
+			want = append(want, tt.secret...)
+			if !bytes.Equal(got, want) {
+				t.Errorf("Combine() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestSSSS_Combine_Invalid(t *testing.T) {
+	shares, err := ssss.Split([]byte("combine me"), 4, 3, ssss.Params{})
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	other, err := ssss.Split([]byte("a different length"), 4, 3, ssss.Params{})
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	tampered := shares[3][:len(shares[3])-1] + "0"
+	if tampered == shares[3] {
+		tampered = shares[3][:len(shares[3])-1] + "1"
+	}
+
+	tests := []struct {
+		name   string
+		shares []string
+		k      int
+		params ssss.Params
+	}{
+		{
+			name:   "rejects fewer shares than the threshold",
+			shares: shares[:2],
+			k:      3,
+		},
+		{
+			name:   "rejects duplicate indices",
+			shares: []string{shares[0], shares[0], shares[1]},
+			k:      3,
+		},
+		{
+			name:   "rejects shares of different security levels",
+			shares: []string{shares[0], shares[1], other[2]},
+			k:      3,
+		},
+		{
+			name:   "rejects share inconsistent with the others",
+			shares: []string{shares[0], shares[1], shares[2], tampered},
+			k:      3,
+		},
+		{
+			name:   "rejects token mismatch",
+			shares: shares[:3],
+			k:      3,
+			params: ssss.Params{Token: "expected"},
+		},
+		{
+			name:   "rejects security level mismatch",
+			shares: shares[:3],
+			k:      3,
+			params: ssss.Params{SecurityLevel: 128},
+		},
+		{
+			name:   "rejects malformed share",
+			shares: []string{shares[0], shares[1], "3-not-hex"},
+			k:      3,
+		},
+		{
+			name:   "rejects share without index",
+			shares: []string{shares[0], shares[1], "abcdef0123456789"},
+			k:      3,
+		},
+		{
+			name:   "rejects value of invalid length",
+			shares: []string{shares[0], shares[1], "3-abc"},
+			k:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ssss.Combine(tt.shares, tt.k, tt.params); err == nil {
+				t.Errorf("Combine() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"bytes"
+	mrand "math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// pick: selects k distinct elements of shares in random order.
+func pick[S any](shares []S, k int) []S {
+	out := make([]S, k)
+	for i, j := range mrand.Perm(len(shares))[:k] {
+		out[i] = shares[j]
+	}
+	return out
+}
+
+func TestSSS_Split16AndCombine16(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		k    int
+	}{
+		{name: "small split", n: 5, k: 3},
+		{name: "more shares than GF(2^8) allows", n: 1000, k: 20},
+		{name: "every share needed", n: 300, k: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := randomBytes(t, 33)
+			shares, err := sss.Split16(secret, tt.n, tt.k)
+			if err != nil {
+				t.Fatalf("Split16() error = %v, want nil", err)
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("Split16() returned %d shares, want %d", len(shares), tt.n)
+			}
+
+			got, err := sss.Combine16(pick(shares, tt.k))
+			if err != nil {
+				t.Fatalf("Combine16() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("Combine16() = %x, want %x", got, secret)
+			}
+
+			if got, err := sss.Combine16(shares[:tt.k-1]); err == nil && bytes.Equal(got, secret) {
+				t.Errorf("Combine16() recovered the secret from fewer shares than the threshold")
+			}
+		})
+	}
+}
+
+func TestSSS_Split16_Invalid(t *testing.T) {
+	shares, err := sss.Split16([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split16() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "split rejects empty secret",
+			run:  func() error { _, err := sss.Split16(nil, 3, 2); return err },
+		},
+		{
+			name: "split rejects threshold below 2",
+			run:  func() error { _, err := sss.Split16([]byte("s"), 3, 1); return err },
+		},
+		{
+			name: "split rejects more than 65535 shares",
+			run:  func() error { _, err := sss.Split16([]byte("s"), sss.MaxShares16+1, 2); return err },
+		},
+		{
+			name: "combine rejects a single share",
+			run:  func() error { _, err := sss.Combine16(shares[:1]); return err },
+		},
+		{
+			name: "combine rejects duplicate X values",
+			run:  func() error { _, err := sss.Combine16([]sss.Share16{shares[0], shares[0]}); return err },
+		},
+		{
+			name: "combine rejects shares of different lengths",
+			run: func() error {
+				_, err := sss.Combine16([]sss.Share16{shares[0], {X: 9, Y: []uint16{1}}})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err == nil {
+				t.Errorf("error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_SplitNTTAndCombineNTT(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		n       int
+		k       int
+		combine int
+	}{
+		{name: "small split", size: 32, n: 5, k: 3, combine: 3},
+		{name: "secret shorter than a limb", size: 3, n: 7, k: 2, combine: 2},
+		{name: "ten thousand holders", size: 32, n: 10_000, k: 5_000, combine: 5_000},
+		{name: "ten thousand holders, small threshold", size: 32, n: 10_000, k: 3, combine: 3},
+		{name: "every share of a full domain", size: 20, n: 4096, k: 4096, combine: 4096},
+		{name: "more shares than the threshold", size: 20, n: 1000, k: 100, combine: 700},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := randomBytes(t, tt.size)
+			shares, err := sss.SplitNTT(secret, tt.n, tt.k)
+			if err != nil {
+				t.Fatalf("SplitNTT() error = %v, want nil", err)
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("SplitNTT() returned %d shares, want %d", len(shares), tt.n)
+			}
+
+			got, err := sss.CombineNTT(pick(shares, tt.combine))
+			if err != nil {
+				t.Fatalf("CombineNTT() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("CombineNTT() = %x, want %x", got, secret)
+			}
+
+			if got, err := sss.CombineNTT(pick(shares, tt.k-1)); err == nil && bytes.Equal(got, secret) {
+				t.Errorf("CombineNTT() recovered the secret from fewer shares than the threshold")
+			}
+		})
+	}
+}
+
+func TestSSS_CombineNTT_Invalid(t *testing.T) {
+	shares, err := sss.SplitNTT([]byte("a secret of two limbs"), 5, 3)
+	if err != nil {
+		t.Fatalf("SplitNTT() error = %v, want nil", err)
+	}
+	other, err := sss.SplitNTT([]byte("short"), 5, 3)
+	if err != nil {
+		t.Fatalf("SplitNTT() error = %v, want nil", err)
+	}
+
+	modified := func(share sss.NTTShare, edit func(*sss.NTTShare)) sss.NTTShare {
+		share.Y = append([]byte{}, share.Y...)
+		edit(&share)
+		return share
+	}
+
+	tests := []struct {
+		name   string
+		shares []sss.NTTShare
+	}{
+		{name: "rejects a single share", shares: shares[:1]},
+		{name: "rejects duplicate indices", shares: []sss.NTTShare{shares[0], shares[0], shares[1]}},
+		{name: "rejects shares of different splits", shares: []sss.NTTShare{shares[0], shares[1], other[2]}},
+		{
+			name:   "rejects an index outside the domain",
+			shares: []sss.NTTShare{shares[0], shares[1], modified(shares[2], func(s *sss.NTTShare) { s.Index = s.Domain + 1 })},
+		},
+		{
+			name:   "rejects a domain that is not a power of two",
+			shares: []sss.NTTShare{modified(shares[0], func(s *sss.NTTShare) { s.Domain = 6 }), shares[1]},
+		},
+		{
+			name:   "rejects a value outside the field",
+			shares: []sss.NTTShare{shares[0], shares[1], modified(shares[2], func(s *sss.NTTShare) { copy(s.Y, bytes.Repeat([]byte{0xff}, 8)) })},
+		},
+		{
+			name:   "rejects a truncated value",
+			shares: []sss.NTTShare{shares[0], shares[1], modified(shares[2], func(s *sss.NTTShare) { s.Y = s.Y[:8] })},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombineNTT(tt.shares); err == nil {
+				t.Errorf("CombineNTT() error = nil, want error")
+			}
+		})
+	}
+
+	if _, err := sss.SplitNTT([]byte("s"), sss.MaxNTTShares+1, 2); err == nil {
+		t.Errorf("SplitNTT() error = nil, want error for more than %d shares", sss.MaxNTTShares)
+	}
+}
+
+// The benchmarks compare the quadratic GF(2^16) implementation with the NTT one as the number of holders grows,
+// with a threshold of half the holders, e.g. go test ./test -run '^$' -bench 'Large' -benchmem
+// The quadratic split of ten thousand shares takes tens of seconds, against milliseconds with the NTT.
+
+var largeSplitSizes = []int{1000, 10_000}
+
+func BenchmarkSSS_LargeSplit(b *testing.B) {
+	secret := randomBytes(b, 16)
+	for _, n := range largeSplitSizes {
+		b.Run("gf65536/n="+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := sss.Split16(secret, n, n/2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("ntt/n="+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := sss.SplitNTT(secret, n, n/2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSSS_LargeCombine(b *testing.B) {
+	secret := randomBytes(b, 16)
+	for _, n := range largeSplitSizes {
+		shares16, err := sss.Split16(secret, n, n/2)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run("gf65536/n="+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := sss.Combine16(shares16[:n/2]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		sharesNTT, err := sss.SplitNTT(secret, n, n/2)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run("ntt/n="+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := sss.CombineNTT(sharesNTT[:n/2]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
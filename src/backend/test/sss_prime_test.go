@@ -0,0 +1,296 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_NamedPrime(t *testing.T) {
+	tests := []struct {
+		name      string
+		prime     string
+		wantBits  int
+		wantError bool
+	}{
+		{
+			name:      "returns Mersenne prime 2^127-1",
+			prime:     sss.Mersenne127,
+			wantBits:  127,
+			wantError: false,
+		},
+		{
+			name:      "returns Mersenne prime 2^521-1",
+			prime:     sss.Mersenne521,
+			wantBits:  521,
+			wantError: false,
+		},
+		{
+			name:      "returns P-256 group order",
+			prime:     sss.P256Order,
+			wantBits:  256,
+			wantError: false,
+		},
+		{
+			name:      "rejects unknown prime",
+			prime:     "unknown",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := sss.NamedPrime(tt.prime)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("NamedPrime() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if p.BitLen() != tt.wantBits {
+				t.Errorf("NamedPrime() bit length = %d, want %d", p.BitLen(), tt.wantBits)
+			}
+			if !p.ProbablyPrime(20) {
+				t.Errorf("NamedPrime() = %v, want a prime", p)
+			}
+		})
+	}
+}
+
+func TestSSS_SplitPrimeAndCombinePrime(t *testing.T) {
+	m521, _ := sss.NamedPrime(sss.Mersenne521)
+	p256, _ := sss.NamedPrime(sss.P256Order)
+
+	tests := []struct {
+		name      string
+		secret    *big.Int
+		n         int
+		k         int
+		prime     *big.Int
+		wantError bool
+	}{
+		{
+			name:      "shares a large secret over 2^521-1",
+			secret:    new(big.Int).Sub(m521, big.NewInt(1)),
+			n:         5,
+			k:         3,
+			prime:     m521,
+			wantError: false,
+		},
+		{
+			name:      "shares a scalar over the P-256 order",
+			secret:    big.NewInt(123456789),
+			n:         4,
+			k:         4,
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "shares a PIN over a caller-supplied prime",
+			secret:    big.NewInt(1234),
+			n:         3,
+			k:         2,
+			prime:     big.NewInt(7919),
+			wantError: false,
+		},
+		{
+			name:      "shares zero",
+			secret:    big.NewInt(0),
+			n:         3,
+			k:         2,
+			prime:     p256,
+			wantError: false,
+		},
+		{
+			name:      "rejects composite modulus",
+			secret:    big.NewInt(5),
+			n:         3,
+			k:         2,
+			prime:     big.NewInt(7917),
+			wantError: true,
+		},
+		{
+			name:      "rejects secret outside the field",
+			secret:    big.NewInt(7919),
+			n:         3,
+			k:         2,
+			prime:     big.NewInt(7919),
+			wantError: true,
+		},
+		{
+			name:      "rejects negative secret",
+			secret:    big.NewInt(-1),
+			n:         3,
+			k:         2,
+			prime:     p256,
+			wantError: true,
+		},
+		{
+			name:      "rejects more shares than field elements",
+			secret:    big.NewInt(1),
+			n:         11,
+			k:         2,
+			prime:     big.NewInt(11),
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			secret:    big.NewInt(1),
+			n:         3,
+			k:         1,
+			prime:     p256,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.SplitPrime(tt.secret, tt.n, tt.k, tt.prime)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitPrime() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("SplitPrime() returned %d shares, want %d", len(shares), tt.n)
+			}
+
+			got, err := sss.CombinePrime(shares[len(shares)-tt.k:])
+			if err != nil {
+				t.Fatalf("CombinePrime() error = %v, want nil", err)
+			}
+			if got.Cmp(tt.secret) != 0 {
+				t.Errorf("CombinePrime() = %v, want %v", got, tt.secret)
+			}
+		})
+	}
+}
+
+func TestSSS_CombinePrime_InvalidShares(t *testing.T) {
+	p := big.NewInt(7919)
+	q := big.NewInt(7907)
+
+	tests := []struct {
+		name   string
+		shares []sss.PrimeShare
+	}{
+		{
+			name:   "rejects a single share",
+			shares: []sss.PrimeShare{{Prime: p, X: 1, Y: big.NewInt(1)}},
+		},
+		{
+			name: "rejects shares over different primes",
+			shares: []sss.PrimeShare{
+				{Prime: p, X: 1, Y: big.NewInt(1)},
+				{Prime: q, X: 2, Y: big.NewInt(2)},
+			},
+		},
+		{
+			name: "rejects duplicate X values",
+			shares: []sss.PrimeShare{
+				{Prime: p, X: 1, Y: big.NewInt(1)},
+				{Prime: p, X: 1, Y: big.NewInt(2)},
+			},
+		},
+		{
+			name: "rejects X value of 0",
+			shares: []sss.PrimeShare{
+				{Prime: p, X: 0, Y: big.NewInt(1)},
+				{Prime: p, X: 1, Y: big.NewInt(2)},
+			},
+		},
+		{
+			name: "rejects value outside the field",
+			shares: []sss.PrimeShare{
+				{Prime: p, X: 1, Y: big.NewInt(7919)},
+				{Prime: p, X: 2, Y: big.NewInt(2)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombinePrime(tt.shares); err == nil {
+				t.Errorf("CombinePrime() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_PrimeShare_Serialization(t *testing.T) {
+	p256, _ := sss.NamedPrime(sss.P256Order)
+	secret := big.NewInt(987654321)
+
+	shares, err := sss.SplitPrime(secret, 3, 2, p256)
+	if err != nil {
+		t.Fatalf("SplitPrime() error = %v, want nil", err)
+	}
+
+	jsonData, err := json.Marshal(shares[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v, want nil", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+	if fields["field"] != sss.P256Order {
+		t.Errorf("serialized field = %v, want %v", fields["field"], sss.P256Order)
+	}
+
+	var fromJSON sss.PrimeShare
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+
+	bsonData, err := bson.Marshal(shares[1])
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v, want nil", err)
+	}
+	var fromBSON sss.PrimeShare
+	if err := bson.Unmarshal(bsonData, &fromBSON); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v, want nil", err)
+	}
+
+	got, err := sss.CombinePrime([]sss.PrimeShare{fromJSON, fromBSON})
+	if err != nil {
+		t.Fatalf("CombinePrime() error = %v, want nil", err)
+	}
+	if got.Cmp(secret) != 0 {
+		t.Errorf("CombinePrime() = %v, want %v", got, secret)
+	}
+}
+
+func TestSSS_PrimeShare_UnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "rejects malformed prime",
+			data: `{"field":"custom","prime":"zz","x":1,"y":"1"}`,
+		},
+		{
+			name: "rejects field name not matching the prime",
+			data: `{"field":"p256-order","prime":"1eef","x":1,"y":"1"}`,
+		},
+		{
+			name: "rejects value outside the field",
+			data: `{"field":"custom","prime":"1eef","x":1,"y":"ffff"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var share sss.PrimeShare
+			if err := json.Unmarshal([]byte(tt.data), &share); err == nil {
+				t.Errorf("json.Unmarshal() error = nil, want error")
+			}
+		})
+	}
+}
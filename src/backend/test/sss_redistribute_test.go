@@ -0,0 +1,400 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/api/sharing"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// holderKeys generates an X25519 key pair for each of n new holders.
+func holderKeys(t *testing.T, n int) []*ecdh.PrivateKey {
+	t.Helper()
+	keys := make([]*ecdh.PrivateKey, n)
+	for i := range keys {
+		key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v, want nil", err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestSSS_Redistribute(t *testing.T) {
+	secret := []byte("team secret")
+
+	shares, err := sss.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		oldShares []sss.Share
+		newN      int
+		newK      int
+	}{
+		{
+			name:      "grows the holder set from (5,3) to (7,4)",
+			oldShares: shares[:3],
+			newN:      7,
+			newK:      4,
+		},
+		{
+			name:      "shrinks the holder set from (5,3) to (3,2)",
+			oldShares: []sss.Share{shares[4], shares[1], shares[3]},
+			newN:      3,
+			newK:      2,
+		},
+		{
+			name:      "redistributes using more than k old shares",
+			oldShares: shares,
+			newN:      4,
+			newK:      4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newShares, err := sss.Redistribute(tt.oldShares, tt.newN, tt.newK)
+			if err != nil {
+				t.Fatalf("Redistribute() error = %v, want nil", err)
+			}
+			if len(newShares) != tt.newN {
+				t.Fatalf("Redistribute() returned %d shares, want %d", len(newShares), tt.newN)
+			}
+
+			got, err := sss.Combine(newShares[tt.newN-tt.newK:])
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("Combine() = %q, want %q", got, secret)
+			}
+
+			if tt.newK > 2 {
+				got, err := sss.Combine(newShares[:tt.newK-1])
+				if err != nil {
+					t.Fatalf("Combine() error = %v, want nil", err)
+				}
+				if bytes.Equal(got, secret) {
+					t.Errorf("Combine() recovered the secret from fewer than the new threshold")
+				}
+			}
+
+			mixed := []sss.Share{shares[0], newShares[1], newShares[2]}
+			if _, err := sss.Combine(mixed); err == nil {
+				t.Errorf("Combine() error = nil, want error when mixing old and new shares")
+			}
+		})
+	}
+}
+
+func TestSSS_Redistribute_InvalidParams(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name string
+		newN int
+		newK int
+	}{
+		{
+			name: "rejects new threshold below 2",
+			newN: 3,
+			newK: 1,
+		},
+		{
+			name: "rejects new n less than new k",
+			newN: 2,
+			newK: 3,
+		},
+		{
+			name: "rejects negative new n",
+			newN: -1,
+			newK: 2,
+		},
+		{
+			name: "rejects more than 255 new shares",
+			newN: 256,
+			newK: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.Redistribute(shares, tt.newN, tt.newK); err == nil {
+				t.Errorf("Redistribute() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_CombineSubShares(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	fromFirst, err := sss.Reshare(shares[0], 3, 2)
+	if err != nil {
+		t.Fatalf("Reshare() error = %v, want nil", err)
+	}
+	fromSecond, err := sss.Reshare(shares[1], 3, 2)
+	if err != nil {
+		t.Fatalf("Reshare() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		subShares []sss.SubShare
+		wantError bool
+	}{
+		{
+			name:      "combines sub-shares addressed to the same holder",
+			subShares: []sss.SubShare{fromFirst[0], fromSecond[0]},
+			wantError: false,
+		},
+		{
+			name:      "rejects a single sub-share",
+			subShares: fromFirst[:1],
+			wantError: true,
+		},
+		{
+			name:      "rejects sub-shares addressed to different holders",
+			subShares: []sss.SubShare{fromFirst[0], fromSecond[1]},
+			wantError: true,
+		},
+		{
+			name:      "rejects two sub-shares from the same old holder",
+			subShares: []sss.SubShare{fromFirst[0], fromFirst[0]},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			share, err := sss.CombineSubShares(tt.subShares)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CombineSubShares() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && share.Epoch != shares[0].Epoch+1 {
+				t.Errorf("CombineSubShares() epoch = %d, want %d", share.Epoch, shares[0].Epoch+1)
+			}
+		})
+	}
+}
+
+func TestSSS_SealSubShare(t *testing.T) {
+	secret := []byte("relayed secret")
+	shares, err := sss.Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	keys := holderKeys(t, 4)
+
+	// every old holder seals one sub-share per new holder, and every new holder opens theirs
+	received := make([][]sss.SubShare, len(keys))
+	for _, share := range shares[:2] {
+		subShares, err := sss.Reshare(share, len(keys), 3)
+		if err != nil {
+			t.Fatalf("Reshare() error = %v, want nil", err)
+		}
+		for j, subShare := range subShares {
+			sealed, err := sss.SealSubShare(subShare, keys[j].PublicKey())
+			if err != nil {
+				t.Fatalf("SealSubShare() error = %v, want nil", err)
+			}
+			if bytes.Contains(sealed.Ciphertext, subShare.Share.Y) {
+				t.Fatalf("SealSubShare() ciphertext contains the sub-share")
+			}
+			opened, err := sss.OpenSubShare(sealed, keys[j])
+			if err != nil {
+				t.Fatalf("OpenSubShare() error = %v, want nil", err)
+			}
+			received[j] = append(received[j], opened)
+		}
+	}
+
+	newShares := make([]sss.Share, len(keys))
+	for j := range keys {
+		if newShares[j], err = sss.CombineSubShares(received[j]); err != nil {
+			t.Fatalf("CombineSubShares() error = %v, want nil", err)
+		}
+	}
+	got, err := sss.Combine(newShares[1:])
+	if err != nil {
+		t.Fatalf("Combine() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Errorf("Combine() = %q, want %q", got, secret)
+	}
+}
+
+func TestSSS_OpenSubShare_Invalid(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	subShares, err := sss.Reshare(shares[0], 2, 2)
+	if err != nil {
+		t.Fatalf("Reshare() error = %v, want nil", err)
+	}
+	keys := holderKeys(t, 2)
+	sealed, err := sss.SealSubShare(subShares[0], keys[0].PublicKey())
+	if err != nil {
+		t.Fatalf("SealSubShare() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		key    *ecdh.PrivateKey
+		tamper func(sealed *sss.SealedSubShare)
+	}{
+		{
+			name:   "rejects another holder's key",
+			key:    keys[1],
+			tamper: func(sealed *sss.SealedSubShare) {},
+		},
+		{
+			name:   "rejects a redirected envelope",
+			key:    keys[0],
+			tamper: func(sealed *sss.SealedSubShare) { sealed.To = 2 },
+		},
+		{
+			name:   "rejects a modified ciphertext",
+			key:    keys[0],
+			tamper: func(sealed *sss.SealedSubShare) { sealed.Ciphertext[0] ^= 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope := sealed
+			envelope.Ciphertext = bytes.Clone(sealed.Ciphertext)
+			tt.tamper(&envelope)
+			if _, err := sss.OpenSubShare(envelope, tt.key); !errors.Is(err, sss.ErrSealedSubShare) {
+				t.Errorf("OpenSubShare() error = %v, want %v", err, sss.ErrSealedSubShare)
+			}
+		})
+	}
+}
+
+func TestSSS_RelayHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := sharing.NewSharingHandler()
+	reqCtx := logging.WithContext(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	serve := func(run func(ctx *gin.Context) error, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest("POST", "/redistribute", strings.NewReader(body)).WithContext(reqCtx)
+		_ = run(ctx)
+		return recorder
+	}
+
+	shares, err := sss.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	subShares, err := sss.Reshare(shares[0], 2, 2)
+	if err != nil {
+		t.Fatalf("Reshare() error = %v, want nil", err)
+	}
+	keys := holderKeys(t, 2)
+	sealed := make([]sss.SealedSubShare, len(subShares))
+	for j, subShare := range subShares {
+		if sealed[j], err = sss.SealSubShare(subShare, keys[j].PublicKey()); err != nil {
+			t.Fatalf("SealSubShare() error = %v, want nil", err)
+		}
+	}
+
+	body, _ := json.Marshal(types.RelaySubSharesRequest{Redistribution: "team-2026", SubShares: sealed})
+	if recorder := serve(handler.RelaySubShares, string(body)); recorder.Code != http.StatusOK {
+		t.Fatalf("RelaySubShares() status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+
+	fetch := func(to byte) []sss.SealedSubShare {
+		body, _ := json.Marshal(types.FetchSubSharesRequest{Redistribution: "team-2026", To: to})
+		recorder := serve(handler.FetchSubShares, string(body))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("FetchSubShares() status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+		}
+		var resp types.SealedSubSharesResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+		}
+		return resp.SubShares
+	}
+
+	got := fetch(2)
+	if len(got) != 1 || got[0].To != 2 {
+		t.Fatalf("FetchSubShares() = %+v, want the sub-share sealed to holder 2", got)
+	}
+	if _, err := sss.OpenSubShare(got[0], keys[1]); err != nil {
+		t.Errorf("OpenSubShare() of the relayed sub-share error = %v, want nil", err)
+	}
+	if again := fetch(2); len(again) != 0 {
+		t.Errorf("FetchSubShares() returned %d sub-shares twice, want the mailbox drained", len(again))
+	}
+
+	relayBody := func(count, ciphertextLen int) string {
+		subShares := make([]sss.SealedSubShare, count)
+		for i := range subShares {
+			subShares[i] = sss.SealedSubShare{From: 1, To: byte(i%255 + 1), Ephemeral: make([]byte, 32), Ciphertext: make([]byte, ciphertextLen)}
+		}
+		body, _ := json.Marshal(types.RelaySubSharesRequest{Redistribution: "limits", SubShares: subShares})
+		return string(body)
+	}
+
+	tests := []struct {
+		name       string
+		run        func(ctx *gin.Context) error
+		body       string
+		wantStatus int
+	}{
+		{name: "relay accepts the longest sub-share", run: handler.RelaySubShares, body: relayBody(1, constants.MAX_SECRET_LEN+sss.SealOverhead), wantStatus: http.StatusOK},
+		{name: "relay rejects X value 0", run: handler.RelaySubShares, body: `{"redistribution": "limits", "sub_shares": [{"from": 0, "to": 1}]}`, wantStatus: http.StatusBadRequest},
+		{name: "relay rejects a longer sub-share", run: handler.RelaySubShares, body: relayBody(1, constants.MAX_SECRET_LEN+sss.SealOverhead+1), wantStatus: http.StatusBadRequest},
+		{name: "relay rejects more sub-shares than holders", run: handler.RelaySubShares, body: relayBody(sss.MaxShares+1, 0), wantStatus: http.StatusBadRequest},
+		{
+			name:       "relay rejects an oversized body",
+			run:        handler.RelaySubShares,
+			body:       `{"redistribution": "` + strings.Repeat("a", int(constants.MAX_RELAY_BODY_BYTES)) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "fetch rejects an oversized body",
+			run:        handler.FetchSubShares,
+			body:       `{"redistribution": "` + strings.Repeat("a", int(constants.MAX_RELAY_BODY_BYTES)) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if recorder := serve(tt.run, tt.body); recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_Refresh(t *testing.T) {
+	secret := []byte("long-lived secret")
+
+	shares, err := sss.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	refreshed, err := sss.Refresh(shares, 3)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v, want nil", err)
+	}
+
+	for i, share := range refreshed {
+		if share.Epoch != 1 {
+			t.Errorf("Refresh() share %d epoch = %d, want 1", i, share.Epoch)
+		}
+		if share.X != shares[i].X {
+			t.Errorf("Refresh() share %d X = %d, want %d", i, share.X, shares[i].X)
+		}
+		if bytes.Equal(share.Y, shares[i].Y) {
+			t.Errorf("Refresh() share %d was not re-randomized", i)
+		}
+	}
+
+	twice, err := sss.Refresh(refreshed, 3)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		shares    []sss.Share
+		wantError bool
+	}{
+		{
+			name:      "combines shares of the first epoch",
+			shares:    refreshed[2:],
+			wantError: false,
+		},
+		{
+			name:      "combines shares of the second epoch",
+			shares:    []sss.Share{twice[0], twice[4], twice[2]},
+			wantError: false,
+		},
+		{
+			name:      "refuses to mix old and refreshed shares",
+			shares:    []sss.Share{shares[0], shares[1], refreshed[2]},
+			wantError: true,
+		},
+		{
+			name:      "refuses to mix refreshed epochs",
+			shares:    []sss.Share{refreshed[0], twice[1], twice[2]},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.Combine(tt.shares)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Combine() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && !bytes.Equal(got, secret) {
+				t.Errorf("Combine() = %q, want %q", got, secret)
+			}
+		})
+	}
+}
+
+func TestSSS_Refresh_OldSharesUseless(t *testing.T) {
+	secret := []byte("rotate me")
+
+	shares, err := sss.Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	refreshed, err := sss.Refresh(shares, 2)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v, want nil", err)
+	}
+
+	// an attacker relabelling an old share to the new epoch still gets garbage
+	stale := shares[0]
+	stale.Epoch = refreshed[1].Epoch
+
+	got, err := sss.Combine([]sss.Share{stale, refreshed[1]})
+	if err != nil {
+		t.Fatalf("Combine() error = %v, want nil", err)
+	}
+	if bytes.Equal(got, secret) {
+		t.Errorf("Combine() recovered the secret from an old share and a refreshed one")
+	}
+}
+
+func TestSSS_Refresh_ThresholdAboveHolders(t *testing.T) {
+	shares, err := sss.Split([]byte("keep me recoverable"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	if _, err := sss.Refresh(shares, len(shares)+1); err == nil {
+		t.Errorf("Refresh() error = nil, want error")
+	}
+}
+
+func TestSSS_ApplyRefresh(t *testing.T) {
+	share := sss.Share{X: 1, Y: []byte{1, 2, 3}, Epoch: 4}
+
+	contribution, err := sss.RefreshContribution([]byte{1, 2, 3}, 4, 2, 3)
+	if err != nil {
+		t.Fatalf("RefreshContribution() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name          string
+		contributions []sss.Share
+		wantError     bool
+	}{
+		{
+			name:          "applies contribution addressed to the holder",
+			contributions: contribution[:1],
+			wantError:     false,
+		},
+		{
+			name:          "rejects missing contributions",
+			contributions: nil,
+			wantError:     true,
+		},
+		{
+			name:          "rejects contribution addressed to another holder",
+			contributions: contribution[1:2],
+			wantError:     true,
+		},
+		{
+			name:          "rejects contribution for another epoch",
+			contributions: []sss.Share{{X: 1, Y: []byte{1, 2, 3}, Epoch: 3}},
+			wantError:     true,
+		},
+		{
+			name:          "rejects contribution of another length",
+			contributions: []sss.Share{{X: 1, Y: []byte{1}, Epoch: 4}},
+			wantError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.ApplyRefresh(share, tt.contributions)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ApplyRefresh() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && got.Epoch != share.Epoch+1 {
+				t.Errorf("ApplyRefresh() epoch = %d, want %d", got.Epoch, share.Epoch+1)
+			}
+		})
+	}
+}
+
+func TestSSS_RefreshContribution_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs     []byte
+		k      int
+		length int
+	}{
+		{
+			name:   "rejects threshold below 2",
+			xs:     []byte{1, 2},
+			k:      1,
+			length: 4,
+		},
+		{
+			name:   "rejects threshold above the number of holders",
+			xs:     []byte{1, 2},
+			k:      3,
+			length: 4,
+		},
+		{
+			name:   "rejects empty shares",
+			xs:     []byte{1, 2},
+			k:      2,
+			length: 0,
+		},
+		{
+			name:   "rejects X value of 0",
+			xs:     []byte{0, 1},
+			k:      2,
+			length: 4,
+		},
+		{
+			name:   "rejects duplicate X values",
+			xs:     []byte{1, 1},
+			k:      2,
+			length: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.RefreshContribution(tt.xs, 0, tt.k, tt.length); err == nil {
+				t.Errorf("RefreshContribution() error = nil, want error")
+			}
+		})
+	}
+}
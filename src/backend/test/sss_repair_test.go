@@ -0,0 +1,163 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_Repair(t *testing.T) {
+	secret := []byte("custodian secret")
+
+	shares, err := sss.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		helpers []sss.Share
+		target  byte
+		want    []byte // expected share value, nil for a fresh index
+	}{
+		{
+			name:    "repairs a lost share with k helpers",
+			helpers: []sss.Share{shares[0], shares[2], shares[4]},
+			target:  2,
+			want:    shares[1].Y,
+		},
+		{
+			name:    "repairs a lost share with more than k helpers",
+			helpers: []sss.Share{shares[0], shares[1], shares[2], shares[4]},
+			target:  4,
+			want:    shares[3].Y,
+		},
+		{
+			name:    "enrolls a new custodian at a fresh index",
+			helpers: shares[:3],
+			target:  42,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.Repair(tt.helpers, tt.target)
+			if err != nil {
+				t.Fatalf("Repair() error = %v, want nil", err)
+			}
+			if got.X != tt.target {
+				t.Errorf("Repair() X = %d, want %d", got.X, tt.target)
+			}
+			if tt.want != nil && !bytes.Equal(got.Y, tt.want) {
+				t.Errorf("Repair() Y = %x, want %x", got.Y, tt.want)
+			}
+
+			// the rebuilt share combines with the others
+			combined, err := sss.Combine([]sss.Share{got, tt.helpers[0], tt.helpers[1]})
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(combined, secret) {
+				t.Errorf("Combine() = %q, want %q", combined, secret)
+			}
+		})
+	}
+}
+
+func TestSSS_Repair_InvalidParams(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 4, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		helpers []sss.Share
+		target  byte
+	}{
+		{
+			name:    "rejects target X value of 0",
+			helpers: shares[:2],
+			target:  0,
+		},
+		{
+			name:    "rejects target among the helpers",
+			helpers: shares[:2],
+			target:  shares[0].X,
+		},
+		{
+			name:    "rejects a single helper",
+			helpers: shares[:1],
+			target:  9,
+		},
+		{
+			name:    "rejects helpers from different epochs",
+			helpers: []sss.Share{shares[0], {X: shares[1].X, Y: shares[1].Y, Epoch: 1}},
+			target:  9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.Repair(tt.helpers, tt.target); err == nil {
+				t.Errorf("Repair() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_Repair_Steps(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 4, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	helperXs := []byte{shares[0].X, shares[1].X, shares[2].X}
+	target := shares[3].X
+
+	pieces := make([][]sss.RepairPiece, len(helperXs))
+	for i := range helperXs {
+		pieces[i], err = sss.RepairContribute(shares[i], helperXs, target)
+		if err != nil {
+			t.Fatalf("RepairContribute() error = %v, want nil", err)
+		}
+		for _, piece := range pieces[i] {
+			if bytes.Equal(piece.Value, shares[i].Y) {
+				t.Errorf("RepairContribute() leaked the helper share in a piece")
+			}
+		}
+	}
+
+	if _, err := sss.RepairContribute(shares[3], helperXs, 9); err == nil {
+		t.Errorf("RepairContribute() error = nil, want error for a share outside the helper set")
+	}
+
+	toFirst := []sss.RepairPiece{pieces[0][0], pieces[1][0], pieces[2][0]}
+	if _, err := sss.RepairAggregate(helperXs[0], helperXs, target, toFirst[:2]); err == nil {
+		t.Errorf("RepairAggregate() error = nil, want error for a missing piece")
+	}
+	if _, err := sss.RepairAggregate(helperXs[0], helperXs, target, []sss.RepairPiece{pieces[0][0], pieces[1][1], pieces[2][0]}); err == nil {
+		t.Errorf("RepairAggregate() error = nil, want error for a misaddressed piece")
+	}
+	if _, err := sss.RepairAggregate(helperXs[0], helperXs, target, []sss.RepairPiece{pieces[0][0], pieces[0][0], pieces[2][0]}); err == nil {
+		t.Errorf("RepairAggregate() error = nil, want error for a duplicate piece")
+	}
+
+	sums := make([]sss.RepairPiece, len(helperXs))
+	for j, helperX := range helperXs {
+		received := []sss.RepairPiece{pieces[0][j], pieces[1][j], pieces[2][j]}
+		sums[j], err = sss.RepairAggregate(helperX, helperXs, target, received)
+		if err != nil {
+			t.Fatalf("RepairAggregate() error = %v, want nil", err)
+		}
+	}
+
+	got, err := sss.RepairRecover(target, helperXs, sums)
+	if err != nil {
+		t.Fatalf("RepairRecover() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got.Y, shares[3].Y) {
+		t.Errorf("RepairRecover() Y = %x, want %x", got.Y, shares[3].Y)
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	constants "github.com/culbec/CRYPTO-sss/src/backend/internal"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/api/sharing"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/logging"
+	"github.com/culbec/CRYPTO-sss/src/backend/internal/types"
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// corrupt: returns a copy of the shares where the shares at the given positions have every byte flipped.
+func corrupt(shares []sss.Share, positions ...int) []sss.Share {
+	out := make([]sss.Share, len(shares))
+	for i, share := range shares {
+		out[i] = sss.Share{X: share.X, Y: append([]byte{}, share.Y...), Epoch: share.Epoch}
+	}
+	for _, p := range positions {
+		for b := range out[p].Y {
+			out[p].Y[b] ^= byte(0x5a + b)
+		}
+	}
+	return out
+}
+
+func TestSSS_CombineRobust(t *testing.T) {
+	secret := []byte("do not trust the holders")
+
+	shares, err := sss.Split(secret, 9, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name         string
+		shares       []sss.Share
+		wantRejected []byte
+		wantErr      error
+	}{
+		{
+			name:         "reconstructs from honest shares",
+			shares:       shares,
+			wantRejected: []byte{},
+		},
+		{
+			name:         "reconstructs from exactly k honest shares",
+			shares:       shares[:3],
+			wantRejected: []byte{},
+		},
+		{
+			name:         "corrects one bad share out of five",
+			shares:       corrupt(shares[:5], 1),
+			wantRejected: []byte{2},
+		},
+		{
+			name:         "corrects three bad shares out of nine",
+			shares:       corrupt(shares, 0, 4, 8),
+			wantRejected: []byte{1, 5, 9},
+		},
+		{
+			name:    "detects a bad share it cannot correct",
+			shares:  corrupt(shares[:4], 2),
+			wantErr: sss.ErrInconsistentShares,
+		},
+		{
+			name:    "refuses to correct more than floor((m-k)/2) bad shares",
+			shares:  corrupt(shares[:7], 0, 1, 2),
+			wantErr: sss.ErrTooManyBadShares,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sss.CombineRobust(tt.shares, 3)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CombineRobust() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CombineRobust() error = %v, want nil", err)
+			}
+			if !bytes.Equal(result.Secret, secret) {
+				t.Errorf("CombineRobust() secret = %q, want %q", result.Secret, secret)
+			}
+			if !bytes.Equal(result.Rejected, tt.wantRejected) {
+				t.Errorf("CombineRobust() rejected = %v, want %v", result.Rejected, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestSSS_CombineRobust_SingleByteCorruption(t *testing.T) {
+	secret := []byte("one flipped byte")
+
+	shares, err := sss.Split(secret, 6, 2)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	shares[3].Y[7] ^= 0x01
+
+	result, err := sss.CombineRobust(shares, 2)
+	if err != nil {
+		t.Fatalf("CombineRobust() error = %v, want nil", err)
+	}
+	if !bytes.Equal(result.Secret, secret) {
+		t.Errorf("CombineRobust() secret = %q, want %q", result.Secret, secret)
+	}
+	if !bytes.Equal(result.Rejected, []byte{shares[3].X}) {
+		t.Errorf("CombineRobust() rejected = %v, want [%d]", result.Rejected, shares[3].X)
+	}
+}
+
+func TestSSS_CombineRobust_InvalidParams(t *testing.T) {
+	shares, err := sss.Split([]byte("secret"), 3, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		shares []sss.Share
+		k      int
+	}{
+		{
+			name:   "rejects threshold below 2",
+			shares: shares,
+			k:      1,
+		},
+		{
+			name:   "rejects fewer shares than the threshold",
+			shares: shares[:2],
+			k:      3,
+		},
+		{
+			name:   "rejects duplicate shares",
+			shares: []sss.Share{shares[0], shares[0], shares[1]},
+			k:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.CombineRobust(tt.shares, tt.k); err == nil {
+				t.Errorf("CombineRobust() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_ShamirGF256Scheme_CorrectsBadShares(t *testing.T) {
+	secret := []byte("scheme level correction")
+
+	shares, err := sss.SplitWith(sss.ShamirGF256, secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+	shares[0].Payload = bytes.Repeat([]byte{0xee}, len(secret))
+
+	if err := sss.VerifyShares(shares); err == nil {
+		t.Errorf("VerifyShares() error = nil, want error for a bad share")
+	}
+
+	got, err := sss.CombineShares(shares)
+	if err != nil {
+		t.Fatalf("CombineShares() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Errorf("CombineShares() = %q, want %q", got, secret)
+	}
+}
+
+func TestSSS_CombineHandler_Limits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := sharing.NewSharingHandler()
+	reqCtx := logging.WithContext(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	body := func(secretLen int) string {
+		shares, err := sss.Split(bytes.Repeat([]byte{'s'}, secretLen), 3, 2)
+		if err != nil {
+			t.Fatalf("Split() error = %v, want nil", err)
+		}
+		encoded, _ := json.Marshal(types.CombineRequest{Shares: shares, Threshold: 2})
+		return string(encoded)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "accepts shares of the maximum length", body: body(constants.MAX_SECRET_LEN), wantStatus: http.StatusOK},
+		{name: "rejects longer shares", body: body(constants.MAX_SECRET_LEN + 1), wantStatus: http.StatusBadRequest},
+		{
+			name:       "rejects an oversized body",
+			body:       `{"threshold": 2, "shares": [], "padding": "` + strings.Repeat("a", int(constants.MAX_COMBINE_BODY_BYTES)) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest("POST", "/combine", strings.NewReader(tt.body)).WithContext(reqCtx)
+			_ = handler.Combine(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Combine() status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
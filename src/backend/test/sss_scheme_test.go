@@ -0,0 +1,177 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_LookupScheme(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        sss.SchemeID
+		wantName  string
+		wantError bool
+	}{
+		{
+			name:      "looks up Shamir over GF(2^8)",
+			id:        sss.ShamirGF256,
+			wantName:  "shamir-gf256",
+			wantError: false,
+		},
+		{
+			name:      "looks up Shamir over a prime field",
+			id:        sss.ShamirPrime,
+			wantName:  "shamir-prime",
+			wantError: false,
+		},
+		{
+			name:      "looks up Shamir over GF(2^16)",
+			id:        sss.ShamirGF65536,
+			wantName:  "shamir-gf65536",
+			wantError: false,
+		},
+		{
+			name:      "rejects unknown scheme",
+			id:        sss.SchemeID(-1),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := sss.LookupScheme(tt.id)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("LookupScheme() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && scheme.Name() != tt.wantName {
+				t.Errorf("LookupScheme() name = %v, want %v", scheme.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestSSS_Schemes_SplitAndCombine(t *testing.T) {
+	secret := []byte("\x00\x00leading zeros survive")
+
+	for id, scheme := range sss.Schemes {
+		t.Run(scheme.Name(), func(t *testing.T) {
+			shares, err := sss.SplitWith(id, secret, 5, 3)
+			if err != nil {
+				t.Fatalf("SplitWith() error = %v, want nil", err)
+			}
+
+			for _, share := range shares {
+				if share.Scheme != id {
+					t.Errorf("SplitWith() share scheme = %v, want %v", share.Scheme, id)
+				}
+				if share.Threshold != 3 {
+					t.Errorf("SplitWith() share threshold = %v, want 3", share.Threshold)
+				}
+			}
+
+			if err := sss.VerifyShares(shares); err != nil {
+				t.Errorf("VerifyShares() error = %v, want nil", err)
+			}
+
+			got, err := sss.CombineShares([]sss.SchemeShare{shares[4], shares[1], shares[2]})
+			if err != nil {
+				t.Fatalf("CombineShares() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("CombineShares() = %q, want %q", got, secret)
+			}
+
+			if _, err := sss.CombineShares(shares[:2]); err == nil {
+				t.Errorf("CombineShares() error = nil, want error for fewer shares than the threshold")
+			}
+		})
+	}
+}
+
+func TestSSS_Schemes_Verify(t *testing.T) {
+	gf256Shares, err := sss.SplitWith(sss.ShamirGF256, []byte("secret"), 5, 3)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+	primeShares, err := sss.SplitWith(sss.ShamirPrime, []byte("secret"), 5, 3)
+	if err != nil {
+		t.Fatalf("SplitWith() error = %v, want nil", err)
+	}
+
+	tampered := func(shares []sss.SchemeShare) []sss.SchemeShare {
+		out := make([]sss.SchemeShare, len(shares))
+		copy(out, shares)
+		payload := append([]byte{}, out[4].Payload...)
+		payload[len(payload)-1] ^= 0x01
+		out[4].Payload = payload
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		shares    []sss.SchemeShare
+		wantError bool
+	}{
+		{
+			name:      "accepts consistent GF(2^8) shares",
+			shares:    gf256Shares,
+			wantError: false,
+		},
+		{
+			name:      "accepts consistent prime-field shares",
+			shares:    primeShares,
+			wantError: false,
+		},
+		{
+			name:      "detects tampered GF(2^8) share",
+			shares:    tampered(gf256Shares),
+			wantError: true,
+		},
+		{
+			name:      "detects tampered prime-field share",
+			shares:    tampered(primeShares),
+			wantError: true,
+		},
+		{
+			name:      "rejects shares from different schemes",
+			shares:    []sss.SchemeShare{gf256Shares[0], primeShares[1], gf256Shares[2]},
+			wantError: true,
+		},
+		{
+			name:      "rejects duplicate indices",
+			shares:    []sss.SchemeShare{gf256Shares[0], gf256Shares[0], gf256Shares[2]},
+			wantError: true,
+		},
+		{
+			name:      "rejects empty share list",
+			shares:    nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sss.VerifyShares(tt.shares)
+			if (err != nil) != tt.wantError {
+				t.Errorf("VerifyShares() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSSS_ShamirPrime_SecretTooLong(t *testing.T) {
+	scheme, err := sss.LookupScheme(sss.ShamirPrime)
+	if err != nil {
+		t.Fatalf("LookupScheme() error = %v, want nil", err)
+	}
+
+	maxLen := scheme.Params().MaxSecretLen
+	if _, err := scheme.Split(make([]byte, maxLen), 3, 2); err != nil {
+		t.Errorf("Split() error = %v, want nil for secret of maximum length", err)
+	}
+	if _, err := scheme.Split(make([]byte, maxLen+1), 3, 2); err == nil {
+		t.Errorf("Split() error = nil, want error for secret longer than %d bytes", maxLen)
+	}
+}
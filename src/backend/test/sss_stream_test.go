@@ -0,0 +1,301 @@
+package test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+// streamHeaderLen: size of a share stream header, see pkg/security/sss/stream.go.
+const streamHeaderLen = 4 + 3 + 4 + 16 + 32
+
+// splitStream: splits data into n share streams with threshold k.
+func splitStream(t *testing.T, data []byte, n, k int) [][]byte {
+	t.Helper()
+
+	buffers := make([]*bytes.Buffer, n)
+	writers := make([]io.Writer, n)
+	for i := range buffers {
+		buffers[i] = &bytes.Buffer{}
+		writers[i] = buffers[i]
+	}
+
+	splitter, err := sss.NewSplitter(bytes.NewReader(data), writers, k)
+	if err != nil {
+		t.Fatalf("NewSplitter() error = %v, want nil", err)
+	}
+	written, err := splitter.Split()
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+	if written != int64(len(data)) {
+		t.Fatalf("Split() = %d, want %d", written, len(data))
+	}
+
+	streams := make([][]byte, n)
+	for i, buffer := range buffers {
+		streams[i] = buffer.Bytes()
+	}
+	return streams
+}
+
+// streamRecords: cuts a share stream into its header and chunk records.
+func streamRecords(stream []byte) ([]byte, [][]byte) {
+	header, rest := stream[:streamHeaderLen], stream[streamHeaderLen:]
+	var records [][]byte
+	for len(rest) > 0 {
+		end := 13 + int(binary.BigEndian.Uint32(rest[9:]))
+		records = append(records, rest[:end])
+		rest = rest[end:]
+	}
+	return header, records
+}
+
+func readers(streams ...[]byte) []io.Reader {
+	out := make([]io.Reader, len(streams))
+	for i, stream := range streams {
+		out[i] = bytes.NewReader(stream)
+	}
+	return out
+}
+
+func TestSSS_StreamSplitAndCombine(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "streams empty input", size: 0},
+		{name: "streams a single byte", size: 1},
+		{name: "streams exactly one chunk", size: sss.StreamChunkSize},
+		{name: "streams several chunks and a partial one", size: 3*sss.StreamChunkSize + 1234},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]byte, tt.size)
+			if _, err := rand.Read(data); err != nil {
+				t.Fatalf("rand.Read() error = %v, want nil", err)
+			}
+
+			streams := splitStream(t, data, 5, 3)
+
+			got, err := io.ReadAll(sss.NewCombiner(readers(streams[4], streams[0], streams[2])))
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("Combiner returned %d bytes differing from the %d input bytes", len(got), len(data))
+			}
+		})
+	}
+}
+
+func TestSSS_StreamCombiner_SmallReads(t *testing.T) {
+	data := bytes.Repeat([]byte("chunked "), sss.StreamChunkSize/4)
+	streams := splitStream(t, data, 3, 2)
+
+	combined := sss.NewCombiner([]io.Reader{
+		iotest.OneByteReader(bytes.NewReader(streams[1])),
+		iotest.HalfReader(bytes.NewReader(streams[2])),
+	})
+	if err := iotest.TestReader(combined, data); err != nil {
+		t.Errorf("TestReader() error = %v, want nil", err)
+	}
+}
+
+func TestSSS_StreamCombiner_Errors(t *testing.T) {
+	data := make([]byte, 2*sss.StreamChunkSize+10)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("rand.Read() error = %v, want nil", err)
+	}
+	streams := splitStream(t, data, 4, 3)
+	other := splitStream(t, data, 4, 3)
+
+	header, records := streamRecords(streams[1])
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	tampered := bytes.Clone(streams[2])
+	tampered[len(tampered)-5] ^= 0x01
+
+	tests := []struct {
+		name       string
+		readers    []io.Reader
+		wantErr    error
+		wantReader int
+		wantChunk  uint64
+	}{
+		{
+			name:       "reports a stream truncated inside a record",
+			readers:    readers(streams[0], streams[1][:len(streams[1])-7], streams[2]),
+			wantErr:    sss.ErrStreamTruncated,
+			wantReader: 1,
+			wantChunk:  2,
+		},
+		{
+			name:       "reports a stream missing its final chunk",
+			readers:    readers(streams[0], join(header, records[0], records[1]), streams[2]),
+			wantErr:    sss.ErrStreamTruncated,
+			wantReader: 1,
+			wantChunk:  2,
+		},
+		{
+			name:       "reports a stream truncated inside its header",
+			readers:    readers(streams[0], streams[1], streams[2][:20]),
+			wantErr:    sss.ErrStreamTruncated,
+			wantReader: 2,
+			wantChunk:  0,
+		},
+		{
+			name:       "reports reordered chunks",
+			readers:    readers(streams[0], join(header, records[1], records[0], records[2]), streams[2]),
+			wantErr:    sss.ErrChunkOutOfOrder,
+			wantReader: 1,
+			wantChunk:  0,
+		},
+		{
+			name:       "reports a skipped chunk",
+			readers:    readers(streams[0], join(header, records[0], records[2]), streams[2]),
+			wantErr:    sss.ErrChunkOutOfOrder,
+			wantReader: 1,
+			wantChunk:  1,
+		},
+		{
+			name:       "reports a tampered chunk",
+			readers:    readers(streams[0], streams[1], tampered),
+			wantErr:    sss.ErrChunkAuthentication,
+			wantReader: -1,
+			wantChunk:  2,
+		},
+		{
+			name:       "reports streams from different splits",
+			readers:    readers(streams[0], other[1], streams[2]),
+			wantErr:    sss.ErrStreamMismatch,
+			wantReader: 1,
+			wantChunk:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := io.ReadAll(sss.NewCombiner(tt.readers))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadAll() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var streamErr *sss.StreamError
+			if !errors.As(err, &streamErr) {
+				t.Fatalf("ReadAll() error = %T, want *sss.StreamError", err)
+			}
+			if streamErr.Reader != tt.wantReader || streamErr.Chunk != tt.wantChunk {
+				t.Errorf("StreamError at reader %d, chunk %d, want reader %d, chunk %d",
+					streamErr.Reader, streamErr.Chunk, tt.wantReader, tt.wantChunk)
+			}
+		})
+	}
+}
+
+func TestSSS_StreamCombiner_InvalidStreams(t *testing.T) {
+	streams := splitStream(t, []byte("short stream"), 3, 3)
+
+	badMagic := bytes.Clone(streams[0])
+	badMagic[0] = 'X'
+
+	tests := []struct {
+		name    string
+		readers []io.Reader
+	}{
+		{
+			name:    "rejects no streams",
+			readers: nil,
+		},
+		{
+			name:    "rejects fewer streams than the threshold",
+			readers: readers(streams[0], streams[1]),
+		},
+		{
+			name:    "rejects bad magic",
+			readers: readers(badMagic, streams[1], streams[2]),
+		},
+		{
+			name:    "rejects the same stream twice",
+			readers: readers(streams[0], streams[0], streams[2]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := io.ReadAll(sss.NewCombiner(tt.readers)); err == nil {
+				t.Errorf("ReadAll() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_NewSplitter_Invalid(t *testing.T) {
+	writers := []io.Writer{io.Discard, io.Discard, io.Discard}
+
+	tests := []struct {
+		name    string
+		r       io.Reader
+		writers []io.Writer
+		k       int
+	}{
+		{
+			name:    "rejects nil reader",
+			r:       nil,
+			writers: writers,
+			k:       2,
+		},
+		{
+			name:    "rejects threshold below 2",
+			r:       bytes.NewReader(nil),
+			writers: writers,
+			k:       1,
+		},
+		{
+			name:    "rejects fewer writers than the threshold",
+			r:       bytes.NewReader(nil),
+			writers: writers[:1],
+			k:       2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.NewSplitter(tt.r, tt.writers, tt.k); err == nil {
+				t.Errorf("NewSplitter() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSSS_Splitter_PropagatesErrors(t *testing.T) {
+	readErr := errors.New("read failed")
+	splitter, err := sss.NewSplitter(iotest.ErrReader(readErr), []io.Writer{io.Discard, io.Discard}, 2)
+	if err != nil {
+		t.Fatalf("NewSplitter() error = %v, want nil", err)
+	}
+	if _, err := splitter.Split(); !errors.Is(err, readErr) {
+		t.Errorf("Split() error = %v, want %v", err, readErr)
+	}
+
+	splitter, err = sss.NewSplitter(bytes.NewReader([]byte("data")), []io.Writer{io.Discard, failingWriter{}}, 2)
+	if err != nil {
+		t.Fatalf("NewSplitter() error = %v, want nil", err)
+	}
+	if _, err := splitter.Split(); err == nil {
+		t.Errorf("Split() error = nil, want error from the failing writer")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
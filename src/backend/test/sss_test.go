@@ -0,0 +1,201 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_Split(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    []byte
+		n         int
+		k         int
+		wantError bool
+	}{
+		{
+			name:      "splits secret with 3-of-5 scheme",
+			secret:    []byte("top secret"),
+			n:         5,
+			k:         3,
+			wantError: false,
+		},
+		{
+			name:      "splits secret with n equal to k",
+			secret:    []byte("top secret"),
+			n:         4,
+			k:         4,
+			wantError: false,
+		},
+		{
+			name:      "splits secret with maximum number of shares",
+			secret:    []byte{0x00, 0xff},
+			n:         sss.MaxShares,
+			k:         2,
+			wantError: false,
+		},
+		{
+			name:      "rejects empty secret",
+			secret:    []byte{},
+			n:         5,
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			secret:    []byte("top secret"),
+			n:         5,
+			k:         1,
+			wantError: true,
+		},
+		{
+			name:      "rejects n less than k",
+			secret:    []byte("top secret"),
+			n:         2,
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects more than 255 shares",
+			secret:    []byte("top secret"),
+			n:         256,
+			k:         3,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := sss.Split(tt.secret, tt.n, tt.k)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Split() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Errorf("Split() returned %d shares, want %d", len(shares), tt.n)
+			}
+			for i, share := range shares {
+				if int(share.X) != i+1 {
+					t.Errorf("Split() share %d X = %d, want %d", i, share.X, i+1)
+				}
+				if len(share.Y) != len(tt.secret) {
+					t.Errorf("Split() share %d length = %d, want %d", i, len(share.Y), len(tt.secret))
+				}
+			}
+		})
+	}
+}
+
+func TestSSS_SplitAndCombine(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+
+	shares, err := sss.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		shares    []sss.Share
+		wantEqual bool
+	}{
+		{
+			name:      "combines first k shares",
+			shares:    shares[:3],
+			wantEqual: true,
+		},
+		{
+			name:      "combines last k shares",
+			shares:    shares[2:],
+			wantEqual: true,
+		},
+		{
+			name:      "combines non-contiguous shares",
+			shares:    []sss.Share{shares[4], shares[0], shares[2]},
+			wantEqual: true,
+		},
+		{
+			name:      "combines all shares",
+			shares:    shares,
+			wantEqual: true,
+		},
+		{
+			name:      "does not recover secret from k-1 shares",
+			shares:    shares[:2],
+			wantEqual: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.Combine(tt.shares)
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if bytes.Equal(got, secret) != tt.wantEqual {
+				t.Errorf("Combine() = %q, want equal to secret = %v", got, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestSSS_Combine_KnownAnswer(t *testing.T) {
+	// f(x) = 0x53 + 0xca*x over GF(2^8) with the AES polynomial
+	shares := []sss.Share{
+		{X: 1, Y: []byte{0x99}},
+		{X: 2, Y: []byte{0xdc}},
+	}
+
+	got, err := sss.Combine(shares)
+	if err != nil {
+		t.Fatalf("Combine() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, []byte{0x53}) {
+		t.Errorf("Combine() = %x, want 53", got)
+	}
+}
+
+func TestSSS_Combine_InvalidShares(t *testing.T) {
+	tests := []struct {
+		name   string
+		shares []sss.Share
+	}{
+		{
+			name:   "rejects nil shares",
+			shares: nil,
+		},
+		{
+			name:   "rejects a single share",
+			shares: []sss.Share{{X: 1, Y: []byte{1}}},
+		},
+		{
+			name:   "rejects share with X equal to 0",
+			shares: []sss.Share{{X: 0, Y: []byte{1}}, {X: 1, Y: []byte{2}}},
+		},
+		{
+			name:   "rejects duplicate X values",
+			shares: []sss.Share{{X: 1, Y: []byte{1}}, {X: 1, Y: []byte{2}}},
+		},
+		{
+			name:   "rejects shares with different lengths",
+			shares: []sss.Share{{X: 1, Y: []byte{1}}, {X: 2, Y: []byte{2, 3}}},
+		},
+		{
+			name:   "rejects empty share values",
+			shares: []sss.Share{{X: 1, Y: []byte{}}, {X: 2, Y: []byte{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sss.Combine(tt.shares); err == nil {
+				t.Errorf("Combine() error = nil, want error")
+			}
+		})
+	}
+}
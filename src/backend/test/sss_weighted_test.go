@@ -0,0 +1,148 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/sss"
+)
+
+func TestSSS_SplitWeighted(t *testing.T) {
+	tests := []struct {
+		name      string
+		weights   []int
+		k         int
+		wantError bool
+	}{
+		{
+			name:      "splits between a CTO of weight 2 and three engineers",
+			weights:   []int{2, 1, 1, 1},
+			k:         3,
+			wantError: false,
+		},
+		{
+			name:      "splits between equal holders",
+			weights:   []int{1, 1, 1},
+			k:         2,
+			wantError: false,
+		},
+		{
+			name:      "rejects zero weight",
+			weights:   []int{2, 0, 1},
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold above the total weight",
+			weights:   []int{1, 1},
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects total weight above 255",
+			weights:   []int{200, 100},
+			k:         2,
+			wantError: true,
+		},
+		{
+			name:      "rejects empty weight list",
+			weights:   nil,
+			k:         2,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holders, err := sss.SplitWeighted([]byte("secret"), tt.weights, tt.k)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitWeighted() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(holders) != len(tt.weights) {
+				t.Fatalf("SplitWeighted() returned %d holders, want %d", len(holders), len(tt.weights))
+			}
+			for i, holder := range holders {
+				if len(holder.Shares) != tt.weights[i] {
+					t.Errorf("SplitWeighted() holder %d has %d shares, want %d", i, len(holder.Shares), tt.weights[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSSS_SplitWeighted_IndependentHolders(t *testing.T) {
+	holders, err := sss.SplitWeighted([]byte("secret"), []int{2, 1}, 2)
+	if err != nil {
+		t.Fatalf("SplitWeighted() error = %v, want nil", err)
+	}
+
+	next := holders[1].Shares[0]
+	holders[0].Shares = append(holders[0].Shares, sss.Share{X: 99, Y: []byte("other!")})
+	if holders[1].Shares[0].X != next.X || !bytes.Equal(holders[1].Shares[0].Y, next.Y) {
+		t.Errorf("appending to holder 0 changed holder 1's share to %+v", holders[1].Shares[0])
+	}
+}
+
+func TestSSS_CombineWeighted(t *testing.T) {
+	secret := []byte("weighted secret")
+	holders, err := sss.SplitWeighted(secret, []int{2, 1, 1, 1}, 3)
+	if err != nil {
+		t.Fatalf("SplitWeighted() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name          string
+		holders       []sss.WeightedShare
+		wantAccessErr bool
+		wantError     bool
+	}{
+		{
+			name:    "CTO and one engineer reconstruct",
+			holders: []sss.WeightedShare{holders[0], holders[2]},
+		},
+		{
+			name:    "three engineers reconstruct",
+			holders: []sss.WeightedShare{holders[1], holders[2], holders[3]},
+		},
+		{
+			name:          "CTO alone cannot reconstruct",
+			holders:       []sss.WeightedShare{holders[0]},
+			wantAccessErr: true,
+			wantError:     true,
+		},
+		{
+			name:          "two engineers cannot reconstruct",
+			holders:       []sss.WeightedShare{holders[1], holders[3]},
+			wantAccessErr: true,
+			wantError:     true,
+		},
+		{
+			name:      "rejects a holder supplied twice",
+			holders:   []sss.WeightedShare{holders[1], holders[1], holders[2]},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sss.CombineWeighted(tt.holders)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CombineWeighted() error = %v, wantError %v", err, tt.wantError)
+			}
+			var accessErr *sss.AccessError
+			if errors.As(err, &accessErr) != tt.wantAccessErr {
+				t.Errorf("CombineWeighted() error = %v, want access error %v", err, tt.wantAccessErr)
+			}
+			if tt.wantError {
+				return
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("CombineWeighted() = %q, want %q", got, secret)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	stdstrings "strings"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/strings"
+)
+
+func TestStringsArmor(t *testing.T) {
+	data := []byte("binary share bytes \x00\x01\x02")
+	headers := map[string]string{"Scheme": "shamir-gf256"}
+
+	armored := strings.Armor(data, headers)
+	if !stdstrings.HasPrefix(armored, "-----BEGIN SSS SHARE-----\n") {
+		t.Fatalf("Armor() = %q, want SSS SHARE block", armored)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{
+			name:      "dearmors a block",
+			input:     armored,
+			wantError: false,
+		},
+		{
+			name:      "ignores surrounding whitespace",
+			input:     "\n  " + armored + "\n\n",
+			wantError: false,
+		},
+		{
+			name:      "rejects another block type",
+			input:     stdstrings.ReplaceAll(armored, "SSS SHARE", "CERTIFICATE"),
+			wantError: true,
+		},
+		{
+			name:      "rejects text after the block",
+			input:     armored + "trailing",
+			wantError: true,
+		},
+		{
+			name:      "rejects plain text",
+			input:     "not armored",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotHeaders, err := strings.Dearmor(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Dearmor() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("Dearmor() = %q, want %q", got, data)
+			}
+			if gotHeaders["Scheme"] != "shamir-gf256" {
+				t.Errorf("Dearmor() headers = %v, want %v", gotHeaders, headers)
+			}
+		})
+	}
+}
+
+func TestStringsBase58(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // hex
+		want string
+	}{
+		{
+			name: "encodes text",
+			data: hex.EncodeToString([]byte("Hello World!")),
+			want: "2NEpo7TZRRrLZSi2U",
+		},
+		{
+			name: "keeps leading zero bytes",
+			data: "0000287fb4cd",
+			want: "11233QC4",
+		},
+		{
+			name: "encodes empty input",
+			data: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.data)
+			if got := strings.Base58Encode(data); got != tt.want {
+				t.Errorf("Base58Encode() = %q, want %q", got, tt.want)
+			}
+			got, err := strings.Base58Decode(tt.want)
+			if err != nil {
+				t.Fatalf("Base58Decode() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("Base58Decode() = %x, want %x", got, data)
+			}
+		})
+	}
+}
+
+func TestStringsBase58Check(t *testing.T) {
+	// Bitcoin address of the hash160 from the Bitcoin wiki's Base58Check example
+	payload, _ := hex.DecodeString("010966776006953d5567439e5e39f86a0d273bee")
+	address := "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"
+
+	if got := strings.Base58CheckEncode(0x00, payload); got != address {
+		t.Errorf("Base58CheckEncode() = %q, want %q", got, address)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{
+			name:      "decodes a valid string",
+			input:     address,
+			wantError: false,
+		},
+		{
+			name:      "rejects a mistyped character",
+			input:     "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN",
+			wantError: true,
+		},
+		{
+			name:      "rejects characters outside the alphabet",
+			input:     "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjv0",
+			wantError: true,
+		},
+		{
+			name:      "rejects a string shorter than the checksum",
+			input:     "1",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, got, err := strings.Base58CheckDecode(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Base58CheckDecode() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if version != 0x00 || !bytes.Equal(got, payload) {
+				t.Errorf("Base58CheckDecode() = %d, %x, want 0, %x", version, got, payload)
+			}
+		})
+	}
+}
+
+func TestStringsBech32m(t *testing.T) {
+	tests := []struct {
+		name string
+		hrp  string
+		data []byte
+		want string
+	}{
+		{
+			name: "encodes the empty BIP-350 test vector",
+			hrp:  "a",
+			data: nil,
+			want: "a1lqfn3a",
+		},
+		{
+			name: "encodes text under the share prefix",
+			hrp:  strings.ShareHRP,
+			data: []byte("hello share"),
+			want: "sss1dpjkcmr0ypeksctjv58q007u",
+		},
+		{
+			name: "encodes a 32-byte share",
+			hrp:  strings.ShareHRP,
+			data: func() []byte {
+				b := make([]byte, 32)
+				for i := range b {
+					b[i] = byte(i)
+				}
+				return b
+			}(),
+			want: "sss1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0szyuduv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := strings.Bech32mEncode(tt.hrp, tt.data)
+			if err != nil {
+				t.Fatalf("Bech32mEncode() error = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("Bech32mEncode() = %q, want %q", got, tt.want)
+			}
+
+			hrp, data, err := strings.Bech32mDecode(stdstrings.ToUpper(got))
+			if err != nil {
+				t.Fatalf("Bech32mDecode() error = %v, want nil", err)
+			}
+			if hrp != tt.hrp || !bytes.Equal(data, tt.data) {
+				t.Errorf("Bech32mDecode() = %q, %x, want %q, %x", hrp, data, tt.hrp, tt.data)
+			}
+		})
+	}
+}
+
+func TestStringsBech32mDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "rejects a mistyped character",
+			input: "sss1dpjkcmr0ypeksctjv58q007v",
+		},
+		{
+			name:  "rejects mixed case",
+			input: "sss1Dpjkcmr0ypeksctjv58q007u",
+		},
+		{
+			name:  "rejects a missing separator",
+			input: "sssdpjkcmr0ypeksctjv58q007u",
+		},
+		{
+			name:  "rejects an empty human-readable part",
+			input: "1lqfn3a",
+		},
+		{
+			name:  "rejects characters outside the charset",
+			input: "sss1bpjkcmr0ypeksctjv58q007u",
+		},
+		{
+			name:  "rejects a Bech32 rather than Bech32m checksum",
+			input: "a12uel5l",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := strings.Bech32mDecode(tt.input); err == nil {
+				t.Error("Bech32mDecode() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestStringsDetect(t *testing.T) {
+	data := []byte("share")
+	bech, err := strings.Bech32mEncode(strings.ShareHRP, data)
+	if err != nil {
+		t.Fatalf("Bech32mEncode() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  strings.Encoding
+	}{
+		{
+			name:  "detects armor",
+			input: strings.Armor(data, nil),
+			want:  strings.EncodingArmor,
+		},
+		{
+			name:  "detects Bech32m",
+			input: bech,
+			want:  strings.EncodingBech32m,
+		},
+		{
+			name:  "detects Base58Check",
+			input: strings.Base58CheckEncode(0x53, data),
+			want:  strings.EncodingBase58Check,
+		},
+		{
+			name:  "detects hex",
+			input: hex.EncodeToString(data),
+			want:  strings.EncodingHex,
+		},
+		{
+			name:  "reports a corrupted Base58Check string as unknown",
+			input: strings.Base58CheckEncode(0x53, data) + "2",
+			want:  strings.EncodingUnknown,
+		},
+		{
+			name:  "reports free text as unknown",
+			input: "hello, world",
+			want:  strings.EncodingUnknown,
+		},
+		{
+			name:  "reports empty input as unknown",
+			input: "   ",
+			want:  strings.EncodingUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Detect(tt.input); got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
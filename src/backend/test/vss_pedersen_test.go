@@ -0,0 +1,184 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+func TestVSS_SplitPedersen(t *testing.T) {
+	secret, err := vss.NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v, want nil", err)
+	}
+
+	shares, commitments, err := vss.SplitPedersen(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitPedersen() error = %v, want nil", err)
+	}
+
+	if commitments.Mode != vss.PedersenMode {
+		t.Errorf("SplitPedersen() mode = %v, want %v", commitments.Mode, vss.PedersenMode)
+	}
+	if len(commitments.Points) != 3 {
+		t.Errorf("SplitPedersen() returned %d commitments, want 3", len(commitments.Points))
+	}
+
+	for _, share := range shares {
+		if len(share.Blinding) != vss.ScalarLen {
+			t.Errorf("SplitPedersen() share %d blinding length = %d, want %d", share.Index, len(share.Blinding), vss.ScalarLen)
+		}
+		if err := vss.VerifyShare(share, commitments); err != nil {
+			t.Errorf("VerifyShare() error = %v, want nil", err)
+		}
+	}
+
+	got, err := vss.Combine([]vss.Share{shares[3], shares[0], shares[1]})
+	if err != nil {
+		t.Fatalf("Combine() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Errorf("Combine() = %x, want %x", got, secret)
+	}
+}
+
+func TestVSS_SplitPedersen_HidesSecret(t *testing.T) {
+	secret, _ := vss.NewSecret()
+
+	_, first, err := vss.SplitPedersen(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("SplitPedersen() error = %v, want nil", err)
+	}
+	_, second, err := vss.SplitPedersen(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("SplitPedersen() error = %v, want nil", err)
+	}
+	_, feldman, err := vss.SplitFeldman(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("SplitFeldman() error = %v, want nil", err)
+	}
+
+	// Feldman always publishes secret*G, Pedersen blinds the constant term on every dealing
+	if bytes.Equal(first.Points[0], second.Points[0]) {
+		t.Errorf("SplitPedersen() committed to the same secret identically twice")
+	}
+	if bytes.Equal(first.Points[0], feldman.Points[0]) {
+		t.Errorf("SplitPedersen() commitment to the secret equals secret*G")
+	}
+}
+
+func TestVSS_VerifyShare_Pedersen(t *testing.T) {
+	secret, _ := vss.NewSecret()
+	shares, commitments, err := vss.SplitPedersen(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitPedersen() error = %v, want nil", err)
+	}
+
+	tamperedBlinding := append([]byte{}, shares[0].Blinding...)
+	tamperedBlinding[0] ^= 0x01
+	tamperedValue := append([]byte{}, shares[0].Value...)
+	tamperedValue[0] ^= 0x01
+
+	tests := []struct {
+		name      string
+		share     vss.Share
+		wantError bool
+		wantIs    error
+	}{
+		{
+			name:      "accepts honest share",
+			share:     shares[0],
+			wantError: false,
+		},
+		{
+			name:      "rejects share without blinding value",
+			share:     vss.Share{Index: shares[0].Index, Value: shares[0].Value},
+			wantError: true,
+			wantIs:    vss.ErrMissingBlinding,
+		},
+		{
+			name:      "rejects tampered blinding value",
+			share:     vss.Share{Index: shares[0].Index, Value: shares[0].Value, Blinding: tamperedBlinding},
+			wantError: true,
+		},
+		{
+			name:      "rejects tampered share value",
+			share:     vss.Share{Index: shares[0].Index, Value: tamperedValue, Blinding: shares[0].Blinding},
+			wantError: true,
+		},
+		{
+			name:      "rejects blinding value of another share",
+			share:     vss.Share{Index: shares[0].Index, Value: shares[0].Value, Blinding: shares[1].Blinding},
+			wantError: true,
+		},
+		{
+			name:      "rejects malformed blinding value",
+			share:     vss.Share{Index: shares[0].Index, Value: shares[0].Value, Blinding: []byte{1}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vss.VerifyShare(tt.share, commitments)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("VerifyShare() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("VerifyShare() error = %v, want %v", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestVSS_PedersenDealing(t *testing.T) {
+	secret, _ := vss.NewSecret()
+
+	dealing, err := vss.NewPedersenDealing(secret, 3)
+	if err != nil {
+		t.Fatalf("NewPedersenDealing() error = %v, want nil", err)
+	}
+
+	pedersen, feldman := dealing.PedersenCommitments(), dealing.FeldmanCommitments()
+	for _, commitments := range []*vss.Commitments{pedersen, feldman} {
+		if err := commitments.Validate(); err != nil {
+			t.Errorf("Validate() %v commitments error = %v, want nil", commitments.Mode, err)
+		}
+	}
+
+	shares := []vss.Share{dealing.Share(7), dealing.Share(2), dealing.Share(40)}
+	for _, share := range shares {
+		if err := vss.VerifyShare(share, pedersen); err != nil {
+			t.Errorf("VerifyShare() against Pedersen commitments error = %v, want nil", err)
+		}
+		if err := vss.VerifyShare(share, feldman); err != nil {
+			t.Errorf("VerifyShare() against Feldman commitments error = %v, want nil", err)
+		}
+	}
+
+	got, err := vss.Combine(shares)
+	if err != nil {
+		t.Fatalf("Combine() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Errorf("Combine() = %x, want %x", got, secret)
+	}
+
+	if _, err := vss.NewPedersenDealing(secret, 1); err == nil {
+		t.Error("NewPedersenDealing() with threshold 1 error = nil, want an error")
+	}
+	if _, err := vss.NewPedersenDealing(make([]byte, 5), 2); err == nil {
+		t.Error("NewPedersenDealing() with a malformed secret error = nil, want an error")
+	}
+
+	feldman.Mode = "unknown"
+	if err := feldman.Validate(); err == nil {
+		t.Error("Validate() with an unknown mode error = nil, want an error")
+	}
+	pedersen.Points[1] = bytes.Repeat([]byte{0xff}, vss.ScalarLen)
+	if err := pedersen.Validate(); err == nil {
+		t.Error("Validate() with an invalid point error = nil, want an error")
+	}
+}
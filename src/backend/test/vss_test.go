@@ -0,0 +1,247 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/culbec/CRYPTO-sss/src/backend/pkg/security/vss"
+)
+
+func TestVSS_SplitFeldman(t *testing.T) {
+	secret, err := vss.NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		n         int
+		k         int
+		wantError bool
+	}{
+		{
+			name:      "deals 3-of-5 shares",
+			secret:    secret,
+			n:         5,
+			k:         3,
+			wantError: false,
+		},
+		{
+			name:      "deals n-of-n shares",
+			secret:    secret,
+			n:         4,
+			k:         4,
+			wantError: false,
+		},
+		{
+			name:      "rejects non-canonical secret",
+			secret:    bytes.Repeat([]byte{0xff}, vss.ScalarLen),
+			n:         5,
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects short secret",
+			secret:    []byte{1, 2, 3},
+			n:         5,
+			k:         3,
+			wantError: true,
+		},
+		{
+			name:      "rejects threshold below 2",
+			secret:    secret,
+			n:         5,
+			k:         1,
+			wantError: true,
+		},
+		{
+			name:      "rejects n less than k",
+			secret:    secret,
+			n:         2,
+			k:         3,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, commitments, err := vss.SplitFeldman(tt.secret, tt.n, tt.k)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("SplitFeldman() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Errorf("SplitFeldman() returned %d shares, want %d", len(shares), tt.n)
+			}
+			if len(commitments.Points) != tt.k {
+				t.Errorf("SplitFeldman() returned %d commitments, want %d", len(commitments.Points), tt.k)
+			}
+
+			for _, share := range shares {
+				if err := vss.VerifyShare(share, commitments); err != nil {
+					t.Errorf("VerifyShare() error = %v, want nil", err)
+				}
+			}
+
+			got, err := vss.Combine(shares[len(shares)-tt.k:])
+			if err != nil {
+				t.Fatalf("Combine() error = %v, want nil", err)
+			}
+			if !bytes.Equal(got, tt.secret) {
+				t.Errorf("Combine() = %x, want %x", got, tt.secret)
+			}
+		})
+	}
+}
+
+func TestVSS_VerifyShare_Feldman(t *testing.T) {
+	secret, _ := vss.NewSecret()
+	shares, commitments, err := vss.SplitFeldman(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitFeldman() error = %v, want nil", err)
+	}
+	_, otherCommitments, err := vss.SplitFeldman(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitFeldman() error = %v, want nil", err)
+	}
+
+	tamperedValue := append([]byte{}, shares[0].Value...)
+	tamperedValue[0] ^= 0x01
+
+	tests := []struct {
+		name        string
+		share       vss.Share
+		commitments *vss.Commitments
+		wantError   bool
+	}{
+		{
+			name:        "accepts honest share",
+			share:       shares[0],
+			commitments: commitments,
+			wantError:   false,
+		},
+		{
+			name:        "rejects tampered share value",
+			share:       vss.Share{Index: shares[0].Index, Value: tamperedValue},
+			commitments: commitments,
+			wantError:   true,
+		},
+		{
+			name:        "rejects share presented under another index",
+			share:       vss.Share{Index: shares[1].Index, Value: shares[0].Value},
+			commitments: commitments,
+			wantError:   true,
+		},
+		{
+			name:        "rejects share against commitments of another dealing",
+			share:       shares[0],
+			commitments: otherCommitments,
+			wantError:   true,
+		},
+		{
+			name:        "rejects index 0",
+			share:       vss.Share{Index: 0, Value: shares[0].Value},
+			commitments: commitments,
+			wantError:   true,
+		},
+		{
+			name:        "rejects missing commitments",
+			share:       shares[0],
+			commitments: nil,
+			wantError:   true,
+		},
+		{
+			name:        "rejects malformed commitment point",
+			share:       shares[0],
+			commitments: &vss.Commitments{Mode: vss.FeldmanMode, Group: vss.GroupName, Points: [][]byte{{1, 2, 3}}},
+			wantError:   true,
+		},
+		{
+			name:        "rejects unknown group",
+			share:       shares[0],
+			commitments: &vss.Commitments{Mode: vss.FeldmanMode, Group: "p256", Points: commitments.Points},
+			wantError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vss.VerifyShare(tt.share, tt.commitments)
+			if (err != nil) != tt.wantError {
+				t.Errorf("VerifyShare() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestVSS_Commitments_Serialization(t *testing.T) {
+	secret, _ := vss.NewSecret()
+	shares, commitments, err := vss.SplitFeldman(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("SplitFeldman() error = %v, want nil", err)
+	}
+
+	jsonData, err := json.Marshal(commitments)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v, want nil", err)
+	}
+	var fromJSON vss.Commitments
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want nil", err)
+	}
+
+	bsonData, err := bson.Marshal(commitments)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v, want nil", err)
+	}
+	var fromBSON vss.Commitments
+	if err := bson.Unmarshal(bsonData, &fromBSON); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v, want nil", err)
+	}
+
+	for _, c := range []*vss.Commitments{&fromJSON, &fromBSON} {
+		if err := vss.VerifyShare(shares[2], c); err != nil {
+			t.Errorf("VerifyShare() error = %v, want nil after round trip", err)
+		}
+	}
+}
+
+func TestVSS_Combine_InvalidShares(t *testing.T) {
+	secret, _ := vss.NewSecret()
+	shares, _, err := vss.SplitFeldman(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("SplitFeldman() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		shares []vss.Share
+	}{
+		{
+			name:   "rejects a single share",
+			shares: shares[:1],
+		},
+		{
+			name:   "rejects duplicate indices",
+			shares: []vss.Share{shares[0], shares[0]},
+		},
+		{
+			name:   "rejects non-canonical value",
+			shares: []vss.Share{shares[0], {Index: 2, Value: bytes.Repeat([]byte{0xff}, vss.ScalarLen)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := vss.Combine(tt.shares); err == nil {
+				t.Errorf("Combine() error = nil, want error")
+			}
+		})
+	}
+}